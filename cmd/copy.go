@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/spf13/cobra"
+)
+
+// copyCmd copies keys to another connection or database
+var copyCmd = &cobra.Command{
+	Use:     "copy [key...]",
+	Aliases: []string{"migrate"},
+	Short:   "Copy keys to another connection or database.",
+	Long: `Copy the given keys, or the keys matching --match, from the configured
+connection to --target with DUMP and RESTORE. Values are copied verbatim and
+TTLs are kept. The target is a database number of the connection, the name of
+a profile of the config file, or name/N for database N of that profile; it can
+be a standalone, sentinel or cluster deployment.
+
+Keys that already exist in the target are skipped, replaced, or copied under
+their name followed by --suffix, depending on --conflict. Keys are read and
+written in pipelines of --batch-size keys.`,
+	Example: `  redis-viewer copy --target 1 --match 'user:*'
+  redis-viewer migrate --target staging --match 'session:*' --conflict replace
+  redis-viewer copy --target staging/2 --conflict rename --suffix :old config:flags`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		target, _ := cmd.Flags().GetString("target")
+		match, _ := cmd.Flags().GetString("match")
+		conflict, _ := cmd.Flags().GetString("conflict")
+		suffix, _ := cmd.Flags().GetString("suffix")
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		policy, err := redis.ParseCopyConflict(conflict)
+		if err != nil {
+			return err
+		}
+		if policy == redis.CopyRename && suffix == "" {
+			return errors.New("--conflict rename needs a --suffix")
+		}
+		if len(args) > 0 && match != "" {
+			return errors.New("give either keys or --match, not both")
+		}
+		if cfg.ReadOnly && !dryRun {
+			return errors.New("copy is disabled in read-only mode")
+		}
+
+		srcOpts := redis.NewOptions(cfg)
+		dstOpts, err := redis.TargetOptions(cfg, srcOpts, target)
+		if err != nil {
+			return err
+		}
+		if dstOpts.RDBFile != "" && !dryRun {
+			return errors.New("cannot copy into an RDB file")
+		}
+		if srcOpts.SameDatabase(dstOpts) && policy != redis.CopyRename {
+			return errors.New("the target is the source database, only --conflict rename can copy into it")
+		}
+
+		src, err := redis.Connect(srcOpts)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := redis.Connect(dstOpts)
+		if err != nil {
+			return fmt.Errorf("target %s: %w", target, err)
+		}
+		defer dst.Close()
+
+		opts := redis.CopyOptions{
+			Conflict:     policy,
+			Suffix:       suffix,
+			BatchSize:    batchSize,
+			Pause:        time.Duration(cfg.AnalysisPause) * time.Millisecond,
+			DryRun:       dryRun,
+			SameDatabase: srcOpts.SameDatabase(dstOpts),
+		}
+		if opts.BatchSize <= 0 {
+			opts.BatchSize = constant.DefaultImportBatchSize
+		}
+
+		ctx := context.Background()
+		start := time.Now()
+		var stats redis.CopyStats
+		if len(args) > 0 {
+			stats, err = redis.CopyKeys(ctx, src, dst, args, opts, nil)
+		} else {
+			stats, err = redis.CopyMatching(ctx, src, dst, match, opts, nil)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, f := range stats.Failures {
+			logger.Warn("copy failed", "key", f.Key, "err", f.Err)
+		}
+		logger.Info("copy complete", "target", target, "created", stats.Created, "replaced", stats.Replaced,
+			"renamed", stats.Renamed, "skipped", stats.Skipped, "failed", stats.Failed(), "dry_run", dryRun,
+			"duration", time.Since(start))
+
+		prefix := ""
+		if dryRun {
+			prefix = "Dry run, nothing written. "
+		}
+		fmt.Fprintf(os.Stderr, "%sCreated %d (%d renamed), replaced %d, skipped %d, failed %d\n",
+			prefix, stats.Created, stats.Renamed, stats.Replaced, stats.Skipped, stats.Failed())
+		for i, f := range stats.Failures {
+			if i == maxReportedFailures {
+				fmt.Fprintf(os.Stderr, "  ... and %d more\n", stats.Failed()-maxReportedFailures)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "  %s: %v\n", f.Key, f.Err)
+		}
+		if stats.Failed() > 0 {
+			// The failures are listed above, the usage would only hide them
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d keys failed to copy", stats.Failed())
+		}
+		return nil
+	},
+}
+
+func init() {
+	copyCmd.Flags().String("target", "", "Connection to copy to: a database number, a profile or profile/N")
+	copyCmd.Flags().String("match", "", "Copy the keys matching this SCAN pattern (every key when no keys are given)")
+	copyCmd.Flags().String("conflict", "skip", "Existing keys in the target: skip, replace or rename")
+	copyCmd.Flags().String("suffix", constant.DefaultCopySuffix, "Suffix of the copies of existing keys with --conflict rename")
+	copyCmd.Flags().Int("batch-size", constant.DefaultImportBatchSize, "Keys copied per pipeline")
+	copyCmd.Flags().Bool("dry-run", false, "Report what would be written without writing")
+	_ = copyCmd.MarkFlagRequired("target")
+
+	rootCmd.AddCommand(copyCmd)
+}
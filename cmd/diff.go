@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/spf13/cobra"
+)
+
+// diffCmd compares the keyspace of the connection with another one
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the keys of two connections or databases.",
+	Long: `Compare the keys matching --match in the configured connection (A) with
+--target (B) and list the keys only in A (-), only in B (+) and the keys whose
+type, value or TTL differ (~). The target is a database number of the
+connection, the name of a profile of the config file, or name/N for database N
+of that profile. TTLs closer than --ttl-tolerance are considered equal.
+
+The command exits with an error when any key differs.`,
+	Example: `  redis-viewer diff --target 1 --match 'user:*'
+  redis-viewer diff --target staging/2 --ttl-tolerance 1m
+  redis-viewer diff --rdb dump.rdb --target prod`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		target, _ := cmd.Flags().GetString("target")
+		match, _ := cmd.Flags().GetString("match")
+		tolerance, _ := cmd.Flags().GetDuration("ttl-tolerance")
+
+		optsA := redis.NewOptions(cfg)
+		optsB, err := redis.TargetOptions(cfg, optsA, target)
+		if err != nil {
+			return err
+		}
+
+		a, err := redis.Connect(optsA)
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		b, err := redis.Connect(optsB)
+		if err != nil {
+			return fmt.Errorf("target %s: %w", target, err)
+		}
+		defer b.Close()
+
+		opts := redis.DiffOptions{
+			Match:        match,
+			BatchSize:    cfg.AnalysisBatchSize,
+			Pause:        time.Duration(cfg.AnalysisPause) * time.Millisecond,
+			TTLTolerance: tolerance,
+		}
+
+		start := time.Now()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tKEY\tDETAILS\t")
+		summary, err := redis.DiffKeyspaces(context.Background(), a, b, opts, func(d redis.KeyDiff) error {
+			_, err := fmt.Fprintf(w, "%s\t%s\t%s\t\n", d.Kind.Marker(), d.Key, d.Details())
+			return err
+		}, nil)
+		if err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		logger.Info("diff complete", "target", target, "compared", summary.Compared, "changed", summary.Changed,
+			"only_a", summary.OnlyA, "only_b", summary.OnlyB, "duration", time.Since(start))
+		fmt.Fprintf(os.Stderr, "%d keys in both: %d identical, %d changed; %d only in A, %d only in B\n",
+			summary.Compared, summary.Identical, summary.Changed, summary.OnlyA, summary.OnlyB)
+		if summary.Differences() > 0 {
+			// Like diff(1), differences are reported through the exit status
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d keys differ", summary.Differences())
+		}
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().String("target", "", "Connection to compare with: a database number, a profile or profile/N")
+	diffCmd.Flags().String("match", "", "Only compare keys matching this SCAN pattern")
+	diffCmd.Flags().Duration("ttl-tolerance", 5*time.Second, "Largest TTL gap still considered equal")
+	_ = diffCmd.MarkFlagRequired("target")
+
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/export"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd writes the keys matching a pattern to a file
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export keys with their type, TTL and value to a file.",
+	Long: `Scan the keys matching --match and write their key, type, TTL and value.
+
+Formats:
+  jsonl     one JSON record per line (default)
+  json      a single JSON array of records
+  csv       key, type, ttl_ms, field and value columns; strings and hashes only
+  dump      JSON lines holding the base64 DUMP payload of each key, binary
+            safe and restored exactly by RESTORE
+  commands  redis-cli commands recreating each key with its TTL, replayable
+            with redis-cli < file
+  resp      the same commands in the Redis protocol, for redis-cli --pipe
+
+Values are written as text in the jsonl, json and csv formats: use dump for
+binary data.`,
+	Example: `  redis-viewer export --match 'user:*' --format jsonl -o out.jsonl
+  redis-viewer export --match 'session:*' --format resp | redis-cli --pipe`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		match, _ := cmd.Flags().GetString("match")
+		formatName, _ := cmd.Flags().GetString("format")
+		output, _ := cmd.Flags().GetString("output")
+
+		format, err := export.ParseFormat(formatName)
+		if err != nil {
+			return err
+		}
+
+		rdb, err := redis.Connect(redis.NewOptions(cfg))
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+
+		var out io.Writer = os.Stdout
+		if output != "" && output != "-" {
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		buf := bufio.NewWriter(out)
+
+		w, err := export.NewWriter(buf, format)
+		if err != nil {
+			return err
+		}
+
+		opts := redis.ScanStatsOptions{
+			Match:     match,
+			BatchSize: cfg.AnalysisBatchSize,
+			Pause:     time.Duration(cfg.AnalysisPause) * time.Millisecond,
+		}
+		start := time.Now()
+		var written, skipped int
+		err = redis.ScanRecords(context.Background(), rdb, opts, format.Dump(), func(records []redis.KeyRecord) error {
+			for _, r := range records {
+				if err := w.Write(r); err != nil {
+					if errors.Is(err, export.ErrUnsupportedType) {
+						skipped++
+						continue
+					}
+					return err
+				}
+				written++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+
+		logger.Info("export complete", "match", match, "format", string(format), "keys", written, "skipped", skipped, "duration", time.Since(start))
+		fmt.Fprintf(os.Stderr, "Exported %d keys", written)
+		if skipped > 0 {
+			fmt.Fprintf(os.Stderr, ", skipped %d keys of types the %s format cannot hold", skipped, format)
+		}
+		fmt.Fprintln(os.Stderr)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().String("match", "", "Only export keys matching this SCAN pattern")
+	exportCmd.Flags().StringP("format", "f", string(export.FormatJSONL), "Output format: jsonl, json, csv, dump, commands or resp")
+	exportCmd.Flags().StringP("output", "o", "", "Output file (default stdout)")
+
+	rootCmd.AddCommand(exportCmd)
+}
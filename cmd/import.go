@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/export"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/spf13/cobra"
+)
+
+// maxReportedFailures is the number of failed keys listed after an import
+const maxReportedFailures = 10
+
+// importCmd recreates keys from an export file
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import keys from a file written by export.",
+	Long: `Read records in the jsonl, json or dump export formats from a file, or from
+stdin when the file is omitted or "-", and recreate the keys with their type
+and TTL. Records holding a DUMP payload are restored with RESTORE.
+
+Existing keys are reported as failures unless --replace or --skip-existing is
+given. Writes are sent in MULTI/EXEC pipelines of --batch-size keys.`,
+	Example: `  redis-viewer import out.jsonl --skip-existing
+  redis-viewer export --match 'user:*' --format dump | redis-viewer import --db 1 --replace`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		replace, _ := cmd.Flags().GetBool("replace")
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+		if replace && skipExisting {
+			return errors.New("--replace and --skip-existing are mutually exclusive")
+		}
+		if cfg.ReadOnly && !dryRun {
+			return errors.New("import is disabled in read-only mode")
+		}
+		if cfg.RDB != "" && !dryRun {
+			return errors.New("cannot import into an RDB file")
+		}
+		if batchSize <= 0 {
+			batchSize = constant.DefaultImportBatchSize
+		}
+		policy := redis.ConflictFail
+		switch {
+		case replace:
+			policy = redis.ConflictReplace
+		case skipExisting:
+			policy = redis.ConflictSkip
+		}
+
+		var in io.Reader = os.Stdin
+		if len(args) == 1 && args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+		reader, err := export.NewReader(in)
+		if err != nil {
+			return err
+		}
+
+		rdb, err := redis.Connect(redis.NewOptions(cfg))
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+
+		ctx := context.Background()
+		start := time.Now()
+		var stats redis.RestoreStats
+		batch := make([]redis.KeyRecord, 0, batchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			batchStats, err := redis.RestoreRecords(ctx, rdb, batch, policy, dryRun)
+			if err != nil {
+				return err
+			}
+			stats.Add(batchStats)
+			batch = batch[:0]
+			return nil
+		}
+
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if errors.Is(err, export.ErrInvalidRecord) {
+				stats.Failures = append(stats.Failures, redis.RestoreFailure{Key: record.Key, Err: err})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			batch = append(batch, record)
+			if len(batch) == batchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		for _, f := range stats.Failures {
+			logger.Warn("import failed", "key", f.Key, "err", f.Err)
+		}
+		logger.Info("import complete", "created", stats.Created, "replaced", stats.Replaced,
+			"skipped", stats.Skipped, "failed", stats.Failed(), "dry_run", dryRun, "duration", time.Since(start))
+
+		prefix := ""
+		if dryRun {
+			prefix = "Dry run, nothing written. "
+		}
+		fmt.Fprintf(os.Stderr, "%sCreated %d, replaced %d, skipped %d, failed %d\n",
+			prefix, stats.Created, stats.Replaced, stats.Skipped, stats.Failed())
+		for i, f := range stats.Failures {
+			if i == maxReportedFailures {
+				fmt.Fprintf(os.Stderr, "  ... and %d more\n", stats.Failed()-maxReportedFailures)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "  %s: %v\n", f.Key, f.Err)
+		}
+		if stats.Failed() > 0 {
+			// The failures are listed above, the usage would only hide them
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d keys failed to import", stats.Failed())
+		}
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().Bool("replace", false, "Overwrite existing keys")
+	importCmd.Flags().Bool("skip-existing", false, "Leave existing keys untouched")
+	importCmd.Flags().Bool("dry-run", false, "Report what would be written without writing")
+	importCmd.Flags().Int("batch-size", constant.DefaultImportBatchSize, "Keys written per pipeline")
+
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/spf13/cobra"
+)
+
+// prefixesCmd prints the memory and key count breakdown by key prefix
+var prefixesCmd = &cobra.Command{
+	Use:   "prefixes",
+	Short: "Break down memory usage and key counts by key prefix.",
+	Long: `Scan the keyspace, run MEMORY USAGE on every key and aggregate key counts,
+memory and TTL coverage per key prefix. Prefixes are split on --prefix-delimiter
+up to --prefix-depth levels. Only --analysis-sample-size keys are scanned unless
+it is 0 or --full is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		match, _ := cmd.Flags().GetString("match")
+		full, _ := cmd.Flags().GetBool("full")
+		top, _ := cmd.Flags().GetInt("top")
+		sortBy, _ := cmd.Flags().GetString("sort")
+
+		order, err := parsePrefixSort(sortBy)
+		if err != nil {
+			return err
+		}
+
+		rdb, err := redis.Connect(redis.NewOptions(cfg))
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+
+		opts := redis.ScanStatsOptions{
+			Match:     match,
+			BatchSize: cfg.AnalysisBatchSize,
+			Pause:     time.Duration(cfg.AnalysisPause) * time.Millisecond,
+		}
+		if !full {
+			opts.Limit = cfg.AnalysisSampleSize
+		}
+
+		report, err := redis.AnalysePrefixes(context.Background(), rdb, opts, cfg.PrefixDelimiter, cfg.PrefixDepth, nil)
+		if err != nil {
+			return err
+		}
+
+		scope := "all keys"
+		if opts.Limit > 0 && report.Root.Keys >= int64(opts.Limit) {
+			scope = fmt.Sprintf("a sample of %d keys", report.Root.Keys)
+		}
+		fmt.Fprintf(os.Stderr, "Analysed %s, %s in total\n", scope, redis.FormatBytes(report.Root.Bytes))
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PREFIX\tKEYS\tKEYS %\tMEMORY\tMEMORY %\tTTL %\t")
+		printPrefixes(w, report.Root, report.Root, order, top)
+		return w.Flush()
+	},
+}
+
+// parsePrefixSort maps the --sort flag to a prefix order
+func parsePrefixSort(s string) (redis.PrefixSort, error) {
+	switch s {
+	case "memory":
+		return redis.PrefixSortBytes, nil
+	case "keys":
+		return redis.PrefixSortKeys, nil
+	case "ttl":
+		return redis.PrefixSortTTL, nil
+	case "name":
+		return redis.PrefixSortName, nil
+	}
+	return 0, fmt.Errorf("invalid sort %q: use memory, keys, ttl or name", s)
+}
+
+// printPrefixes writes the sub-prefixes of node depth-first, at most top per
+// level, followed by the keys directly under node
+func printPrefixes(w *tabwriter.Writer, root, node *redis.PrefixStats, order redis.PrefixSort, top int) {
+	children := node.Children()
+	if len(children) == 0 {
+		return
+	}
+	redis.SortPrefixes(children, order)
+
+	indent := strings.Repeat("  ", node.Depth)
+	for i, child := range children {
+		if top > 0 && i == top {
+			fmt.Fprintf(w, "%s(%d more)\t\t\t\t\t\t\n", indent, len(children)-top)
+			break
+		}
+		printPrefixRow(w, root, indent+child.Prefix, *child)
+		printPrefixes(w, root, child, order, top)
+	}
+
+	if other := node.Other(); other.Keys > 0 {
+		printPrefixRow(w, root, indent+"(other)", other)
+	}
+}
+
+func printPrefixRow(w *tabwriter.Writer, root *redis.PrefixStats, label string, p redis.PrefixStats) {
+	fmt.Fprintf(w, "%s\t%d\t%.1f\t%s\t%.1f\t%.1f\t\n",
+		label,
+		p.Keys,
+		percentOf(p.Keys, root.Keys),
+		redis.FormatBytes(p.Bytes),
+		percentOf(p.Bytes, root.Bytes),
+		p.TTLCoverage(),
+	)
+}
+
+func percentOf(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total) * 100
+}
+
+func init() {
+	prefixesCmd.Flags().String("match", "", "Only analyse keys matching this SCAN pattern")
+	prefixesCmd.Flags().Bool("full", false, "Scan every key instead of a sample")
+	prefixesCmd.Flags().Int("top", 20, "Prefixes listed per level (0 lists all)")
+	prefixesCmd.Flags().String("sort", "memory", "Sort prefixes by memory, keys, ttl or name")
+
+	rootCmd.AddCommand(prefixesCmd)
+}
@@ -24,24 +24,49 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hawkins/redis-viewer/internal/config"
 	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/logger"
 	"github.com/hawkins/redis-viewer/internal/ui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-var cfgFile string
+var (
+	cfgFile   string
+	logCloser io.Closer
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "redis-viewer",
 	Short: "view redis data in terminal.",
 	Long:  `Redis Viewer is a tool to view redis data in terminal.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if cfg.LogFile == "" {
+			return nil
+		}
+
+		closer, err := logger.Init(cfg.LogFile, cfg.LogLevel)
+		if err != nil {
+			return err
+		}
+		logCloser = closer
+		logger.Info("redis-viewer starting", "command", cmd.Name(), "addrs", strings.Join(cfg.Addrs, ","), "db", cfg.DB)
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if logCloser != nil {
+			logCloser.Close()
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg := config.Get()
 
@@ -52,8 +77,10 @@ var rootCmd = &cobra.Command{
 
 		p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
 		if err := p.Start(); err != nil {
+			logger.Error("program exited with error", "err", err)
 			log.Fatal("start failed: ", err)
 		}
+		logger.Info("redis-viewer exiting")
 	},
 }
 
@@ -83,8 +110,46 @@ func init() {
 		StringP("password", "p", "", "Redis password")
 	rootCmd.PersistentFlags().
 		StringP("master-name", "m", "", "Redis Sentinel master name")
+	rootCmd.PersistentFlags().
+		String("sentinel-username", "", "Redis Sentinel username, when it differs from the data nodes")
+	rootCmd.PersistentFlags().
+		String("sentinel-password", "", "Redis Sentinel password, when it differs from the data nodes")
+	rootCmd.PersistentFlags().
+		Bool("cluster-read-only", false, "Let cluster replicas serve read-only commands")
+	rootCmd.PersistentFlags().
+		Bool("route-by-latency", false, "Send read-only commands to the closest master or replica (cluster or sentinel)")
+	rootCmd.PersistentFlags().
+		Bool("route-randomly", false, "Send read-only commands to a random master or replica (cluster or sentinel)")
+	rootCmd.PersistentFlags().
+		Bool("replica-only", false, "Send every command to a sentinel replica (implies --read-only)")
+	rootCmd.PersistentFlags().
+		String("rdb", "", "Browse an RDB file instead of connecting to a server (read-only)")
 	rootCmd.PersistentFlags().
 		Int64P("limit", "l", constant.DefaultCount, "Scan count per page")
+	rootCmd.PersistentFlags().
+		Bool("read-only", false, "Disable every action that modifies data or server state")
+	rootCmd.PersistentFlags().
+		Bool("list-show-size", false, "Show the element count and memory usage of loaded keys in the key list")
+	rootCmd.PersistentFlags().
+		Int("refresh-interval", constant.DefaultRefreshInterval, "Auto-refresh interval in seconds")
+	rootCmd.PersistentFlags().
+		Int("stats-window", constant.DefaultStatsWindow, "Number of one-second samples charted on the stats page")
+	rootCmd.PersistentFlags().
+		Int("analysis-batch-size", constant.DefaultAnalysisBatchSize, "Keys per pipeline in keyspace analysis reports")
+	rootCmd.PersistentFlags().
+		Int("analysis-pause-ms", constant.DefaultAnalysisPause, "Pause in milliseconds between the SCAN pages of keyspace analyses")
+	rootCmd.PersistentFlags().
+		Int("analysis-sample-size", constant.DefaultAnalysisSampleSize, "Keys scanned by sampled analysis reports (0 scans every key)")
+	rootCmd.PersistentFlags().
+		String("prefix-delimiter", constant.DefaultPrefixDelimiter, "Delimiter separating key prefix segments")
+	rootCmd.PersistentFlags().
+		Int("prefix-depth", constant.DefaultPrefixDepth, "Number of prefix levels in the prefix breakdown")
+
+	// Logging flags
+	rootCmd.PersistentFlags().
+		String("log-file", "", "Write logs to this file (logging is disabled when empty)")
+	rootCmd.PersistentFlags().
+		String("log-level", "info", "Log level: debug, info, warn or error")
 
 	// Bind flags to viper
 	viper.BindPFlag("addrs", rootCmd.PersistentFlags().Lookup("addrs"))
@@ -92,7 +157,27 @@ func init() {
 	viper.BindPFlag("username", rootCmd.PersistentFlags().Lookup("username"))
 	viper.BindPFlag("password", rootCmd.PersistentFlags().Lookup("password"))
 	viper.BindPFlag("master_name", rootCmd.PersistentFlags().Lookup("master-name"))
+	viper.BindPFlag("sentinel.username", rootCmd.PersistentFlags().Lookup("sentinel-username"))
+	viper.BindPFlag("sentinel.password", rootCmd.PersistentFlags().Lookup("sentinel-password"))
+	viper.BindPFlag("cluster.read_only", rootCmd.PersistentFlags().Lookup("cluster-read-only"))
+	viper.BindPFlag("cluster.route_by_latency", rootCmd.PersistentFlags().Lookup("route-by-latency"))
+	viper.BindPFlag("cluster.route_randomly", rootCmd.PersistentFlags().Lookup("route-randomly"))
+	viper.BindPFlag("sentinel.route_by_latency", rootCmd.PersistentFlags().Lookup("route-by-latency"))
+	viper.BindPFlag("sentinel.route_randomly", rootCmd.PersistentFlags().Lookup("route-randomly"))
+	viper.BindPFlag("sentinel.replica_only", rootCmd.PersistentFlags().Lookup("replica-only"))
+	viper.BindPFlag("rdb", rootCmd.PersistentFlags().Lookup("rdb"))
 	viper.BindPFlag("limit", rootCmd.PersistentFlags().Lookup("limit"))
+	viper.BindPFlag("read_only", rootCmd.PersistentFlags().Lookup("read-only"))
+	viper.BindPFlag("list_show_size", rootCmd.PersistentFlags().Lookup("list-show-size"))
+	viper.BindPFlag("refresh_interval", rootCmd.PersistentFlags().Lookup("refresh-interval"))
+	viper.BindPFlag("stats_window", rootCmd.PersistentFlags().Lookup("stats-window"))
+	viper.BindPFlag("analysis_batch_size", rootCmd.PersistentFlags().Lookup("analysis-batch-size"))
+	viper.BindPFlag("analysis_pause_ms", rootCmd.PersistentFlags().Lookup("analysis-pause-ms"))
+	viper.BindPFlag("analysis_sample_size", rootCmd.PersistentFlags().Lookup("analysis-sample-size"))
+	viper.BindPFlag("prefix_delimiter", rootCmd.PersistentFlags().Lookup("prefix-delimiter"))
+	viper.BindPFlag("prefix_depth", rootCmd.PersistentFlags().Lookup("prefix-depth"))
+	viper.BindPFlag("log_file", rootCmd.PersistentFlags().Lookup("log-file"))
+	viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// snapshotCmd groups the snapshot subcommands
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save keys to a local snapshot and compare live data with it.",
+	Long: `Save the keys matching a pattern, with their type, value and TTL, to a local
+file, then list the keys added, removed and modified since. Snapshot files are
+JSON Lines: a header line followed by records in the jsonl export format.
+
+The same comparison, with value diffs, is available in the UI with the Z key.`,
+}
+
+// snapshotSaveCmd writes a snapshot file
+var snapshotSaveCmd = &cobra.Command{
+	Use:     "save <file>",
+	Short:   "Save the keys matching --match to a new snapshot file.",
+	Example: `  redis-viewer snapshot save before.snapshot.jsonl --match 'job:42:*'`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		match, _ := cmd.Flags().GetString("match")
+
+		rdb, err := redis.Connect(redis.NewOptions(cfg))
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+
+		opts := redis.ScanStatsOptions{
+			Match:     match,
+			BatchSize: cfg.AnalysisBatchSize,
+			Pause:     time.Duration(cfg.AnalysisPause) * time.Millisecond,
+		}
+		header := snapshot.Header{Created: time.Now(), DB: cfg.DB, Addrs: cfg.Addrs}
+
+		start := time.Now()
+		saved, err := snapshot.Save(context.Background(), rdb, args[0], header, opts, nil)
+		if err != nil {
+			return err
+		}
+		logger.Info("snapshot saved", "path", args[0], "match", match, "keys", saved, "duration", time.Since(start))
+		fmt.Fprintf(os.Stderr, "Saved %d keys to %s\n", saved, args[0])
+		return nil
+	},
+}
+
+// snapshotDiffCmd compares live data with a snapshot file
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff <file>",
+	Short: "List the keys added, removed and modified since a snapshot.",
+	Long: `Scan the live keys of the snapshot pattern and compare them with the snapshot:
+keys removed since (-), added (+) and keys whose type, value or TTL changed (~).
+TTLs are compared as they would be now, and gaps below --ttl-tolerance are
+ignored. The command exits with an error when any key changed.`,
+	Example: `  redis-viewer snapshot diff before.snapshot.jsonl`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		tolerance, _ := cmd.Flags().GetDuration("ttl-tolerance")
+
+		snap, err := snapshot.Load(args[0])
+		if err != nil {
+			return err
+		}
+		if snap.DB != cfg.DB {
+			fmt.Fprintf(os.Stderr, "Warning: the snapshot was taken from DB %d, comparing with DB %d\n", snap.DB, cfg.DB)
+		}
+
+		rdb, err := redis.Connect(redis.NewOptions(cfg))
+		if err != nil {
+			return err
+		}
+		defer rdb.Close()
+
+		opts := redis.DiffOptions{
+			BatchSize:    cfg.AnalysisBatchSize,
+			Pause:        time.Duration(cfg.AnalysisPause) * time.Millisecond,
+			TTLTolerance: tolerance,
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "STATUS\tKEY\tDETAILS\t")
+		summary, err := snap.Diff(context.Background(), rdb, opts, func(d redis.KeyDiff) error {
+			_, err := fmt.Fprintf(w, "%s\t%s\t%s\t\n", d.Kind.Marker(), d.Key, d.Details())
+			return err
+		}, nil)
+		if err != nil {
+			return err
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		logger.Info("snapshot diff complete", "path", args[0], "unchanged", summary.Identical,
+			"modified", summary.Changed, "removed", summary.OnlyA, "added", summary.OnlyB)
+		fmt.Fprintf(os.Stderr, "Since %s: %d added, %d removed, %d modified, %d unchanged\n",
+			snap.Created.Format(time.RFC3339), summary.OnlyB, summary.OnlyA, summary.Changed, summary.Identical)
+		if summary.Differences() > 0 {
+			// Like diff(1), changes are reported through the exit status
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d keys changed", summary.Differences())
+		}
+		return nil
+	},
+}
+
+func init() {
+	snapshotSaveCmd.Flags().String("match", "", "Only save keys matching this SCAN pattern")
+	snapshotDiffCmd.Flags().Duration("ttl-tolerance", 5*time.Second, "Largest TTL gap still considered equal")
+
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotDiffCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -11,6 +11,75 @@ type Config struct {
 	Password   string
 	MasterName string `mapstructure:"master_name"`
 	Limit      int64
+	ReadOnly   bool `mapstructure:"read_only"`
+	// ListShowSize adds the element count and memory usage of loaded keys
+	// to their description in the key list
+	ListShowSize bool `mapstructure:"list_show_size"`
+
+	// RDB browses an RDB file instead of connecting to a server
+	RDB string
+
+	// Cluster settings, used when several Addrs are given
+	Cluster ClusterConfig
+	// Sentinel settings, used when MasterName is set
+	Sentinel SentinelConfig
+
+	// Profiles are named connections that diff and copy can target
+	Profiles map[string]Profile
+
+	// RefreshInterval is the auto-refresh period in seconds
+	RefreshInterval int `mapstructure:"refresh_interval"`
+	// StatsWindow is the number of one-second samples charted on the stats page
+	StatsWindow int `mapstructure:"stats_window"`
+
+	// Keyspace analysis throttling: keys per pipeline and milliseconds between batches
+	AnalysisBatchSize int `mapstructure:"analysis_batch_size"`
+	AnalysisPause     int `mapstructure:"analysis_pause_ms"`
+	// AnalysisSampleSize caps sampled reports, 0 scans every key
+	AnalysisSampleSize int `mapstructure:"analysis_sample_size"`
+
+	// Key prefix breakdown: segment delimiter and number of levels
+	PrefixDelimiter string `mapstructure:"prefix_delimiter"`
+	PrefixDepth     int    `mapstructure:"prefix_depth"`
+
+	// LogFile enables logging to the given path, LogLevel filters it
+	LogFile  string `mapstructure:"log_file"`
+	LogLevel string `mapstructure:"log_level"`
+}
+
+// ClusterConfig holds the settings of cluster deployments
+type ClusterConfig struct {
+	// Read-only commands may be served by replicas (READONLY), by the
+	// closest node or by a random node of the slot
+	ReadOnly       bool `mapstructure:"read_only"`
+	RouteByLatency bool `mapstructure:"route_by_latency"`
+	RouteRandomly  bool `mapstructure:"route_randomly"`
+}
+
+// SentinelConfig holds the settings of sentinel deployments
+type SentinelConfig struct {
+	// Credentials of the sentinels themselves, when they differ from the data nodes
+	Username string
+	Password string
+
+	// ReplicaOnly sends every command to a replica, which implies read-only mode
+	ReplicaOnly bool `mapstructure:"replica_only"`
+	// Read-only commands may be served by the closest or a random node among
+	// the master and its replicas
+	RouteByLatency bool `mapstructure:"route_by_latency"`
+	RouteRandomly  bool `mapstructure:"route_randomly"`
+}
+
+// Profile is a named connection. Cluster and sentinel routing settings are
+// shared with the main connection.
+type Profile struct {
+	Addrs      []string
+	DB         int
+	Username   string
+	Password   string
+	MasterName string `mapstructure:"master_name"`
+	// RDB browses an RDB file instead of connecting to a server
+	RDB string
 }
 
 // Get retrieves configuration from Viper
@@ -3,12 +3,34 @@ package constant
 
 // default config
 const (
-	DefaultCount = 50
+	DefaultCount           = 50
+	DefaultRefreshInterval = 2  // seconds
+	DefaultStatsWindow     = 60 // samples
+	// keyspace analysis batches and the pause between them
+	DefaultAnalysisBatchSize = 100
+	DefaultAnalysisPause     = 10 // milliseconds
+	// keys scanned by sampled analysis reports
+	DefaultAnalysisSampleSize = 10000
+	// key prefix breakdown
+	DefaultPrefixDelimiter = ":"
+	DefaultPrefixDepth     = 3
+	// keys written per MULTI/EXEC pipeline by import
+	DefaultImportBatchSize = 100
+	// suffix of the copies of keys existing in the target of a copy
+	DefaultCopySuffix = ":copy"
 )
 
 // redis
 const (
 	MaxRetries = 3
+	// number of random keys sampled for TTL detail on the stats page
+	TTLSampleSize = 10
+	// number of SLOWLOG entries fetched per node
+	SlowLogCount = 128
+	// number of biggest keys reported per type
+	BigKeysTopN = 10
+	// number of differing keys listed by the diff screen
+	DiffMaxKeys = 10000
 	// cluster
 	MaxRedirects = 10
 )
@@ -0,0 +1,184 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
+
+// commandChunkSize is the number of elements written per RPUSH, SADD, ZADD
+// or HSET command, keeping lines of big collections reasonable
+const commandChunkSize = 100
+
+// commandsWriter writes redis-cli commands recreating each key: DEL for
+// collections, the type's write commands, then PEXPIRE for keys with a TTL.
+// Arguments are quoted as redis-cli parses them, so binary values survive.
+type commandsWriter struct {
+	w *bufio.Writer
+}
+
+func (w *commandsWriter) Write(r redis.KeyRecord) error {
+	cmds, err := recordCommands(r)
+	if err != nil {
+		return err
+	}
+	for _, args := range cmds {
+		for i, arg := range args {
+			if i > 0 {
+				w.w.WriteByte(' ')
+			}
+			w.w.WriteString(quoteArg(arg))
+		}
+		w.w.WriteByte('\n')
+	}
+	return nil
+}
+
+func (w *commandsWriter) Close() error {
+	return w.w.Flush()
+}
+
+// respWriter writes the commands of commandsWriter as RESP arrays of bulk
+// strings, the input redis-cli --pipe expects
+type respWriter struct {
+	w *bufio.Writer
+}
+
+func (w *respWriter) Write(r redis.KeyRecord) error {
+	cmds, err := recordCommands(r)
+	if err != nil {
+		return err
+	}
+	for _, args := range cmds {
+		fmt.Fprintf(w.w, "*%d\r\n", len(args))
+		for _, arg := range args {
+			fmt.Fprintf(w.w, "$%d\r\n", len(arg))
+			w.w.WriteString(arg)
+			w.w.WriteString("\r\n")
+		}
+	}
+	return nil
+}
+
+func (w *respWriter) Close() error {
+	return w.w.Flush()
+}
+
+// recordCommands returns the commands recreating a record, as arguments
+func recordCommands(r redis.KeyRecord) ([][]string, error) {
+	var cmds [][]string
+	if r.Dump != nil {
+		ttl := r.TTLMillis
+		if ttl < 0 {
+			ttl = 0
+		}
+		return [][]string{{"RESTORE", r.Key, strconv.FormatInt(ttl, 10), string(r.Dump), "REPLACE"}}, nil
+	}
+
+	switch v := r.Value.(type) {
+	case string:
+		cmds = append(cmds, []string{"SET", r.Key, v})
+	case []string:
+		name := "RPUSH"
+		if r.Type == "set" {
+			name = "SADD"
+		}
+		cmds = append(cmds, []string{"DEL", r.Key})
+		cmds = appendChunked(cmds, []string{name, r.Key}, v, 1)
+	case map[string]string:
+		args := make([]string, 0, 2*len(v))
+		for _, field := range redis.SortedFields(v) {
+			args = append(args, field, v[field])
+		}
+		cmds = append(cmds, []string{"DEL", r.Key})
+		cmds = appendChunked(cmds, []string{"HSET", r.Key}, args, 2)
+	case []redis.ZMember:
+		args := make([]string, 0, 2*len(v))
+		for _, m := range v {
+			args = append(args, zaddScore(m.Score), m.Member)
+		}
+		cmds = append(cmds, []string{"DEL", r.Key})
+		cmds = appendChunked(cmds, []string{"ZADD", r.Key}, args, 2)
+	case []redis.StreamEntry:
+		cmds = append(cmds, []string{"DEL", r.Key})
+		for _, e := range v {
+			args := []string{"XADD", r.Key, e.ID}
+			for _, f := range e.Fields {
+				args = append(args, f.Name, f.Value)
+			}
+			cmds = append(cmds, args)
+		}
+	default:
+		return nil, fmt.Errorf("%s is a %s: %w", r.Key, r.Type, ErrUnsupportedType)
+	}
+
+	if r.TTLMillis > 0 {
+		cmds = append(cmds, []string{"PEXPIRE", r.Key, strconv.FormatInt(r.TTLMillis, 10)})
+	}
+	return cmds, nil
+}
+
+// appendChunked appends prefix+args commands of at most commandChunkSize
+// elements of width arguments each
+func appendChunked(cmds [][]string, prefix, args []string, width int) [][]string {
+	step := commandChunkSize * width
+	for start := 0; start < len(args); start += step {
+		end := start + step
+		if end > len(args) {
+			end = len(args)
+		}
+		cmd := append(append([]string{}, prefix...), args[start:end]...)
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// zaddScore formats a score the way ZADD accepts infinities
+func zaddScore(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	}
+	return formatScore(score)
+}
+
+// quoteArg quotes an argument for redis-cli when it is empty or holds
+// spaces, quotes, backslashes or non-printable bytes
+func quoteArg(s string) string {
+	plain := s != ""
+	for i := 0; i < len(s) && plain; i++ {
+		c := s[i]
+		plain = c > ' ' && c < 0x7f && c != '"' && c != '\'' && c != '\\'
+	}
+	if plain {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' || c == '"':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c == '\n':
+			b.WriteString(`\n`)
+		case c == '\r':
+			b.WriteString(`\r`)
+		case c == '\t':
+			b.WriteString(`\t`)
+		case c < ' ' || c >= 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
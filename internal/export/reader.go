@@ -0,0 +1,156 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	encodingjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
+
+// ErrInvalidRecord is returned for a well-formed record that cannot be
+// imported, such as one of an unknown type: reading can go on after it
+var ErrInvalidRecord = errors.New("invalid record")
+
+// Reader reads the records of an export file. Read returns io.EOF after the
+// last record.
+type Reader interface {
+	Read() (redis.KeyRecord, error)
+}
+
+// NewReader returns a reader of the jsonl, json or dump format, telling a
+// JSON array from JSON lines by the first character of the input
+func NewReader(r io.Reader) (Reader, error) {
+	br := bufio.NewReader(r)
+	dec := encodingjson.NewDecoder(br)
+
+	first, err := firstByte(br)
+	if err != nil {
+		return nil, err
+	}
+	if first != '[' {
+		return &jsonlReader{dec: dec}, nil
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return &jsonReader{dec: dec}, nil
+}
+
+// firstByte peeks at the first non-space byte of the input
+func firstByte(br *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		b, err := br.Peek(n)
+		if len(b) < n {
+			if err == io.EOF {
+				return 0, nil
+			}
+			return 0, err
+		}
+		if c := b[n-1]; c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c, nil
+		}
+	}
+}
+
+type jsonlReader struct {
+	dec  *encodingjson.Decoder
+	line int
+}
+
+func (r *jsonlReader) Read() (redis.KeyRecord, error) {
+	r.line++
+	var raw rawRecord
+	if err := r.dec.Decode(&raw); err != nil {
+		if err == io.EOF {
+			return redis.KeyRecord{}, err
+		}
+		return redis.KeyRecord{}, fmt.Errorf("record %d: %w", r.line, err)
+	}
+	return raw.record(r.line)
+}
+
+// jsonReader reads the elements of a JSON array one at a time
+type jsonReader struct {
+	dec   *encodingjson.Decoder
+	index int
+}
+
+func (r *jsonReader) Read() (redis.KeyRecord, error) {
+	if !r.dec.More() {
+		return redis.KeyRecord{}, io.EOF
+	}
+	r.index++
+	var raw rawRecord
+	if err := r.dec.Decode(&raw); err != nil {
+		return redis.KeyRecord{}, fmt.Errorf("record %d: %w", r.index, err)
+	}
+	return raw.record(r.index)
+}
+
+// ParseRecord decodes a single record of the jsonl format, numbered n in
+// error messages
+func ParseRecord(b []byte, n int) (redis.KeyRecord, error) {
+	var raw rawRecord
+	if err := encodingjson.Unmarshal(b, &raw); err != nil {
+		return redis.KeyRecord{}, fmt.Errorf("record %d: %w", n, err)
+	}
+	return raw.record(n)
+}
+
+// rawRecord is a record with its value left undecoded until the type is known
+type rawRecord struct {
+	Key       string                  `json:"key"`
+	Type      string                  `json:"type"`
+	TTLMillis *int64                  `json:"ttl_ms"`
+	Value     encodingjson.RawMessage `json:"value"`
+	Dump      []byte                  `json:"dump"`
+}
+
+// record decodes the value into the Go type of its Redis type
+func (raw rawRecord) record(n int) (redis.KeyRecord, error) {
+	r := redis.KeyRecord{Key: raw.Key, Type: raw.Type, TTLMillis: -1, Dump: raw.Dump}
+	if raw.TTLMillis != nil {
+		r.TTLMillis = *raw.TTLMillis
+	}
+	if raw.Key == "" {
+		return r, fmt.Errorf("record %d: missing key: %w", n, ErrInvalidRecord)
+	}
+	if raw.Dump != nil || len(raw.Value) == 0 || bytes.Equal(raw.Value, []byte("null")) {
+		return r, nil
+	}
+
+	var err error
+	switch raw.Type {
+	case "string":
+		var v string
+		err = encodingjson.Unmarshal(raw.Value, &v)
+		r.Value = v
+	case "list", "set":
+		var v []string
+		err = encodingjson.Unmarshal(raw.Value, &v)
+		r.Value = v
+	case "hash":
+		var v map[string]string
+		err = encodingjson.Unmarshal(raw.Value, &v)
+		r.Value = v
+	case "zset":
+		var v []redis.ZMember
+		err = encodingjson.Unmarshal(raw.Value, &v)
+		r.Value = v
+	case "stream":
+		var v []redis.StreamEntry
+		err = encodingjson.Unmarshal(raw.Value, &v)
+		r.Value = v
+	default:
+		return r, fmt.Errorf("record %d (%s): unsupported type %q: %w", n, raw.Key, raw.Type, ErrInvalidRecord)
+	}
+	if err != nil {
+		return r, fmt.Errorf("record %d (%s): %w: %s value: %v", n, raw.Key, ErrInvalidRecord, raw.Type, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bufio"
+	encodingjson "encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
+
+// rawWriter writes values as redis-cli --raw prints them: a string as is,
+// collections one element per line, hashes as field and value lines, sorted
+// sets as member and score lines
+type rawWriter struct {
+	w *bufio.Writer
+}
+
+func (w *rawWriter) Write(r redis.KeyRecord) error {
+	if err := checkValue(r); err != nil {
+		return err
+	}
+	for _, line := range rawLines(r.Value) {
+		w.w.WriteString(line)
+		w.w.WriteByte('\n')
+	}
+	return nil
+}
+
+func (w *rawWriter) Close() error {
+	return w.w.Flush()
+}
+
+func rawLines(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case map[string]string:
+		lines := make([]string, 0, 2*len(v))
+		for _, field := range redis.SortedFields(v) {
+			lines = append(lines, field, v[field])
+		}
+		return lines
+	case []redis.ZMember:
+		lines := make([]string, 0, 2*len(v))
+		for _, m := range v {
+			lines = append(lines, m.Member, formatScore(m.Score))
+		}
+		return lines
+	case []redis.StreamEntry:
+		var lines []string
+		for _, e := range v {
+			lines = append(lines, e.ID)
+			for _, f := range e.Fields {
+				lines = append(lines, f.Name, f.Value)
+			}
+		}
+		return lines
+	}
+	return nil
+}
+
+// prettyWriter writes the indented JSON of the value of a single key, or an
+// object of values by key name for several keys. String values holding JSON
+// are embedded as JSON. encoding/json is used as it reindents the embedded
+// JSON and the output of Marshalers.
+type prettyWriter struct {
+	w       io.Writer
+	records []redis.KeyRecord
+}
+
+func (w *prettyWriter) Write(r redis.KeyRecord) error {
+	if err := checkValue(r); err != nil {
+		return err
+	}
+	w.records = append(w.records, r)
+	return nil
+}
+
+func (w *prettyWriter) Close() error {
+	var doc interface{}
+	if len(w.records) == 1 {
+		doc = prettyValue(w.records[0].Value)
+	} else {
+		values := make(map[string]interface{}, len(w.records))
+		for _, r := range w.records {
+			values[r.Key] = prettyValue(r.Value)
+		}
+		doc = values
+	}
+
+	b, err := encodingjson.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(b, '\n'))
+	return err
+}
+
+func prettyValue(value interface{}) interface{} {
+	if s, ok := value.(string); ok && encodingjson.Valid([]byte(s)) {
+		return encodingjson.RawMessage(s)
+	}
+	return value
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'g', -1, 64)
+}
@@ -0,0 +1,196 @@
+// Package export writes and reads key records in the export file formats
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var json = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Format is an export file format
+type Format string
+
+const (
+	// FormatJSONL writes one JSON record per line
+	FormatJSONL Format = "jsonl"
+	// FormatJSON writes a single JSON array of records
+	FormatJSON Format = "json"
+	// FormatCSV writes key, type, ttl_ms, field and value columns, for
+	// strings and hashes only
+	FormatCSV Format = "csv"
+	// FormatDump writes JSON lines holding the base64 DUMP payload of each
+	// key instead of its value, restoring keys exactly
+	FormatDump Format = "dump"
+	// FormatRaw writes values as redis-cli --raw prints them
+	FormatRaw Format = "raw"
+	// FormatPretty writes the indented JSON of the values
+	FormatPretty Format = "pretty"
+	// FormatCommands writes redis-cli commands recreating the keys
+	FormatCommands Format = "commands"
+	// FormatRESP writes the same commands encoded in the Redis protocol, for
+	// redis-cli --pipe
+	FormatRESP Format = "resp"
+)
+
+// Formats lists the formats accepted by ParseFormat
+var Formats = []Format{FormatJSONL, FormatJSON, FormatCSV, FormatDump, FormatCommands, FormatRESP}
+
+// ErrUnsupportedType is returned when a format cannot represent a key type
+var ErrUnsupportedType = errors.New("type not supported by the format")
+
+// checkValue fails with ErrUnsupportedType for a record holding neither a
+// value nor a DUMP payload, a key of a type whose value cannot be read
+func checkValue(r redis.KeyRecord) error {
+	if r.Value == nil && r.Dump == nil {
+		return fmt.Errorf("%s is a %s: %w", r.Key, r.Type, ErrUnsupportedType)
+	}
+	return nil
+}
+
+// ParseFormat validates a format name
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("invalid format %q: use jsonl, json, csv, dump, commands or resp", s)
+}
+
+// Dump reports whether the format needs DUMP payloads rather than values
+func (f Format) Dump() bool {
+	return f == FormatDump
+}
+
+// Extension returns the usual file name extension of the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatJSONL, FormatDump:
+		return ".jsonl"
+	case FormatJSON, FormatPretty:
+		return ".json"
+	case FormatCSV:
+		return ".csv"
+	case FormatCommands:
+		return ".redis"
+	case FormatRESP:
+		return ".resp"
+	}
+	return ".txt"
+}
+
+// Writer writes records in a format. Close must be called to complete the
+// output; it does not close the underlying writer.
+type Writer interface {
+	Write(r redis.KeyRecord) error
+	Close() error
+}
+
+// NewWriter returns a writer of records in format f
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case FormatJSONL, FormatDump:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"key", "type", "ttl_ms", "field", "value"}); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw}, nil
+	case FormatRaw:
+		return &rawWriter{w: bufio.NewWriter(w)}, nil
+	case FormatPretty:
+		return &prettyWriter{w: w}, nil
+	case FormatCommands:
+		return &commandsWriter{w: bufio.NewWriter(w)}, nil
+	case FormatRESP:
+		return &respWriter{w: bufio.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("invalid format %q", f)
+}
+
+type jsonlWriter struct {
+	enc *jsoniter.Encoder
+}
+
+func (w *jsonlWriter) Write(r redis.KeyRecord) error {
+	if err := checkValue(r); err != nil {
+		return err
+	}
+	return w.enc.Encode(r)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+// jsonWriter streams the records as the elements of a JSON array
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (w *jsonWriter) Write(r redis.KeyRecord) error {
+	if err := checkValue(r); err != nil {
+		return err
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if w.count == 0 {
+		sep = "[\n  "
+	}
+	w.count++
+	if _, err := io.WriteString(w.w, sep); err != nil {
+		return err
+	}
+	_, err = w.w.Write(b)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	end := "\n]\n"
+	if w.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(w.w, end)
+	return err
+}
+
+// csvWriter writes a row per string and a row per hash field
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (w *csvWriter) Write(r redis.KeyRecord) error {
+	ttl := strconv.FormatInt(r.TTLMillis, 10)
+	switch value := r.Value.(type) {
+	case string:
+		return w.w.Write([]string{r.Key, r.Type, ttl, "", value})
+	case map[string]string:
+		for _, field := range redis.SortedFields(value) {
+			if err := w.w.Write([]string{r.Key, r.Type, ttl, field, value[field]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("%s is a %s: %w", r.Key, r.Type, ErrUnsupportedType)
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
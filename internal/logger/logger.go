@@ -0,0 +1,156 @@
+// Package logger provides a small leveled, structured file logger.
+// Nothing is written until Init is called with a file path.
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is a logging severity
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	levelDisabled
+)
+
+// String returns the lower-case level name
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return "disabled"
+	}
+}
+
+// ParseLevel parses a level name (debug, info, warn or error)
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return levelDisabled, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+	}
+}
+
+var (
+	mu    sync.Mutex
+	out   io.Writer
+	level = levelDisabled
+)
+
+// Init opens (appending to) the log file at path and enables logging at the
+// given level. The returned closer must be closed on exit.
+func Init(path string, levelName string) (io.Closer, error) {
+	lvl, err := ParseLevel(levelName)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open log file: %w", err)
+	}
+
+	mu.Lock()
+	out = f
+	level = lvl
+	mu.Unlock()
+
+	return f, nil
+}
+
+// Enabled reports whether messages at lvl are written
+func Enabled(lvl Level) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return out != nil && lvl >= level
+}
+
+// Debug logs a message with key/value pairs at debug level
+func Debug(msg string, keyvals ...interface{}) { write(LevelDebug, msg, keyvals) }
+
+// Info logs a message with key/value pairs at info level
+func Info(msg string, keyvals ...interface{}) { write(LevelInfo, msg, keyvals) }
+
+// Warn logs a message with key/value pairs at warn level
+func Warn(msg string, keyvals ...interface{}) { write(LevelWarn, msg, keyvals) }
+
+// Error logs a message with key/value pairs at error level
+func Error(msg string, keyvals ...interface{}) { write(LevelError, msg, keyvals) }
+
+// write formats a logfmt line: time=... level=... msg=... key=value ...
+func write(lvl Level, msg string, keyvals []interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if out == nil || lvl < level {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("time=")
+	b.WriteString(time.Now().Format(time.RFC3339Nano))
+	b.WriteString(" level=")
+	b.WriteString(lvl.String())
+	b.WriteString(" msg=")
+	b.WriteString(quote(msg))
+
+	for i := 0; i < len(keyvals); i += 2 {
+		b.WriteByte(' ')
+		b.WriteString(fmt.Sprint(keyvals[i]))
+		b.WriteByte('=')
+		if i+1 < len(keyvals) {
+			b.WriteString(quote(formatValue(keyvals[i+1])))
+		} else {
+			b.WriteString(`"(MISSING)"`)
+		}
+	}
+	b.WriteByte('\n')
+
+	_, _ = io.WriteString(out, b.String())
+}
+
+// formatValue renders a logged value as a string
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quote quotes s when it contains characters that would break logfmt parsing
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
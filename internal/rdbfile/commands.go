@@ -0,0 +1,914 @@
+package rdbfile
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	errWrongType = "WRONGTYPE Operation against a key holding the wrong kind of value"
+	errSyntax    = "ERR syntax error"
+	errNotInt    = "ERR value is not an integer or out of range"
+)
+
+// minDatabases is the database count reported by CONFIG GET databases, the
+// Redis default, raised when the file holds higher databases
+const minDatabases = 16
+
+// exec runs a command, returning its reply and whether to close the
+// connection
+func (c *conn) exec(args []string) ([]byte, bool) {
+	name := strings.ToLower(args[0])
+	args = args[1:]
+
+	switch name {
+	case "ping":
+		if len(args) > 0 {
+			return bulkReply(args[0]), false
+		}
+		return statusReply("PONG"), false
+	case "echo":
+		if len(args) != 1 {
+			return wrongArgs(name), false
+		}
+		return bulkReply(args[0]), false
+	case "quit":
+		return statusReply("OK"), true
+	case "auth", "readonly":
+		// There are no users and no replicas to route reads to
+		return statusReply("OK"), false
+	case "client":
+		if len(args) > 0 && strings.EqualFold(args[0], "setname") {
+			return statusReply("OK"), false
+		}
+	case "select":
+		if len(args) != 1 {
+			return wrongArgs(name), false
+		}
+		db, err := strconv.Atoi(args[0])
+		if err != nil {
+			return errorReply(errNotInt), false
+		}
+		if db < 0 || db >= c.server.file.databases() {
+			return errorReply("ERR DB index is out of range"), false
+		}
+		c.db = db
+		return statusReply("OK"), false
+	case "info":
+		return bulkReply(c.server.file.info()), false
+	case "config":
+		if len(args) == 2 && strings.EqualFold(args[0], "get") {
+			var reply []string
+			if matchGlob(strings.ToLower(args[1]), "databases") {
+				reply = []string{"databases", strconv.Itoa(c.server.file.databases())}
+			}
+			return bulkArrayReply(reply), false
+		}
+	case "dbsize":
+		return intReply(int64(len(c.keyspace().keys))), false
+	}
+
+	if handler, ok := keyCommands[name]; ok {
+		return handler(c, args), false
+	}
+	return errorReply(fmt.Sprintf("ERR '%s' is not available when browsing an RDB file", name)), false
+}
+
+func wrongArgs(name string) []byte {
+	return errorReply(fmt.Sprintf("ERR wrong number of arguments for '%s' command", name))
+}
+
+// keyspace returns the selected database, empty when the file has none
+func (c *conn) keyspace() *database {
+	if db, ok := c.server.file.dbs[c.db]; ok {
+		return db
+	}
+	return &database{}
+}
+
+// lookup returns the entry of a key, nil when it does not exist
+func (c *conn) lookup(key string) *entry {
+	return c.keyspace().entries[key]
+}
+
+// keyCommands handle the commands reading keys
+var keyCommands = map[string]func(c *conn, args []string) []byte{
+	"scan":        cmdScan,
+	"keys":        cmdKeys,
+	"randomkey":   cmdRandomKey,
+	"exists":      cmdExists,
+	"type":        cmdType,
+	"ttl":         ttlCommand(false, false),
+	"pttl":        ttlCommand(true, false),
+	"expiretime":  ttlCommand(false, true),
+	"pexpiretime": ttlCommand(true, true),
+	"object":      cmdObject,
+	"dump":        cmdDump,
+	"get":         cmdGet,
+	"mget":        cmdMGet,
+	"strlen":      cmdStrlen,
+	"getrange":    cmdGetRange,
+	"llen":        lengthCommand("list"),
+	"lrange":      cmdLRange,
+	"lindex":      cmdLIndex,
+	"scard":       lengthCommand("set"),
+	"smembers":    cmdSMembers,
+	"sismember":   cmdSIsMember,
+	"sscan":       collectionScan("set"),
+	"zcard":       lengthCommand("zset"),
+	"zrange":      cmdZRange,
+	"zrevrange":   cmdZRevRange,
+	"zscore":      cmdZScore,
+	"zscan":       collectionScan("zset"),
+	"hlen":        lengthCommand("hash"),
+	"hgetall":     cmdHGetAll,
+	"hget":        cmdHGet,
+	"hmget":       cmdHMGet,
+	"hexists":     cmdHExists,
+	"hkeys":       cmdHKeys,
+	"hvals":       cmdHVals,
+	"hscan":       collectionScan("hash"),
+	"xlen":        lengthCommand("stream"),
+	"xrange":      streamRange(false),
+	"xrevrange":   streamRange(true),
+}
+
+// scanOptions are the MATCH, COUNT and TYPE options of the SCAN family
+type scanOptions struct {
+	match    string
+	count    int
+	typeName string
+}
+
+func parseScanOptions(args []string, allowType bool) (scanOptions, bool) {
+	opts := scanOptions{match: "*", count: 10}
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return opts, false
+		}
+		switch strings.ToLower(args[i]) {
+		case "match":
+			opts.match = args[i+1]
+		case "count":
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return opts, false
+			}
+			opts.count = n
+		case "type":
+			if !allowType {
+				return opts, false
+			}
+			opts.typeName = strings.ToLower(args[i+1])
+		default:
+			return opts, false
+		}
+	}
+	return opts, true
+}
+
+// cmdScan walks the sorted keys, the cursor being the index of the next key
+func cmdScan(c *conn, args []string) []byte {
+	if len(args) < 1 {
+		return wrongArgs("scan")
+	}
+	cursor, err := strconv.Atoi(args[0])
+	if err != nil || cursor < 0 {
+		return errorReply("ERR invalid cursor")
+	}
+	opts, ok := parseScanOptions(args[1:], true)
+	if !ok {
+		return errorReply(errSyntax)
+	}
+
+	db := c.keyspace()
+	var keys []string
+	end := cursor + opts.count
+	if end >= len(db.keys) {
+		end = len(db.keys)
+	}
+	for i := cursor; i < end; i++ {
+		key := db.keys[i]
+		if opts.typeName != "" && db.entries[key].typeName != opts.typeName {
+			continue
+		}
+		if matchGlob(opts.match, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	next := end
+	if end >= len(db.keys) {
+		next = 0
+	}
+	return arrayReply([][]byte{bulkReply(strconv.Itoa(next)), bulkArrayReply(keys)})
+}
+
+func cmdKeys(c *conn, args []string) []byte {
+	if len(args) != 1 {
+		return wrongArgs("keys")
+	}
+	var keys []string
+	for _, key := range c.keyspace().keys {
+		if matchGlob(args[0], key) {
+			keys = append(keys, key)
+		}
+	}
+	return bulkArrayReply(keys)
+}
+
+func cmdRandomKey(c *conn, args []string) []byte {
+	keys := c.keyspace().keys
+	if len(keys) == 0 {
+		return nilReply()
+	}
+	return bulkReply(keys[rand.Intn(len(keys))])
+}
+
+func cmdExists(c *conn, args []string) []byte {
+	if len(args) == 0 {
+		return wrongArgs("exists")
+	}
+	var n int64
+	for _, key := range args {
+		if c.lookup(key) != nil {
+			n++
+		}
+	}
+	return intReply(n)
+}
+
+func cmdType(c *conn, args []string) []byte {
+	if len(args) != 1 {
+		return wrongArgs("type")
+	}
+	e := c.lookup(args[0])
+	if e == nil {
+		return statusReply("none")
+	}
+	return statusReply(e.typeName)
+}
+
+// ttlCommand returns the handler of TTL, PTTL, EXPIRETIME and PEXPIRETIME.
+// The remaining time to live is relative to the snapshot time.
+func ttlCommand(millis, absolute bool) func(c *conn, args []string) []byte {
+	return func(c *conn, args []string) []byte {
+		if len(args) != 1 {
+			return wrongArgs("ttl")
+		}
+		e := c.lookup(args[0])
+		switch {
+		case e == nil:
+			return intReply(-2)
+		case e.expireAt == 0:
+			return intReply(-1)
+		}
+
+		ms := e.expireAt
+		if !absolute {
+			ms -= c.server.file.Created.UnixNano() / int64(time.Millisecond)
+		}
+		if millis {
+			return intReply(ms)
+		}
+		return intReply((ms + 500) / 1000)
+	}
+}
+
+func cmdObject(c *conn, args []string) []byte {
+	if len(args) != 2 {
+		return wrongArgs("object")
+	}
+	e := c.lookup(args[1])
+	if e == nil {
+		return nilReply()
+	}
+	switch strings.ToLower(args[0]) {
+	case "encoding":
+		return bulkReply(e.encoding)
+	case "idletime":
+		if e.idle < 0 {
+			return errorReply("ERR the file holds no idle time, it was saved under an LFU maxmemory-policy or without one")
+		}
+		return intReply(e.idle)
+	case "freq":
+		if e.freq < 0 {
+			return errorReply("ERR the file holds no access frequency, it was not saved under an LFU maxmemory-policy")
+		}
+		return intReply(int64(e.freq))
+	case "refcount":
+		return intReply(1)
+	}
+	return errorReply(errSyntax)
+}
+
+func cmdDump(c *conn, args []string) []byte {
+	if len(args) != 1 {
+		return wrongArgs("dump")
+	}
+	e := c.lookup(args[0])
+	if e == nil {
+		return nilReply()
+	}
+	return bulkReply(string(dumpPayload(e, c.server.file.Version)))
+}
+
+// typed looks a key up, returning a WRONGTYPE error reply when it is not of
+// the expected type. Both are nil when the key does not exist.
+func (c *conn) typed(key, typeName string) (*entry, []byte) {
+	e := c.lookup(key)
+	if e == nil {
+		return nil, nil
+	}
+	if e.typeName != typeName {
+		return nil, errorReply(errWrongType)
+	}
+	return e, nil
+}
+
+func cmdGet(c *conn, args []string) []byte {
+	if len(args) != 1 {
+		return wrongArgs("get")
+	}
+	e, reply := c.typed(args[0], "string")
+	if reply != nil {
+		return reply
+	}
+	if e == nil {
+		return nilReply()
+	}
+	return bulkReply(e.value.(string))
+}
+
+func cmdMGet(c *conn, args []string) []byte {
+	items := make([][]byte, len(args))
+	for i, key := range args {
+		e := c.lookup(key)
+		if e == nil || e.typeName != "string" {
+			items[i] = nilReply()
+			continue
+		}
+		items[i] = bulkReply(e.value.(string))
+	}
+	return arrayReply(items)
+}
+
+func cmdStrlen(c *conn, args []string) []byte {
+	if len(args) != 1 {
+		return wrongArgs("strlen")
+	}
+	e, reply := c.typed(args[0], "string")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return intReply(0)
+	}
+	return intReply(int64(len(e.value.(string))))
+}
+
+func cmdGetRange(c *conn, args []string) []byte {
+	if len(args) != 3 {
+		return wrongArgs("getrange")
+	}
+	start, err1 := strconv.Atoi(args[1])
+	stop, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return errorReply(errNotInt)
+	}
+	e, reply := c.typed(args[0], "string")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return bulkReply("")
+	}
+	s := e.value.(string)
+	from, to, ok := indexRange(start, stop, len(s))
+	if !ok {
+		return bulkReply("")
+	}
+	return bulkReply(s[from:to])
+}
+
+// lengthCommand returns the handler of LLEN, SCARD, ZCARD, HLEN and XLEN
+func lengthCommand(typeName string) func(c *conn, args []string) []byte {
+	return func(c *conn, args []string) []byte {
+		if len(args) != 1 {
+			return wrongArgs("len")
+		}
+		e, reply := c.typed(args[0], typeName)
+		if e == nil {
+			if reply != nil {
+				return reply
+			}
+			return intReply(0)
+		}
+		return intReply(int64(e.length()))
+	}
+}
+
+// length returns the number of elements of a collection
+func (e *entry) length() int {
+	switch v := e.value.(type) {
+	case []string:
+		return len(v)
+	case []field:
+		return len(v)
+	case []zmember:
+		return len(v)
+	case []streamEntry:
+		return len(v)
+	}
+	return 0
+}
+
+// indexRange converts inclusive, possibly negative, start and stop indexes
+// to a slice range of a sequence of n elements
+func indexRange(start, stop, n int) (int, int, bool) {
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop || start >= n {
+		return 0, 0, false
+	}
+	return start, stop + 1, true
+}
+
+func cmdLRange(c *conn, args []string) []byte {
+	if len(args) != 3 {
+		return wrongArgs("lrange")
+	}
+	start, err1 := strconv.Atoi(args[1])
+	stop, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return errorReply(errNotInt)
+	}
+	e, reply := c.typed(args[0], "list")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return arrayReply(nil)
+	}
+	values := e.value.([]string)
+	from, to, ok := indexRange(start, stop, len(values))
+	if !ok {
+		return arrayReply(nil)
+	}
+	return bulkArrayReply(values[from:to])
+}
+
+func cmdLIndex(c *conn, args []string) []byte {
+	if len(args) != 2 {
+		return wrongArgs("lindex")
+	}
+	index, err := strconv.Atoi(args[1])
+	if err != nil {
+		return errorReply(errNotInt)
+	}
+	e, reply := c.typed(args[0], "list")
+	if reply != nil {
+		return reply
+	}
+	if e == nil {
+		return nilReply()
+	}
+	values := e.value.([]string)
+	if index < 0 {
+		index += len(values)
+	}
+	if index < 0 || index >= len(values) {
+		return nilReply()
+	}
+	return bulkReply(values[index])
+}
+
+func cmdSMembers(c *conn, args []string) []byte {
+	if len(args) != 1 {
+		return wrongArgs("smembers")
+	}
+	e, reply := c.typed(args[0], "set")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return arrayReply(nil)
+	}
+	return bulkArrayReply(e.value.([]string))
+}
+
+func cmdSIsMember(c *conn, args []string) []byte {
+	if len(args) != 2 {
+		return wrongArgs("sismember")
+	}
+	e, reply := c.typed(args[0], "set")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return intReply(0)
+	}
+	for _, m := range e.value.([]string) {
+		if m == args[1] {
+			return intReply(1)
+		}
+	}
+	return intReply(0)
+}
+
+// collectionScan returns the handler of SSCAN, ZSCAN and HSCAN, which
+// return every matching element in a single call
+func collectionScan(typeName string) func(c *conn, args []string) []byte {
+	return func(c *conn, args []string) []byte {
+		if len(args) < 2 {
+			return wrongArgs("scan")
+		}
+		opts, ok := parseScanOptions(args[2:], false)
+		if !ok {
+			return errorReply(errSyntax)
+		}
+		e, reply := c.typed(args[0], typeName)
+		var elements []string
+		if reply != nil {
+			return reply
+		}
+		if e != nil {
+			switch v := e.value.(type) {
+			case []string:
+				for _, m := range v {
+					if matchGlob(opts.match, m) {
+						elements = append(elements, m)
+					}
+				}
+			case []field:
+				for _, f := range v {
+					if matchGlob(opts.match, f.name) {
+						elements = append(elements, f.name, f.value)
+					}
+				}
+			case []zmember:
+				for _, m := range v {
+					if matchGlob(opts.match, m.member) {
+						elements = append(elements, m.member, formatScore(m.score))
+					}
+				}
+			}
+		}
+		return arrayReply([][]byte{bulkReply("0"), bulkArrayReply(elements)})
+	}
+}
+
+func cmdZRange(c *conn, args []string) []byte {
+	return zrange(c, "zrange", args, false)
+}
+
+func cmdZRevRange(c *conn, args []string) []byte {
+	return zrange(c, "zrevrange", args, true)
+}
+
+// zrange serves ZRANGE and ZREVRANGE by index, with WITHSCORES and REV
+func zrange(c *conn, name string, args []string, rev bool) []byte {
+	if len(args) < 3 {
+		return wrongArgs(name)
+	}
+	withScores := false
+	for _, opt := range args[3:] {
+		switch strings.ToLower(opt) {
+		case "withscores":
+			withScores = true
+		case "rev":
+			rev = true
+		default:
+			return errorReply("ERR only index ranges are available when browsing an RDB file")
+		}
+	}
+	start, err1 := strconv.Atoi(args[1])
+	stop, err2 := strconv.Atoi(args[2])
+	if err1 != nil || err2 != nil {
+		return errorReply(errNotInt)
+	}
+	e, reply := c.typed(args[0], "zset")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return arrayReply(nil)
+	}
+
+	members := e.value.([]zmember)
+	from, to, ok := indexRange(start, stop, len(members))
+	if !ok {
+		return arrayReply(nil)
+	}
+	var values []string
+	for i := from; i < to; i++ {
+		m := members[i]
+		if rev {
+			m = members[len(members)-1-i]
+		}
+		values = append(values, m.member)
+		if withScores {
+			values = append(values, formatScore(m.score))
+		}
+	}
+	return bulkArrayReply(values)
+}
+
+func cmdZScore(c *conn, args []string) []byte {
+	if len(args) != 2 {
+		return wrongArgs("zscore")
+	}
+	e, reply := c.typed(args[0], "zset")
+	if reply != nil {
+		return reply
+	}
+	if e == nil {
+		return nilReply()
+	}
+	for _, m := range e.value.([]zmember) {
+		if m.member == args[1] {
+			return bulkReply(formatScore(m.score))
+		}
+	}
+	return nilReply()
+}
+
+// formatScore formats a score as Redis replies it
+func formatScore(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	}
+	return strconv.FormatFloat(score, 'g', -1, 64)
+}
+
+// hashCommand runs fn on the fields of a hash, replying empty when the key
+// does not exist
+func hashCommand(c *conn, args []string, n int, name string, empty []byte, fn func([]field) []byte) []byte {
+	if len(args) < n {
+		return wrongArgs(name)
+	}
+	e, reply := c.typed(args[0], "hash")
+	if e == nil {
+		if reply != nil {
+			return reply
+		}
+		return empty
+	}
+	return fn(e.value.([]field))
+}
+
+func cmdHGetAll(c *conn, args []string) []byte {
+	return hashCommand(c, args, 1, "hgetall", arrayReply(nil), func(fields []field) []byte {
+		values := make([]string, 0, 2*len(fields))
+		for _, f := range fields {
+			values = append(values, f.name, f.value)
+		}
+		return bulkArrayReply(values)
+	})
+}
+
+func cmdHGet(c *conn, args []string) []byte {
+	return hashCommand(c, args, 2, "hget", nilReply(), func(fields []field) []byte {
+		for _, f := range fields {
+			if f.name == args[1] {
+				return bulkReply(f.value)
+			}
+		}
+		return nilReply()
+	})
+}
+
+func cmdHMGet(c *conn, args []string) []byte {
+	lookup := func(fields []field) []byte {
+		items := make([][]byte, len(args)-1)
+		for i, name := range args[1:] {
+			items[i] = nilReply()
+			for _, f := range fields {
+				if f.name == name {
+					items[i] = bulkReply(f.value)
+					break
+				}
+			}
+		}
+		return arrayReply(items)
+	}
+	if len(args) < 2 {
+		return wrongArgs("hmget")
+	}
+	return hashCommand(c, args, 2, "hmget", lookup(nil), lookup)
+}
+
+func cmdHExists(c *conn, args []string) []byte {
+	return hashCommand(c, args, 2, "hexists", intReply(0), func(fields []field) []byte {
+		for _, f := range fields {
+			if f.name == args[1] {
+				return intReply(1)
+			}
+		}
+		return intReply(0)
+	})
+}
+
+func cmdHKeys(c *conn, args []string) []byte {
+	return hashCommand(c, args, 1, "hkeys", arrayReply(nil), func(fields []field) []byte {
+		names := make([]string, len(fields))
+		for i, f := range fields {
+			names[i] = f.name
+		}
+		return bulkArrayReply(names)
+	})
+}
+
+func cmdHVals(c *conn, args []string) []byte {
+	return hashCommand(c, args, 1, "hvals", arrayReply(nil), func(fields []field) []byte {
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = f.value
+		}
+		return bulkArrayReply(values)
+	})
+}
+
+// streamRange returns the handler of XRANGE and XREVRANGE
+func streamRange(rev bool) func(c *conn, args []string) []byte {
+	return func(c *conn, args []string) []byte {
+		if len(args) != 3 && len(args) != 5 {
+			return wrongArgs("xrange")
+		}
+		lowArg, highArg := args[1], args[2]
+		if rev {
+			lowArg, highArg = highArg, lowArg
+		}
+		low, lowOK := parseStreamID(lowArg, false)
+		high, highOK := parseStreamID(highArg, true)
+		if !lowOK || !highOK {
+			return errorReply("ERR Invalid stream ID specified as stream command argument")
+		}
+		count := -1
+		if len(args) == 5 {
+			n, err := strconv.Atoi(args[4])
+			if !strings.EqualFold(args[3], "count") || err != nil {
+				return errorReply(errSyntax)
+			}
+			count = n
+		}
+
+		e, reply := c.typed(args[0], "stream")
+		if e == nil {
+			if reply != nil {
+				return reply
+			}
+			return arrayReply(nil)
+		}
+
+		entries := e.value.([]streamEntry)
+		var items [][]byte
+		for i := range entries {
+			entry := entries[i]
+			if rev {
+				entry = entries[len(entries)-1-i]
+			}
+			if entry.id.less(low) || high.less(entry.id) {
+				continue
+			}
+			if count >= 0 && len(items) == count {
+				break
+			}
+			items = append(items, arrayReply([][]byte{bulkReply(entry.id.String()), bulkArrayReply(entry.fields)}))
+		}
+		return arrayReply(items)
+	}
+}
+
+// parseStreamID parses a range bound: - and +, a millisecond time whose
+// sequence defaults to the lowest or highest, or a full ID. An exclusive
+// bound, prefixed with (, is moved to the next or previous ID.
+func parseStreamID(s string, high bool) (streamID, bool) {
+	switch s {
+	case "-":
+		return streamID{}, true
+	case "+":
+		return streamID{ms: math.MaxUint64, seq: math.MaxUint64}, true
+	}
+
+	exclusive := strings.HasPrefix(s, "(")
+	s = strings.TrimPrefix(s, "(")
+	var id streamID
+	var err error
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		if id.ms, err = strconv.ParseUint(s[:i], 10, 64); err != nil {
+			return id, false
+		}
+		if id.seq, err = strconv.ParseUint(s[i+1:], 10, 64); err != nil {
+			return id, false
+		}
+	} else {
+		if id.ms, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return id, false
+		}
+		if high {
+			id.seq = math.MaxUint64
+		}
+	}
+
+	if exclusive {
+		if high {
+			if id.seq > 0 {
+				id.seq--
+			} else if id.ms > 0 {
+				id.ms, id.seq = id.ms-1, math.MaxUint64
+			} else {
+				return id, false
+			}
+		} else {
+			if id.seq < math.MaxUint64 {
+				id.seq++
+			} else if id.ms < math.MaxUint64 {
+				id.ms, id.seq = id.ms+1, 0
+			} else {
+				return id, false
+			}
+		}
+	}
+	return id, true
+}
+
+// databases is the number of databases reported by CONFIG GET databases
+func (f *File) databases() int {
+	n := minDatabases
+	for db := range f.dbs {
+		if db >= n {
+			n = db + 1
+		}
+	}
+	return n
+}
+
+// info renders the INFO reply: the server and memory fields recorded in the
+// file, its auxiliary fields and its keyspace
+func (f *File) info() string {
+	var b strings.Builder
+	b.WriteString("# Server\r\n")
+	fmt.Fprintf(&b, "redis_version:%s\r\n", f.Aux["redis-ver"])
+	fmt.Fprintf(&b, "rdb_file:%s\r\n", f.Path)
+	fmt.Fprintf(&b, "rdb_version:%d\r\n", f.Version)
+	fmt.Fprintf(&b, "rdb_created:%s\r\n", f.Created.Format(time.RFC3339))
+	fmt.Fprintf(&b, "rdb_expired_keys_skipped:%d\r\n", f.Expired)
+	if usedMem, err := strconv.ParseInt(f.Aux["used-mem"], 10, 64); err == nil {
+		fmt.Fprintf(&b, "\r\n# Memory\r\nused_memory:%d\r\n", usedMem)
+	}
+
+	b.WriteString("\r\n# Aux\r\n")
+	names := make([]string, 0, len(f.Aux))
+	for name := range f.Aux {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s:%s\r\n", name, f.Aux[name])
+	}
+
+	b.WriteString("\r\n# Keyspace\r\n")
+	dbs := make([]int, 0, len(f.dbs))
+	for n := range f.dbs {
+		dbs = append(dbs, n)
+	}
+	sort.Ints(dbs)
+	created := f.Created.UnixNano() / int64(time.Millisecond)
+	for _, n := range dbs {
+		db := f.dbs[n]
+		if len(db.keys) == 0 {
+			continue
+		}
+		var ttlSum int64
+		for _, key := range db.keys {
+			if e := db.entries[key]; e.expireAt != 0 {
+				ttlSum += e.expireAt - created
+			}
+		}
+		avgTTL := int64(0)
+		if db.expires > 0 {
+			avgTTL = ttlSum / int64(db.expires)
+		}
+		fmt.Fprintf(&b, "db%d:keys=%d,expires=%d,avg_ttl=%d\r\n", n, len(db.keys), db.expires, avgTTL)
+	}
+	return b.String()
+}
@@ -0,0 +1,43 @@
+package rdbfile
+
+import "encoding/binary"
+
+// crc64Table is the table of the Jones CRC-64 of DUMP payloads and RDB
+// files, in its reflected form with no initial or final inversion, unlike
+// hash/crc64
+var crc64Table = func() *[256]uint64 {
+	const poly = 0x95ac9329ac4bc9b5
+	var t [256]uint64
+	for i := range t {
+		crc := uint64(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 == 1 {
+				crc = crc>>1 ^ poly
+			} else {
+				crc >>= 1
+			}
+		}
+		t[i] = crc
+	}
+	return &t
+}()
+
+func crc64(crc uint64, b []byte) uint64 {
+	for _, c := range b {
+		crc = crc64Table[byte(crc)^c] ^ crc>>8
+	}
+	return crc
+}
+
+// dumpPayload builds the DUMP payload of a key: its type and serialized
+// value as stored in the file, the RDB version and a checksum. RESTORE
+// accepts it on servers supporting that version.
+func dumpPayload(e *entry, version int) []byte {
+	payload := make([]byte, 0, len(e.raw)+11)
+	payload = append(payload, e.rdbType)
+	payload = append(payload, e.raw...)
+	payload = append(payload, byte(version), byte(version>>8))
+	var sum [8]byte
+	binary.LittleEndian.PutUint64(sum[:], crc64(0, payload))
+	return append(payload, sum[:]...)
+}
@@ -0,0 +1,113 @@
+package rdbfile
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func TestDumpPayload(t *testing.T) {
+	// DUMP of "SET mykey 10" on Redis 5, from the documentation of DUMP
+	e := &entry{rdbType: typeString, raw: []byte{0xc0, 0x0a}}
+	want := "\x00\xc0\n\t\x00\xbem\x06\x89Z(\x00\n"
+	if got := string(dumpPayload(e, 9)); got != want {
+		t.Errorf("payload %q, want %q", got, want)
+	}
+}
+
+// restore decodes a DUMP payload as RESTORE does: the RDB version and the
+// checksum of the footer are checked, then the value is loaded and must use
+// the rest of the payload
+func restore(payload []byte, version int) (*entry, error) {
+	if len(payload) < 11 {
+		return nil, errors.New("payload too short")
+	}
+	body, footer := payload[:len(payload)-10], payload[len(payload)-10:]
+	if v := int(binary.LittleEndian.Uint16(footer)); v > version {
+		return nil, fmt.Errorf("payload version %d", v)
+	}
+	if binary.LittleEndian.Uint64(footer[2:]) != crc64(0, payload[:len(payload)-8]) {
+		return nil, errors.New("checksum mismatch")
+	}
+
+	r := &reader{buf: body, pos: 1}
+	e, err := readValue(r, body[0])
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(body) {
+		return nil, fmt.Errorf("%d bytes left after the value", len(body)-r.pos)
+	}
+	return e, nil
+}
+
+func TestDumpPayloadRoundTrip(t *testing.T) {
+	for _, name := range fixtures(t) {
+		f := parseFixture(t, name)
+		for n, db := range f.dbs {
+			for _, key := range db.keys {
+				e := db.entries[key]
+				restored, err := restore(dumpPayload(e, f.Version), f.Version)
+				if err != nil {
+					t.Errorf("%s db %d %q: %v", name, n, key, err)
+					continue
+				}
+				if restored.typeName != e.typeName || !reflect.DeepEqual(flatten(restored), flatten(e)) {
+					t.Errorf("%s db %d %q: restored a different %s", name, n, key, restored.typeName)
+				}
+			}
+		}
+	}
+}
+
+// TestRestore restores the keys of every fixture in the Redis server at
+// REDIS_VIEWER_TEST_ADDR and reads them back. The keys are created under
+// the rdbfile-test: prefix and deleted afterwards. Fixtures of a newer RDB
+// version than the server supports are skipped.
+func TestRestore(t *testing.T) {
+	addr := os.Getenv("REDIS_VIEWER_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_VIEWER_TEST_ADDR is not set")
+	}
+	ctx := context.Background()
+	c := redis.NewClient(&redis.Options{Addr: addr})
+	defer c.Close()
+	if err := c.Ping(ctx).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range fixtures(t) {
+		f := parseFixture(t, name)
+	fixture:
+		for n, db := range f.dbs {
+			for _, key := range db.keys {
+				e := db.entries[key]
+				target := fmt.Sprintf("rdbfile-test:%s:%d:%s", name, n, key)
+				err := c.RestoreReplace(ctx, target, 0, string(dumpPayload(e, f.Version))).Err()
+				if err != nil && strings.Contains(err.Error(), "payload version") {
+					t.Logf("%s: RDB version %d is not supported by the server", name, f.Version)
+					break fixture
+				}
+				if err != nil {
+					t.Errorf("%s db %d %q: RESTORE: %v", name, n, key, err)
+					continue
+				}
+
+				value, err := readBack(ctx, c, target, e.typeName)
+				c.Del(ctx, target)
+				if err != nil {
+					t.Errorf("%s db %d %q: %v", name, n, key, err)
+				} else if want := flatten(e); !reflect.DeepEqual(value, want) {
+					t.Errorf("%s db %d %q: restored %q, want %q", name, n, key, value, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package rdbfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+var errCorrupt = errors.New("corrupt compact encoding")
+
+// intsetEntries decodes an intset: the integer width and element count as
+// little endian uint32, then the sorted elements
+func intsetEntries(b []byte) ([]string, error) {
+	if len(b) < 8 {
+		return nil, errCorrupt
+	}
+	width := int(binary.LittleEndian.Uint32(b[0:4]))
+	n := int(binary.LittleEndian.Uint32(b[4:8]))
+	if width != 2 && width != 4 && width != 8 || len(b)-8 < n*width {
+		return nil, errCorrupt
+	}
+
+	entries := make([]string, n)
+	for i := range entries {
+		v := b[8+i*width:]
+		var x int64
+		switch width {
+		case 2:
+			x = int64(int16(binary.LittleEndian.Uint16(v)))
+		case 4:
+			x = int64(int32(binary.LittleEndian.Uint32(v)))
+		default:
+			x = int64(binary.LittleEndian.Uint64(v))
+		}
+		entries[i] = strconv.FormatInt(x, 10)
+	}
+	return entries, nil
+}
+
+// ziplistEntries decodes the entries of a ziplist, integers as decimal text
+func ziplistEntries(b []byte) ([]string, error) {
+	if len(b) < 11 {
+		return nil, errCorrupt
+	}
+	var entries []string
+	pos := 10
+	for {
+		if pos >= len(b) {
+			return nil, errCorrupt
+		}
+		if b[pos] == 0xff {
+			return entries, nil
+		}
+
+		// The length of the previous entry, 1 or 5 bytes
+		if b[pos] == 0xfe {
+			pos += 5
+		} else {
+			pos++
+		}
+		if pos >= len(b) {
+			return nil, errCorrupt
+		}
+
+		enc := b[pos]
+		pos++
+		var n int
+		switch enc >> 6 {
+		case 0:
+			n = int(enc & 0x3f)
+		case 1:
+			if pos >= len(b) {
+				return nil, errCorrupt
+			}
+			n = int(enc&0x3f)<<8 | int(b[pos])
+			pos++
+		case 2:
+			if pos+4 > len(b) {
+				return nil, errCorrupt
+			}
+			n = int(binary.BigEndian.Uint32(b[pos:]))
+			pos += 4
+		default:
+			v, size, err := ziplistInt(enc, b[pos:])
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, strconv.FormatInt(v, 10))
+			pos += size
+			continue
+		}
+		if n < 0 || pos+n > len(b) {
+			return nil, errCorrupt
+		}
+		entries = append(entries, string(b[pos:pos+n]))
+		pos += n
+	}
+}
+
+// ziplistInt decodes an integer entry of a ziplist, returning its size
+func ziplistInt(enc byte, b []byte) (int64, int, error) {
+	size := 0
+	switch enc {
+	case 0xc0:
+		size = 2
+	case 0xd0:
+		size = 4
+	case 0xe0:
+		size = 8
+	case 0xf0:
+		size = 3
+	case 0xfe:
+		size = 1
+	default:
+		if enc >= 0xf1 && enc <= 0xfd {
+			// Immediate 4 bit value, stored plus one
+			return int64(enc&0x0f) - 1, 0, nil
+		}
+		return 0, 0, fmt.Errorf("invalid ziplist encoding 0x%02x", enc)
+	}
+	if len(b) < size {
+		return 0, 0, errCorrupt
+	}
+	return signedLE(b[:size]), size, nil
+}
+
+// listpackEntries decodes the entries of a listpack, integers as decimal
+// text. Each entry is followed by its length, used to walk backwards.
+func listpackEntries(b []byte) ([]string, error) {
+	if len(b) < 7 {
+		return nil, errCorrupt
+	}
+	var entries []string
+	pos := 6
+	for {
+		if pos >= len(b) {
+			return nil, errCorrupt
+		}
+		enc := b[pos]
+		if enc == 0xff {
+			return entries, nil
+		}
+
+		var (
+			size  int // encoding and data
+			value string
+		)
+		switch {
+		case enc>>7 == 0:
+			// 7 bit unsigned integer
+			size = 1
+			value = strconv.Itoa(int(enc))
+		case enc>>6 == 2:
+			// String of up to 63 bytes
+			n := int(enc & 0x3f)
+			size = 1 + n
+			if pos+size > len(b) {
+				return nil, errCorrupt
+			}
+			value = string(b[pos+1 : pos+size])
+		case enc>>5 == 6:
+			// 13 bit signed integer
+			if pos+2 > len(b) {
+				return nil, errCorrupt
+			}
+			v := int(enc&0x1f)<<8 | int(b[pos+1])
+			if v >= 1<<12 {
+				v -= 1 << 13
+			}
+			size = 2
+			value = strconv.Itoa(v)
+		case enc>>4 == 0xe:
+			// String of up to 4095 bytes
+			if pos+2 > len(b) {
+				return nil, errCorrupt
+			}
+			n := int(enc&0x0f)<<8 | int(b[pos+1])
+			size = 2 + n
+			if pos+size > len(b) {
+				return nil, errCorrupt
+			}
+			value = string(b[pos+2 : pos+size])
+		case enc == 0xf0:
+			// String with a 32 bit length
+			if pos+5 > len(b) {
+				return nil, errCorrupt
+			}
+			n := int(binary.LittleEndian.Uint32(b[pos+1:]))
+			size = 5 + n
+			if n < 0 || pos+size > len(b) {
+				return nil, errCorrupt
+			}
+			value = string(b[pos+5 : pos+size])
+		case enc >= 0xf1 && enc <= 0xf4:
+			// 16, 24, 32 and 64 bit signed integers
+			n := [...]int{2, 3, 4, 8}[enc-0xf1]
+			size = 1 + n
+			if pos+size > len(b) {
+				return nil, errCorrupt
+			}
+			value = strconv.FormatInt(signedLE(b[pos+1:pos+size]), 10)
+		default:
+			return nil, fmt.Errorf("invalid listpack encoding 0x%02x", enc)
+		}
+
+		entries = append(entries, value)
+		pos += size + backlenSize(size)
+	}
+}
+
+// backlenSize is the number of bytes of the length following a listpack
+// entry of size bytes, 7 bits per byte
+func backlenSize(size int) int {
+	switch {
+	case size < 1<<7:
+		return 1
+	case size < 1<<14:
+		return 2
+	case size < 1<<21:
+		return 3
+	case size < 1<<28:
+		return 4
+	}
+	return 5
+}
+
+// signedLE decodes a little endian two's complement integer of 1 to 8 bytes
+func signedLE(b []byte) int64 {
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	shift := uint(64 - 8*len(b))
+	return int64(v<<shift) >> shift
+}
+
+// zipmapEntries decodes the alternating fields and values of a zipmap, the
+// hash encoding of RDB files older than Redis 2.6
+func zipmapEntries(b []byte) ([]string, error) {
+	var entries []string
+	pos := 1
+	readLen := func() (int, error) {
+		if pos >= len(b) {
+			return 0, errCorrupt
+		}
+		n := int(b[pos])
+		pos++
+		switch {
+		case n == 0xff:
+			return -1, nil
+		case n == 0xfe:
+			if pos+4 > len(b) {
+				return 0, errCorrupt
+			}
+			n = int(binary.LittleEndian.Uint32(b[pos:]))
+			pos += 4
+		}
+		return n, nil
+	}
+	for {
+		n, err := readLen()
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return entries, nil
+		}
+		if pos+n > len(b) {
+			return nil, errCorrupt
+		}
+		field := string(b[pos : pos+n])
+		pos += n
+
+		n, err = readLen()
+		if err != nil || n < 0 || pos+1+n > len(b) {
+			return nil, errCorrupt
+		}
+		// A byte of unused space follows the value length
+		free := int(b[pos])
+		pos++
+		value := string(b[pos : pos+n])
+		pos += n + free
+		entries = append(entries, field, value)
+	}
+}
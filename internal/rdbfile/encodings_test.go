@@ -0,0 +1,98 @@
+package rdbfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompactEncodings(t *testing.T) {
+	tests := []struct {
+		name   string
+		decode func([]byte) ([]string, error)
+		in     string
+		want   []string // nil when decoding must fail
+	}{
+		// Width, count, then little endian elements
+		{"intset", intsetEntries, "\x02\x00\x00\x00\x02\x00\x00\x00\xff\xff\x01\x00", []string{"-1", "1"}},
+		{"intset 64 bit", intsetEntries, "\x08\x00\x00\x00\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x80", []string{"-9223372036854775808"}},
+		{"intset truncated", intsetEntries, "\x02\x00\x00\x00\x02\x00\x00\x00\xff\xff\x01", nil},
+		{"intset invalid width", intsetEntries, "\x03\x00\x00\x00\x01\x00\x00\x00\x01\x00\x00", nil},
+		{"intset huge count", intsetEntries, "\x02\x00\x00\x00\xff\xff\xff\xff\x01\x00", nil},
+		{"intset short header", intsetEntries, "\x02\x00\x00", nil},
+
+		// Header, then previous length, encoding and data of each entry
+		{"ziplist", ziplistEntries, "\x13\x00\x00\x00\x0f\x00\x00\x00\x04\x00" +
+			"\x00\x01a" + "\x03\xf6" + "\x02\xc0\x34\x12" + "\x04\xfe\x80" + "\xff",
+			[]string{"a", "5", "4660", "-128"}},
+		{"ziplist 5 byte previous length", ziplistEntries, "\x12\x00\x00\x00\x0a\x00\x00\x00\x01\x00" +
+			"\xfe\x00\x01\x00\x00\x02ab" + "\xff", []string{"ab"}},
+		{"ziplist no end", ziplistEntries, "\x0d\x00\x00\x00\x0a\x00\x00\x00\x01\x00\x00\x01a", nil},
+		{"ziplist string past the end", ziplistEntries, "\x0e\x00\x00\x00\x0a\x00\x00\x00\x01\x00\x00\x05ab\xff", nil},
+		{"ziplist 32 bit length past the end", ziplistEntries, "\x0e\x00\x00\x00\x0a\x00\x00\x00\x01\x00\x00\x80\xff\xff\xff\xff", nil},
+		{"ziplist integer truncated", ziplistEntries, "\x0e\x00\x00\x00\x0a\x00\x00\x00\x01\x00\x00\xe0\x01\x02", nil},
+		{"ziplist invalid encoding", ziplistEntries, "\x0d\x00\x00\x00\x0a\x00\x00\x00\x01\x00\x00\xff\xff", nil},
+
+		// Header, then encoding, data and backwards length of each entry
+		{"listpack", listpackEntries, "\x14\x00\x00\x00\x04\x00" +
+			"\x05\x01" + "\x81a\x02" + "\xdf\xff\x02" + "\xf1\x34\x12\x03" + "\xff",
+			[]string{"5", "a", "-1", "4660"}},
+		{"listpack 12 bit string", listpackEntries, "\x0c\x00\x00\x00\x01\x00\xe0\x02ab\x04\xff", []string{"ab"}},
+		{"listpack 64 bit integer", listpackEntries, "\x11\x00\x00\x00\x01\x00\xf4\x00\x00\x00\x00\x00\x00\x00\x80\x09\xff",
+			[]string{"-9223372036854775808"}},
+		{"listpack no end", listpackEntries, "\x09\x00\x00\x00\x01\x00\x05\x01", nil},
+		{"listpack string past the end", listpackEntries, "\x0a\x00\x00\x00\x01\x00\x85ab\xff", nil},
+		{"listpack 32 bit length past the end", listpackEntries, "\x0c\x00\x00\x00\x01\x00\xf0\xff\xff\xff\x7f\xff", nil},
+		{"listpack integer truncated", listpackEntries, "\x09\x00\x00\x00\x01\x00\xf3\x01\x02", nil},
+		{"listpack invalid encoding", listpackEntries, "\x09\x00\x00\x00\x01\x00\xf5\x01\xff", nil},
+		{"listpack short header", listpackEntries, "\x07\x00\x00", nil},
+
+		// Count, then field length, field, value length, free space, value
+		{"zipmap", zipmapEntries, "\x02\x01f\x01\x00v\x02ab\x01\x02xyz\xff", []string{"f", "v", "ab", "x"}},
+		{"zipmap no end", zipmapEntries, "\x01\x01f\x01\x00v", nil},
+		{"zipmap value past the end", zipmapEntries, "\x01\x01f\x05\x00v\xff", nil},
+		{"zipmap field past the end", zipmapEntries, "\x01\x09f\x01\x00v\xff", nil},
+		{"zipmap 32 bit length truncated", zipmapEntries, "\x01\xfe\x01\x00", nil},
+		{"zipmap missing value", zipmapEntries, "\x01\x01f\xff", nil},
+	}
+	for _, tt := range tests {
+		got, err := tt.decode([]byte(tt.in))
+		switch {
+		case tt.want == nil && err == nil:
+			t.Errorf("%s: decoded %q, want an error", tt.name, got)
+		case tt.want != nil && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.want != nil && !reflect.DeepEqual(got, tt.want):
+			t.Errorf("%s: decoded %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLZFDecompress(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		outLen int
+		want   string // empty when decompressing must fail
+	}{
+		{"literal", "\x02abc", 3, "abc"},
+		// A literal "a", then 9 bytes copied from 1 byte back
+		{"back reference", "\x00a\xe0\x00\x00", 10, "aaaaaaaaaa"},
+		{"short back reference", "\x01ab\x20\x01", 5, "ababa"},
+		{"wrong length", "\x02abc", 4, ""},
+		{"literal truncated", "\x05abc", 6, ""},
+		{"back reference before the start", "\x00a\x20\x05", 4, ""},
+		{"back reference truncated", "\x00a\xe0", 10, ""},
+		{"long back reference truncated", "\x00a\xe0\x00", 10, ""},
+	}
+	for _, tt := range tests {
+		got, err := lzfDecompress([]byte(tt.in), tt.outLen)
+		switch {
+		case tt.want == "" && err == nil:
+			t.Errorf("%s: decompressed %q, want an error", tt.name, got)
+		case tt.want != "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.want != "" && string(got) != tt.want:
+			t.Errorf("%s: decompressed %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,696 @@
+// Package rdbfile parses Redis RDB snapshot files and serves their content over
+// an in-memory connection speaking the Redis protocol
+package rdbfile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Opcodes preceding the keys and metadata of the file
+const (
+	opFunctionPreGA = 0xf5
+	opFunction2     = 0xf6
+	opModuleAux     = 0xf7
+	opIdle          = 0xf8
+	opFreq          = 0xf9
+	opAux           = 0xfa
+	opResizeDB      = 0xfb
+	opExpireTimeMs  = 0xfc
+	opExpireTime    = 0xfd
+	opSelectDB      = 0xfe
+	opEOF           = 0xff
+	opSlotInfo      = 0xf4
+)
+
+// Value types
+const (
+	typeString            = 0
+	typeList              = 1
+	typeSet               = 2
+	typeZSet              = 3
+	typeHash              = 4
+	typeZSet2             = 5
+	typeModule            = 6
+	typeModule2           = 7
+	typeHashZipmap        = 9
+	typeListZiplist       = 10
+	typeSetIntset         = 11
+	typeZSetZiplist       = 12
+	typeHashZiplist       = 13
+	typeListQuicklist     = 14
+	typeStreamListpacks   = 15
+	typeHashListpack      = 16
+	typeZSetListpack      = 17
+	typeListQuicklist2    = 18
+	typeStreamListpacks2  = 19
+	typeSetListpack       = 20
+	typeStreamListpacks3  = 21
+	typeHashMetadataPreGA = 22
+	typeHashListpackExPre = 23
+	typeHashMetadata      = 24
+	typeHashListpackEx    = 25
+)
+
+// encodings names the encoding each value type is stored with, as OBJECT
+// ENCODING would report it
+var encodings = map[byte]string{
+	typeList:              "linkedlist",
+	typeSet:               "hashtable",
+	typeZSet:              "skiplist",
+	typeHash:              "hashtable",
+	typeZSet2:             "skiplist",
+	typeModule2:           "module",
+	typeHashZipmap:        "zipmap",
+	typeListZiplist:       "ziplist",
+	typeSetIntset:         "intset",
+	typeZSetZiplist:       "ziplist",
+	typeHashZiplist:       "ziplist",
+	typeListQuicklist:     "quicklist",
+	typeStreamListpacks:   "stream",
+	typeHashListpack:      "listpack",
+	typeZSetListpack:      "listpack",
+	typeListQuicklist2:    "quicklist",
+	typeStreamListpacks2:  "stream",
+	typeSetListpack:       "listpack",
+	typeStreamListpacks3:  "stream",
+	typeHashMetadataPreGA: "hashtable",
+	typeHashListpackExPre: "listpack",
+	typeHashMetadata:      "hashtable",
+	typeHashListpackEx:    "listpack",
+}
+
+// File is the content of an RDB file
+type File struct {
+	Path    string
+	Version int
+	// Aux holds the auxiliary fields, such as redis-ver and used-mem
+	Aux map[string]string
+	// Created is the time the snapshot was taken, from its ctime field or
+	// else the modification time of the file. TTLs are reported relative to
+	// it, and keys that had expired by then are left out as Redis would.
+	Created time.Time
+	// Expired counts the keys left out
+	Expired int
+
+	dbs map[int]*database
+}
+
+// database is the keyspace of one logical database
+type database struct {
+	entries map[string]*entry
+	keys    []string // sorted, giving SCAN a stable cursor
+	expires int
+}
+
+// entry is a key of the file
+type entry struct {
+	typeName string // the TYPE reply
+	encoding string
+	expireAt int64 // unix milliseconds, 0 when the key does not expire
+	idle     int64 // LRU idle seconds, -1 when not saved
+	freq     int   // LFU counter, -1 when not saved
+	value    interface{}
+
+	// The serialized value and its type byte, for DUMP
+	rdbType byte
+	raw     []byte
+}
+
+// field is a hash field
+type field struct {
+	name, value string
+}
+
+// zmember is a sorted set member
+type zmember struct {
+	member string
+	score  float64
+}
+
+// streamID is a stream entry ID
+type streamID struct {
+	ms, seq uint64
+}
+
+func (id streamID) String() string {
+	return strconv.FormatUint(id.ms, 10) + "-" + strconv.FormatUint(id.seq, 10)
+}
+
+func (id streamID) less(o streamID) bool {
+	return id.ms < o.ms || id.ms == o.ms && id.seq < o.seq
+}
+
+// streamEntry is a stream entry with its alternating fields and values
+type streamEntry struct {
+	id     streamID
+	fields []string
+}
+
+// Keys returns the number of keys of each database
+func (f *File) Keys() map[int]int {
+	counts := make(map[int]int, len(f.dbs))
+	for n, db := range f.dbs {
+		counts[n] = len(db.keys)
+	}
+	return counts
+}
+
+// Load reads and parses an RDB file. The whole file is kept in memory.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := Parse(data, info.ModTime())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	f.Path = path
+	return f, nil
+}
+
+// Parse parses the content of an RDB file. created is the snapshot time to
+// use when the file does not record it.
+func Parse(data []byte, created time.Time) (*File, error) {
+	if len(data) < 9 || string(data[:5]) != "REDIS" {
+		return nil, errors.New("not an RDB file")
+	}
+	version, err := strconv.Atoi(string(data[5:9]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RDB version %q", data[5:9])
+	}
+
+	f := &File{
+		Version: version,
+		Aux:     make(map[string]string),
+		Created: created,
+		dbs:     make(map[int]*database),
+	}
+	r := &reader{buf: data, pos: 9}
+	if err := f.parse(r); err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(r, version); err != nil {
+		return nil, err
+	}
+	if ctime, err := strconv.ParseInt(f.Aux["ctime"], 10, 64); err == nil {
+		f.Created = time.Unix(ctime, 0)
+	}
+	f.dropExpired()
+	return f, nil
+}
+
+// verifyChecksum checks the CRC-64 of the file that follows the end of file
+// marker since version 5. A zero checksum means it was disabled when saving.
+func verifyChecksum(r *reader, version int) error {
+	if version < 5 {
+		return nil
+	}
+	end := r.pos
+	sum, err := r.readUint64LE()
+	if err != nil {
+		return err
+	}
+	if sum != 0 && sum != crc64(0, r.buf[:end]) {
+		return errors.New("checksum mismatch")
+	}
+	return nil
+}
+
+// parse reads the opcodes and keys up to the end of file marker
+func (f *File) parse(r *reader) error {
+	db := f.database(0)
+	var expireAt int64
+	idle, freq := int64(-1), -1
+
+	for {
+		op, err := r.readByte()
+		if err != nil {
+			return err
+		}
+
+		switch op {
+		case opEOF:
+			return nil
+		case opSelectDB:
+			n, err := r.readLen()
+			if err != nil {
+				return err
+			}
+			db = f.database(n)
+		case opResizeDB:
+			if _, err := r.readLen(); err != nil {
+				return err
+			}
+			if _, err := r.readLen(); err != nil {
+				return err
+			}
+		case opSlotInfo:
+			for i := 0; i < 3; i++ {
+				if _, err := r.readLen(); err != nil {
+					return err
+				}
+			}
+		case opExpireTime:
+			s, err := r.readUint32LE()
+			if err != nil {
+				return err
+			}
+			expireAt = int64(s) * 1000
+		case opExpireTimeMs:
+			ms, err := r.readUint64LE()
+			if err != nil {
+				return err
+			}
+			expireAt = int64(ms)
+		case opIdle:
+			n, err := r.readUint()
+			if err != nil {
+				return err
+			}
+			idle = int64(n)
+		case opFreq:
+			b, err := r.readByte()
+			if err != nil {
+				return err
+			}
+			freq = int(b)
+		case opAux:
+			key, err := r.readString()
+			if err != nil {
+				return err
+			}
+			value, err := r.readString()
+			if err != nil {
+				return err
+			}
+			f.Aux[key] = value
+		case opModuleAux:
+			if _, err := r.readUint(); err != nil {
+				return err
+			}
+			// The "when" opcode and value, then the module's own data
+			if _, err := r.readUint(); err != nil {
+				return err
+			}
+			if _, err := r.readUint(); err != nil {
+				return err
+			}
+			if err := skipModuleData(r); err != nil {
+				return err
+			}
+		case opFunction2:
+			if _, err := r.readString(); err != nil {
+				return err
+			}
+		case opFunctionPreGA:
+			return errors.New("functions saved by a Redis 7.0 release candidate are not supported")
+		default:
+			key, err := r.readString()
+			if err != nil {
+				return err
+			}
+			start := r.pos
+			e, err := readValue(r, op)
+			if err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			e.expireAt, e.idle, e.freq = expireAt, idle, freq
+			e.rdbType, e.raw = op, r.buf[start:r.pos]
+			if _, ok := db.entries[key]; !ok {
+				db.keys = append(db.keys, key)
+			}
+			db.entries[key] = e
+			expireAt, idle, freq = 0, -1, -1
+		}
+	}
+}
+
+func (f *File) database(n int) *database {
+	db, ok := f.dbs[n]
+	if !ok {
+		db = &database{entries: make(map[string]*entry)}
+		f.dbs[n] = db
+	}
+	return db
+}
+
+// dropExpired removes the keys that had expired when the snapshot was
+// taken and sorts the remaining keys
+func (f *File) dropExpired() {
+	now := f.Created.UnixNano() / int64(time.Millisecond)
+	for _, db := range f.dbs {
+		keys := db.keys[:0]
+		for _, key := range db.keys {
+			e := db.entries[key]
+			if e.expireAt != 0 && e.expireAt <= now {
+				delete(db.entries, key)
+				f.Expired++
+				continue
+			}
+			if e.expireAt != 0 {
+				db.expires++
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		db.keys = keys
+	}
+}
+
+// readValue decodes a value of type t
+func readValue(r *reader, t byte) (*entry, error) {
+	e := &entry{encoding: encodings[t]}
+	var err error
+
+	switch t {
+	case typeString:
+		var s string
+		s, err = r.readString()
+		e.typeName, e.value, e.encoding = "string", s, stringEncoding(s)
+	case typeList:
+		e.typeName = "list"
+		e.value, err = readStrings(r, 1)
+	case typeSet:
+		e.typeName = "set"
+		e.value, err = readStrings(r, 1)
+	case typeHash:
+		e.typeName = "hash"
+		var pairs []string
+		if pairs, err = readStrings(r, 2); err == nil {
+			e.value = toFields(pairs)
+		}
+	case typeZSet, typeZSet2:
+		e.typeName = "zset"
+		e.value, err = readZSet(r, t == typeZSet2)
+	case typeListQuicklist, typeListQuicklist2:
+		e.typeName = "list"
+		e.value, err = readQuicklist(r, t == typeListQuicklist2)
+	case typeHashMetadataPreGA, typeHashMetadata:
+		e.typeName = "hash"
+		e.value, err = readHashMetadata(r, t == typeHashMetadata)
+	case typeHashListpackExPre, typeHashListpackEx:
+		e.typeName = "hash"
+		if t == typeHashListpackEx {
+			// The minimum field expiry, implied by the fields
+			if _, err = r.readUint64LE(); err != nil {
+				return nil, err
+			}
+		}
+		var entries []string
+		if entries, err = readCompact(r, listpackEntries); err == nil {
+			if len(entries)%3 != 0 {
+				return nil, errCorrupt
+			}
+			var pairs []string
+			for i := 0; i < len(entries); i += 3 {
+				pairs = append(pairs, entries[i], entries[i+1])
+			}
+			e.value = toFields(pairs)
+		}
+	case typeHashZipmap, typeHashZiplist, typeHashListpack:
+		e.typeName = "hash"
+		decode := ziplistEntries
+		if t == typeHashZipmap {
+			decode = zipmapEntries
+		} else if t == typeHashListpack {
+			decode = listpackEntries
+		}
+		var pairs []string
+		if pairs, err = readCompact(r, decode); err == nil {
+			if len(pairs)%2 != 0 {
+				return nil, errCorrupt
+			}
+			e.value = toFields(pairs)
+		}
+	case typeListZiplist:
+		e.typeName = "list"
+		e.value, err = readCompact(r, ziplistEntries)
+	case typeSetIntset, typeSetListpack:
+		e.typeName = "set"
+		decode := intsetEntries
+		if t == typeSetListpack {
+			decode = listpackEntries
+		}
+		e.value, err = readCompact(r, decode)
+	case typeZSetZiplist, typeZSetListpack:
+		e.typeName = "zset"
+		decode := ziplistEntries
+		if t == typeZSetListpack {
+			decode = listpackEntries
+		}
+		var pairs []string
+		if pairs, err = readCompact(r, decode); err == nil {
+			e.value, err = toZMembers(pairs)
+		}
+	case typeStreamListpacks, typeStreamListpacks2, typeStreamListpacks3:
+		e.typeName = "stream"
+		e.value, err = readStream(r, t)
+	case typeModule2:
+		var id uint64
+		if id, err = r.readUint(); err == nil {
+			e.typeName = moduleTypeName(id)
+			err = skipModuleData(r)
+		}
+	case typeModule:
+		return nil, errors.New("values of modules saved by Redis 4.0 release candidates are not supported")
+	default:
+		return nil, fmt.Errorf("unsupported value type %d", t)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// stringEncoding is the encoding Redis gives a string when loading it
+func stringEncoding(s string) string {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(n, 10) == s {
+		return "int"
+	}
+	if len(s) <= 44 {
+		return "embstr"
+	}
+	return "raw"
+}
+
+// readStrings reads a count of groups of width strings
+func readStrings(r *reader, width int) ([]string, error) {
+	n, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, n*width)
+	for i := 0; i < n*width; i++ {
+		s, err := r.readString()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// readCompact reads a string holding a compact encoding and decodes it
+func readCompact(r *reader, decode func([]byte) ([]string, error)) ([]string, error) {
+	s, err := r.readString()
+	if err != nil {
+		return nil, err
+	}
+	return decode([]byte(s))
+}
+
+// readQuicklist reads the nodes of a quicklist: ziplists, or for the second
+// version listpacks and plain nodes holding a single large element
+func readQuicklist(r *reader, v2 bool) ([]string, error) {
+	n, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	for i := 0; i < n; i++ {
+		container := uint64(2)
+		if v2 {
+			if container, err = r.readUint(); err != nil {
+				return nil, err
+			}
+		}
+		if container == 1 {
+			s, err := r.readString()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, s)
+			continue
+		}
+
+		decode := ziplistEntries
+		if v2 {
+			decode = listpackEntries
+		}
+		entries, err := readCompact(r, decode)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, entries...)
+	}
+	return values, nil
+}
+
+// readZSet reads the members of a skiplist encoded sorted set
+func readZSet(r *reader, binaryScores bool) ([]zmember, error) {
+	n, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]zmember, 0, n)
+	for i := 0; i < n; i++ {
+		member, err := r.readString()
+		if err != nil {
+			return nil, err
+		}
+		var score float64
+		if binaryScores {
+			score, err = r.readBinaryDouble()
+		} else {
+			score, err = r.readDouble()
+		}
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, zmember{member: member, score: score})
+	}
+	sortZMembers(members)
+	return members, nil
+}
+
+// readHashMetadata reads a hash with field expiries (Redis 7.4). Fields
+// have a TTL relative to a minimum expiry, or an absolute one before GA.
+func readHashMetadata(r *reader, relative bool) ([]field, error) {
+	if relative {
+		if _, err := r.readUint64LE(); err != nil {
+			return nil, err
+		}
+	}
+	n, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]field, 0, n)
+	for i := 0; i < n; i++ {
+		if relative {
+			_, err = r.readUint()
+		} else {
+			_, err = r.readUint64LE()
+		}
+		if err != nil {
+			return nil, err
+		}
+		name, err := r.readString()
+		if err != nil {
+			return nil, err
+		}
+		value, err := r.readString()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field{name: name, value: value})
+	}
+	return fields, nil
+}
+
+func toFields(pairs []string) []field {
+	fields := make([]field, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		fields = append(fields, field{name: pairs[i], value: pairs[i+1]})
+	}
+	return fields
+}
+
+func toZMembers(pairs []string) ([]zmember, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errCorrupt
+	}
+	members := make([]zmember, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		score, err := strconv.ParseFloat(pairs[i+1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid score %q", pairs[i+1])
+		}
+		members = append(members, zmember{member: pairs[i], score: score})
+	}
+	sortZMembers(members)
+	return members, nil
+}
+
+// sortZMembers orders members by score then member, as ZRANGE returns them
+func sortZMembers(members []zmember) {
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if a.score != b.score || math.IsNaN(a.score) || math.IsNaN(b.score) {
+			return a.score < b.score
+		}
+		return a.member < b.member
+	})
+}
+
+// Module data is a sequence of typed values ending with moduleOpEOF
+const (
+	moduleOpEOF    = 0
+	moduleOpSInt   = 1
+	moduleOpUInt   = 2
+	moduleOpFloat  = 3
+	moduleOpDouble = 4
+	moduleOpString = 5
+)
+
+// skipModuleData skips the data saved by a module, which only the module
+// itself can interpret
+func skipModuleData(r *reader) error {
+	for {
+		op, err := r.readUint()
+		if err != nil {
+			return err
+		}
+		switch op {
+		case moduleOpEOF:
+			return nil
+		case moduleOpSInt, moduleOpUInt:
+			_, err = r.readUint()
+		case moduleOpFloat:
+			_, err = r.readBytes(4)
+		case moduleOpDouble:
+			_, err = r.readBytes(8)
+		case moduleOpString:
+			_, err = r.readString()
+		default:
+			return fmt.Errorf("invalid module data opcode %d", op)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// moduleTypeName decodes the 9 character type name packed in the high 54
+// bits of a module ID, such as ReJSON-RL
+func moduleTypeName(id uint64) string {
+	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	name := make([]byte, 9)
+	for i := range name {
+		name[i] = charset[id>>(64-6*(uint(i)+1))&63]
+	}
+	return string(name)
+}
@@ -0,0 +1,326 @@
+package rdbfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixtureTests are keys of the files in testdata, saved by real Redis
+// servers, with their value as flatten returns it. A nil value only checks
+// the length.
+var fixtureTests = []struct {
+	file     string
+	db       int
+	key      string
+	typeName string
+	encoding string
+	expireAt int64
+	length   int
+	value    []string
+}{
+	{file: "easily_compressible_string_key", key: strings.Repeat("a", 200), typeName: "string", encoding: "embstr",
+		value: []string{"Key that redis should compress easily"}},
+	{file: "integer_keys", key: "-29477", typeName: "string", encoding: "embstr",
+		value: []string{"Negative 16 bit integer"}},
+	{file: "integer_keys", key: "183358245", typeName: "string", encoding: "embstr",
+		value: []string{"Positive 32 bit integer"}},
+	{file: "keys_with_expiry", key: "expires_ms_precision", typeName: "string", encoding: "embstr", expireAt: 1671963072573,
+		value: []string{"2022-12-25 10:11:12.573 UTC"}},
+	{file: "multiple_databases", db: 2, key: "key_in_second_database", typeName: "string", encoding: "embstr",
+		value: []string{"second"}},
+	{file: "rdb_version_5_with_checksum", key: "longerstring", typeName: "string", encoding: "embstr",
+		value: []string{"thisisalongerstring.idontknowwhatitmeans"}},
+	{file: "parser_filters", key: "n3", typeName: "string", encoding: "int",
+		value: []string{"500001"}},
+	{file: "parser_filters", key: "b5", typeName: "string", encoding: "embstr",
+		value: []string{"\x00\x00\x00\x00\xff"}},
+	{file: "parser_filters", key: "l3", typeName: "list", encoding: "linkedlist", length: 2},
+	{file: "parser_filters", key: "l11", typeName: "list", encoding: "ziplist", length: 3,
+		value: []string{"9999999999", "9999999998", "9999999997"}},
+	{file: "parser_filters", key: "set1", typeName: "set", encoding: "hashtable", length: 4,
+		value: []string{"a", "b", "c", "d"}},
+	{file: "parser_filters", key: "set6", typeName: "set", encoding: "intset", length: 3,
+		value: []string{"9999999997", "9999999998", "9999999999"}},
+	{file: "parser_filters", key: "h1", typeName: "hash", encoding: "hashtable", length: 3},
+	{file: "parser_filters", key: "h3", typeName: "hash", encoding: "zipmap", length: 3,
+		value: []string{"b", "b2", "c", "c2", "d", "d"}},
+	{file: "parser_filters", key: "z4", typeName: "zset", encoding: "ziplist", length: 3,
+		value: []string{"10000000001", "10000000001", "10000000002", "10000000002", "10000000003", "10000000003"}},
+	{file: "regular_set", key: "regular_set", typeName: "set", encoding: "hashtable", length: 6,
+		value: []string{"alpha", "beta", "delta", "gamma", "kappa", "phi"}},
+	{file: "regular_sorted_set", key: "force_sorted_set", typeName: "zset", encoding: "skiplist", length: 500},
+	{file: "rdb_version_8_with_64b_length_and_scores", key: "bigset", typeName: "zset", encoding: "skiplist", length: 1000},
+	{file: "zipmap_that_compresses_easily", key: "zipmap_compresses_easily", typeName: "hash", encoding: "zipmap", length: 3,
+		value: []string{"a", "aa", "aa", "aaaa", "aaaaa", "aaaaaaaaaaaaaa"}},
+	{file: "zipmap_big_len", key: "zimap_doesnt_compress", typeName: "hash", encoding: "zipmap", length: 2,
+		value: []string{"MKD1G6", "2", "YNNXK", "F7TI"}},
+	{file: "ziplist_that_compresses_easily", key: "ziplist_compresses_easily", typeName: "list", encoding: "ziplist", length: 6,
+		value: []string{"aaaaaa", "aaaaaaaaaaaa", "aaaaaaaaaaaaaaaaaa", "aaaaaaaaaaaaaaaaaaaaaaaa",
+			"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}},
+	{file: "ziplist_with_integers", key: "ziplist_with_integers", typeName: "list", encoding: "ziplist", length: 24,
+		value: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "-2", "13", "25", "-61", "63",
+			"16380", "-16000", "65535", "-65523", "4194304", "9223372036854775807"}},
+	{file: "intset_16", key: "intset_16", typeName: "set", encoding: "intset", length: 3,
+		value: []string{"32764", "32765", "32766"}},
+	{file: "intset_32", key: "intset_32", typeName: "set", encoding: "intset", length: 3,
+		value: []string{"2147418108", "2147418109", "2147418110"}},
+	{file: "intset_64", key: "intset_64", typeName: "set", encoding: "intset", length: 3,
+		value: []string{"9223090557583032316", "9223090557583032317", "9223090557583032318"}},
+	{file: "hash_as_ziplist", key: "zipmap_compresses_easily", typeName: "hash", encoding: "ziplist", length: 3,
+		value: []string{"a", "aa", "aa", "aaaa", "aaaaa", "aaaaaaaaaaaaaa"}},
+	{file: "sorted_set_as_ziplist", key: "sorted_set_as_ziplist", typeName: "zset", encoding: "ziplist", length: 3,
+		value: []string{"8b6ba6718a786daefa69438148361901", "1", "cb7a24bb7528f934b841b34c3a73e0c7", "2.37",
+			"523af537946b79c4f8369ed39ba78605", "3.423"}},
+	{file: "quicklist", key: "list", typeName: "list", encoding: "quicklist", length: 6,
+		value: []string{"eb5foapxep8846is", "ns8ra7iy34tpvt", "2dmoobfe4vlmok1f", "bmnctno6rrxjs5yl", "sq1c36x0ixv50jqm", "jfds2extynrj6l"}},
+	{file: "listpack", key: "l", typeName: "list", encoding: "quicklist", length: 9,
+		value: []string{"1", "20000", "aaaa", "4", "16380", "-16380", "1048576", "268435456", "8589934592"}},
+	{file: "listpack", key: "h", typeName: "hash", encoding: "listpack", length: 11,
+		value: []string{"1", "1", "10", "8589934592", "11", "8589934592", "2", "2000", "3", "aaaaaaaaaaaaaaaa", "4", "16380",
+			"5", "-16380", "6", "1048576", "7", "-1048576", "8", "268435456", "9", "-268435456"}},
+	{file: "listpack", key: "z", typeName: "zset", encoding: "listpack", length: 12,
+		value: []string{"11", "-8589934592", "9", "-268435456", "7", "-1048576", "5", "-16380", "12", "-2000", "3", "0",
+			"1", "1", "2", "2000", "4", "16380", "6", "1048576", "8", "268435456", "10", "8589934592"}},
+	{file: "set_listpack", key: "s", typeName: "set", encoding: "listpack", length: 4,
+		value: []string{"a", "b", "c", "d"}},
+	{file: "hash_with_hfe", key: "hash-hfe", typeName: "hash", encoding: "hashtable", length: 8,
+		value: []string{"F1", "V1", "F2", "V2", "F3", "V3", "F4", "V4", "F5", "V5", "F6", "V6", "F7", "V7", "F8", "V8"}},
+	{file: "hash_as_listpack_with_hfe", key: "listpack-hfe", typeName: "hash", encoding: "listpack", length: 3,
+		value: []string{"F1", "V1", "F2", "V2", "F3", "V3"}},
+	{file: "stream_listpacks_1", key: "my", typeName: "stream", encoding: "stream", length: 3,
+		value: []string{"1528466280444-0", "k", "v", "k1", "v1", "1528466284783-0", "a", "b",
+			"1528468321367-0", "key", "value", "key1", "value1"}},
+	{file: "stream_listpacks_1", key: "trim", typeName: "stream", encoding: "stream", length: 118},
+	{file: "stream_listpacks_2", key: "astream", typeName: "stream", encoding: "stream", length: 2,
+		value: []string{"1681085300799-0", "a", "1", "b", "2", "c", "3", "1681085312465-0", "a", "2", "b", "3", "c", "4"}},
+	{file: "stream_listpacks_3", key: "mystream", typeName: "stream", encoding: "stream", length: 1,
+		value: []string{"1704557973866-0", "name", "Sara", "surname", "OConnor"}},
+}
+
+// readFixture reads a file of testdata by name
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name+".rdb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// fixtures returns the name of every file of testdata
+func fixtures(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.rdb"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no fixtures: %v", err)
+	}
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = strings.TrimSuffix(filepath.Base(path), ".rdb")
+	}
+	return names
+}
+
+// parseFixture parses a file of testdata. The epoch as creation time keeps
+// the keys of files that do not record it.
+func parseFixture(t *testing.T, name string) *File {
+	t.Helper()
+	f, err := Parse(readFixture(t, name), time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return f
+}
+
+// flatten returns a value as strings: set members and hash fields sorted,
+// sorted set members followed by their score, stream entry IDs followed by
+// their fields and values
+func flatten(e *entry) []string {
+	var out []string
+	switch v := e.value.(type) {
+	case string:
+		out = []string{v}
+	case []string:
+		out = append(out, v...)
+		if e.typeName == "set" {
+			sort.Strings(out)
+		}
+	case []field:
+		fields := append([]field(nil), v...)
+		sort.Slice(fields, func(i, j int) bool { return fields[i].name < fields[j].name })
+		for _, f := range fields {
+			out = append(out, f.name, f.value)
+		}
+	case []zmember:
+		for _, m := range v {
+			out = append(out, m.member, strconv.FormatFloat(m.score, 'f', -1, 64))
+		}
+	case []streamEntry:
+		for _, s := range v {
+			out = append(out, s.id.String())
+			out = append(out, s.fields...)
+		}
+	}
+	return out
+}
+
+func TestParseFixtures(t *testing.T) {
+	for _, tt := range fixtureTests {
+		f := parseFixture(t, tt.file)
+		db := f.dbs[tt.db]
+		if db == nil || db.entries[tt.key] == nil {
+			t.Errorf("%s: key %q not found in db %d", tt.file, tt.key, tt.db)
+			continue
+		}
+		e := db.entries[tt.key]
+
+		if e.typeName != tt.typeName || e.encoding != tt.encoding {
+			t.Errorf("%s %q: type %s encoding %s, want %s %s", tt.file, tt.key, e.typeName, e.encoding, tt.typeName, tt.encoding)
+		}
+		if e.expireAt != tt.expireAt {
+			t.Errorf("%s %q: expires at %d, want %d", tt.file, tt.key, e.expireAt, tt.expireAt)
+		}
+		if tt.length != 0 && e.length() != tt.length {
+			t.Errorf("%s %q: length %d, want %d", tt.file, tt.key, e.length(), tt.length)
+		}
+		if got := flatten(e); tt.value != nil && !reflect.DeepEqual(got, tt.value) {
+			t.Errorf("%s %q: value %q, want %q", tt.file, tt.key, got, tt.value)
+		}
+	}
+}
+
+func TestParseDatabases(t *testing.T) {
+	tests := []struct {
+		file    string
+		version int
+		keys    map[int]int
+	}{
+		{"empty_database", 3, map[int]int{0: 0}},
+		{"multiple_databases", 3, map[int]int{0: 1, 2: 1}},
+		{"integer_keys", 3, map[int]int{0: 6}},
+		{"parser_filters", 2, map[int]int{0: 43}},
+		{"listpack", 10, map[int]int{0: 3}},
+		{"stream_listpacks_1", 9, map[int]int{0: 5}},
+	}
+	for _, tt := range tests {
+		f := parseFixture(t, tt.file)
+		if f.Version != tt.version {
+			t.Errorf("%s: version %d, want %d", tt.file, f.Version, tt.version)
+		}
+		if keys := f.Keys(); !reflect.DeepEqual(keys, tt.keys) {
+			t.Errorf("%s: keys %v, want %v", tt.file, keys, tt.keys)
+		}
+	}
+}
+
+func TestParseCreated(t *testing.T) {
+	// The snapshot time of the file takes precedence, and keys that had
+	// expired by then are left out
+	f := parseFixture(t, "quicklist")
+	if want := time.Unix(1644050920, 0); !f.Created.Equal(want) {
+		t.Errorf("created %v, want %v", f.Created, want)
+	}
+
+	f, err := Parse(readFixture(t, "keys_with_expiry"), time.Unix(1671963072, 573e6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Expired != 1 || len(f.Keys()) != 1 || f.Keys()[0] != 0 {
+		t.Errorf("expired %d, keys %v, want the key left out", f.Expired, f.Keys())
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"not an RDB file", "REDIT0009\xff"},
+		{"invalid version", "REDIS00x9\xff"},
+		{"no end of file", "REDIS0003"},
+		{"unknown type", "REDIS0003\xfe\x00\x64\x01k\x01v\xff"},
+		{"length past the end", "REDIS0003\xfe\x00\x00\x01k\x3fv\xff"},
+		{"missing checksum", "REDIS0009\xff"},
+		{"wrong checksum", "REDIS0009\xff\x01\x02\x03\x04\x05\x06\x07\x08"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.data), time.Unix(0, 0)); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+
+	// A zero checksum was disabled when saving
+	if _, err := Parse([]byte("REDIS0009\xff\x00\x00\x00\x00\x00\x00\x00\x00"), time.Unix(0, 0)); err != nil {
+		t.Errorf("disabled checksum: %v", err)
+	}
+}
+
+// parseNoPanic parses data, failing the test with the variant of the file
+// that made the parser panic
+func parseNoPanic(t *testing.T, data []byte, variant string) (err error) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("%s: panic: %v", variant, r)
+		}
+	}()
+	_, err = Parse(data, time.Unix(0, 0))
+	return err
+}
+
+func TestParseTruncated(t *testing.T) {
+	for _, name := range fixtures(t) {
+		data := readFixture(t, name)
+		for n := 0; n < len(data); n++ {
+			if err := parseNoPanic(t, data[:n], name+" truncated to "+strconv.Itoa(n)); err == nil {
+				t.Errorf("%s truncated to %d bytes: no error", name, n)
+			}
+		}
+	}
+}
+
+func TestParseCorrupt(t *testing.T) {
+	for _, name := range fixtures(t) {
+		data := readFixture(t, name)
+		if len(data) > 4096 {
+			continue
+		}
+		f := parseFixture(t, name)
+
+		for i := 9; i < len(data); i++ {
+			for _, mask := range []byte{0x01, 0x40, 0x80, 0xc0, 0xff} {
+				corrupt := append([]byte(nil), data...)
+				corrupt[i] ^= mask
+				err := parseNoPanic(t, corrupt, name+" byte "+strconv.Itoa(i)+" flipped")
+				if f.Version >= 5 && i < len(data)-8 && err == nil {
+					t.Errorf("%s byte %d flipped: checksum not verified", name, i)
+				}
+
+				// Without the checksum, the corrupt values reach the decoders
+				if f.Version >= 5 {
+					copy(corrupt[len(corrupt)-8:], make([]byte, 8))
+					parseNoPanic(t, corrupt, name+" byte "+strconv.Itoa(i)+" flipped without checksum")
+				}
+			}
+		}
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	data := readFixture(t, "rdb_version_5_with_checksum")
+	data[len(data)-1] ^= 1
+	if _, err := Parse(data, time.Unix(0, 0)); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("got %v, want a checksum mismatch", err)
+	}
+	if _, err := Parse(data[:len(data)-4], time.Unix(0, 0)); !errors.Is(err, errTruncated) {
+		t.Errorf("got %v, want %v", err, errTruncated)
+	}
+}
@@ -0,0 +1,251 @@
+package rdbfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// errTruncated is returned when the file ends in the middle of a value
+var errTruncated = errors.New("unexpected end of file")
+
+// Special encodings of strings, flagged by the two high bits of a length
+const (
+	encInt8  = 0
+	encInt16 = 1
+	encInt32 = 2
+	encLZF   = 3
+)
+
+// reader decodes the primitives of the RDB format from an in-memory file
+type reader struct {
+	buf []byte
+	pos int
+}
+
+func (r *reader) readByte() (byte, error) {
+	if r.pos >= len(r.buf) {
+		return 0, errTruncated
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b, nil
+}
+
+func (r *reader) readBytes(n int) ([]byte, error) {
+	if n < 0 || n > len(r.buf)-r.pos {
+		return nil, errTruncated
+	}
+	b := r.buf[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+// readUint32LE and readUint64LE read the fixed size little endian fields
+// used for expiry times and binary doubles
+func (r *reader) readUint32LE() (uint32, error) {
+	b, err := r.readBytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *reader) readUint64LE() (uint64, error) {
+	b, err := r.readBytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// readLength reads a length, or the kind of special encoding of a string
+// when encoded is set
+func (r *reader) readLength() (n uint64, encoded bool, err error) {
+	b, err := r.readByte()
+	if err != nil {
+		return 0, false, err
+	}
+	switch b >> 6 {
+	case 0:
+		return uint64(b & 0x3f), false, nil
+	case 1:
+		next, err := r.readByte()
+		if err != nil {
+			return 0, false, err
+		}
+		return uint64(b&0x3f)<<8 | uint64(next), false, nil
+	case 2:
+		switch b {
+		case 0x80:
+			v, err := r.readBytes(4)
+			if err != nil {
+				return 0, false, err
+			}
+			return uint64(binary.BigEndian.Uint32(v)), false, nil
+		case 0x81:
+			v, err := r.readBytes(8)
+			if err != nil {
+				return 0, false, err
+			}
+			return binary.BigEndian.Uint64(v), false, nil
+		}
+		return 0, false, fmt.Errorf("invalid length encoding 0x%02x", b)
+	}
+	return uint64(b & 0x3f), true, nil
+}
+
+// readLen reads a plain length, as an int bounded by the file size so that
+// corrupt lengths do not cause huge allocations
+func (r *reader) readLen() (int, error) {
+	n, encoded, err := r.readLength()
+	if err != nil {
+		return 0, err
+	}
+	if encoded {
+		return 0, errors.New("unexpected string encoding in a length")
+	}
+	if n > uint64(len(r.buf)) {
+		return 0, fmt.Errorf("invalid length %d", n)
+	}
+	return int(n), nil
+}
+
+// readUint reads a length holding a number, such as a stream ID part
+func (r *reader) readUint() (uint64, error) {
+	n, encoded, err := r.readLength()
+	if err == nil && encoded {
+		err = errors.New("unexpected string encoding in a number")
+	}
+	return n, err
+}
+
+// readString reads a string, decoding integer and LZF encodings
+func (r *reader) readString() (string, error) {
+	n, encoded, err := r.readLength()
+	if err != nil {
+		return "", err
+	}
+	if !encoded {
+		if n > uint64(len(r.buf)) {
+			return "", errTruncated
+		}
+		b, err := r.readBytes(int(n))
+		return string(b), err
+	}
+
+	switch n {
+	case encInt8:
+		b, err := r.readBytes(1)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(int8(b[0])), 10), nil
+	case encInt16:
+		b, err := r.readBytes(2)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(int16(binary.LittleEndian.Uint16(b))), 10), nil
+	case encInt32:
+		b, err := r.readBytes(4)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(int64(int32(binary.LittleEndian.Uint32(b))), 10), nil
+	case encLZF:
+		clen, err := r.readLen()
+		if err != nil {
+			return "", err
+		}
+		ulen, err := r.readUint()
+		if err != nil {
+			return "", err
+		}
+		// LZF cannot expand data more than about a hundred times
+		if ulen > uint64(clen)*128+64 {
+			return "", fmt.Errorf("invalid LZF length %d", ulen)
+		}
+		compressed, err := r.readBytes(clen)
+		if err != nil {
+			return "", err
+		}
+		b, err := lzfDecompress(compressed, int(ulen))
+		return string(b), err
+	}
+	return "", fmt.Errorf("invalid string encoding %d", n)
+}
+
+// readDouble reads a score of the original sorted set type: a length byte
+// followed by the score as text, with special lengths for NaN and infinities
+func (r *reader) readDouble() (float64, error) {
+	n, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 253:
+		return math.NaN(), nil
+	case 254:
+		return math.Inf(1), nil
+	case 255:
+		return math.Inf(-1), nil
+	}
+	b, err := r.readBytes(int(n))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(string(b), 64)
+}
+
+// readBinaryDouble reads a little endian IEEE 754 double
+func (r *reader) readBinaryDouble() (float64, error) {
+	v, err := r.readUint64LE()
+	return math.Float64frombits(v), err
+}
+
+// lzfDecompress expands an LZF compressed string of outLen bytes
+func lzfDecompress(in []byte, outLen int) ([]byte, error) {
+	out := make([]byte, 0, outLen)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+		if ctrl < 32 {
+			// Literal run of ctrl+1 bytes
+			n := ctrl + 1
+			if i+n > len(in) {
+				return nil, errTruncated
+			}
+			out = append(out, in[i:i+n]...)
+			i += n
+			continue
+		}
+
+		// Back reference of length+2 bytes
+		length := ctrl >> 5
+		if length == 7 {
+			if i >= len(in) {
+				return nil, errTruncated
+			}
+			length += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, errTruncated
+		}
+		ref := len(out) - (ctrl&0x1f)<<8 - int(in[i]) - 1
+		i++
+		if ref < 0 {
+			return nil, errors.New("invalid LZF back reference")
+		}
+		for j := 0; j < length+2; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if len(out) != outLen {
+		return nil, fmt.Errorf("LZF data expands to %d bytes, expected %d", len(out), outLen)
+	}
+	return out, nil
+}
@@ -0,0 +1,204 @@
+package rdbfile
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server answers the read-only commands of the key browser from the content
+// of an RDB file. Connections are in-memory pipes, so a go-redis client uses
+// it through its Dialer option as if it were a Redis server.
+type Server struct {
+	file *File
+}
+
+// NewServer returns a server of the content of f
+func NewServer(f *File) *Server {
+	return &Server{file: f}
+}
+
+// Dial opens a connection to the server, with the signature of the go-redis
+// Dialer option
+func (s *Server) Dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, server := net.Pipe()
+	c := &conn{server: s, replies: newReplyQueue(server)}
+	go c.serve(server)
+	return client, nil
+}
+
+// conn is the state of a client connection
+type conn struct {
+	server  *Server
+	db      int
+	replies *replyQueue
+}
+
+// serve reads commands until the client closes the connection
+func (c *conn) serve(nc net.Conn) {
+	defer nc.Close()
+	defer c.replies.close()
+
+	r := bufio.NewReader(nc)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrClosedPipe) {
+				c.replies.push(errorReply("ERR Protocol error: " + err.Error()))
+			}
+			return
+		}
+		if len(args) == 0 {
+			continue
+		}
+		reply, quit := c.exec(args)
+		c.replies.push(reply)
+		if quit {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, nil
+	}
+	if line[0] != '*' {
+		// Inline command
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 0 || n > 1<<20 {
+		return nil, fmt.Errorf("invalid multibulk length %q", line)
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" || line[0] != '$' {
+			return nil, fmt.Errorf("expected '$', got %q", line)
+		}
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("invalid bulk length %q", line)
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// replyQueue writes replies from its own goroutine. A pipe has no buffer:
+// replying while the client is still writing a pipeline would block both.
+type replyQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending [][]byte
+	closed  bool
+}
+
+func newReplyQueue(w io.Writer) *replyQueue {
+	q := &replyQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	go q.run(w)
+	return q
+}
+
+func (q *replyQueue) push(reply []byte) {
+	q.mu.Lock()
+	q.pending = append(q.pending, reply)
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+func (q *replyQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.cond.Signal()
+}
+
+func (q *replyQueue) run(w io.Writer) {
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		pending := q.pending
+		q.pending = nil
+		closed := q.closed
+		q.mu.Unlock()
+
+		for _, reply := range pending {
+			if _, err := w.Write(reply); err != nil {
+				return
+			}
+		}
+		if closed {
+			return
+		}
+	}
+}
+
+// Reply encoding
+
+func statusReply(s string) []byte {
+	return []byte("+" + s + "\r\n")
+}
+
+func errorReply(s string) []byte {
+	return []byte("-" + s + "\r\n")
+}
+
+func intReply(n int64) []byte {
+	return []byte(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+func bulkReply(s string) []byte {
+	return []byte("$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n")
+}
+
+func nilReply() []byte {
+	return []byte("$-1\r\n")
+}
+
+func arrayReply(items [][]byte) []byte {
+	b := []byte("*" + strconv.Itoa(len(items)) + "\r\n")
+	for _, item := range items {
+		b = append(b, item...)
+	}
+	return b
+}
+
+func bulkArrayReply(values []string) []byte {
+	items := make([][]byte, len(values))
+	for i, v := range values {
+		items[i] = bulkReply(v)
+	}
+	return arrayReply(items)
+}
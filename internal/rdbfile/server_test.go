@@ -0,0 +1,133 @@
+package rdbfile
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// readBack reads a key with the commands of its type, returning its value
+// as flatten does
+func readBack(ctx context.Context, c *redis.Client, key, typeName string) ([]string, error) {
+	switch typeName {
+	case "string":
+		s, err := c.Get(ctx, key).Result()
+		return []string{s}, err
+	case "list":
+		return c.LRange(ctx, key, 0, -1).Result()
+	case "set":
+		members, err := c.SMembers(ctx, key).Result()
+		sort.Strings(members)
+		return members, err
+	case "hash":
+		fields, err := c.HGetAll(ctx, key).Result()
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var out []string
+		for _, name := range names {
+			out = append(out, name, fields[name])
+		}
+		return out, err
+	case "zset":
+		members, err := c.ZRangeWithScores(ctx, key, 0, -1).Result()
+		var out []string
+		for _, m := range members {
+			out = append(out, m.Member.(string), strconv.FormatFloat(m.Score, 'f', -1, 64))
+		}
+		return out, err
+	case "stream":
+		// XRANGE through Do keeps repeated fields, unlike XMessage
+		reply, err := c.Do(ctx, "xrange", key, "-", "+").Slice()
+		var out []string
+		for _, item := range reply {
+			entry, ok := item.([]interface{})
+			if !ok || len(entry) != 2 {
+				return nil, errors.New("invalid XRANGE reply")
+			}
+			fields, _ := entry[1].([]interface{})
+			out = append(out, entry[0].(string))
+			for _, f := range fields {
+				out = append(out, f.(string))
+			}
+		}
+		return out, err
+	}
+	return nil, errors.New("unknown type " + typeName)
+}
+
+// serve returns a client of a server of f, using database db
+func serve(f *File, db int) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:   "testdata",
+		DB:     db,
+		Dialer: NewServer(f).Dial,
+	})
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	for _, tt := range fixtureTests {
+		f := parseFixture(t, tt.file)
+		e := f.dbs[tt.db].entries[tt.key]
+		c := serve(f, tt.db)
+
+		if typeName, err := c.Type(ctx, tt.key).Result(); err != nil || typeName != tt.typeName {
+			t.Errorf("%s %q: TYPE %q %v, want %q", tt.file, tt.key, typeName, err, tt.typeName)
+		}
+		if encoding, err := c.ObjectEncoding(ctx, tt.key).Result(); err != nil || encoding != tt.encoding {
+			t.Errorf("%s %q: OBJECT ENCODING %q %v, want %q", tt.file, tt.key, encoding, err, tt.encoding)
+		}
+		value, err := readBack(ctx, c, tt.key, tt.typeName)
+		if err != nil {
+			t.Errorf("%s %q: %v", tt.file, tt.key, err)
+		} else if want := flatten(e); !reflect.DeepEqual(value, want) {
+			t.Errorf("%s %q: read %q, want %q", tt.file, tt.key, value, want)
+		}
+		if payload, err := c.Dump(ctx, tt.key).Result(); err != nil || payload != string(dumpPayload(e, f.Version)) {
+			t.Errorf("%s %q: DUMP %q %v", tt.file, tt.key, payload, err)
+		}
+		c.Close()
+	}
+}
+
+func TestServerScan(t *testing.T) {
+	ctx := context.Background()
+	f := parseFixture(t, "parser_filters")
+	c := serve(f, 0)
+	defer c.Close()
+
+	var keys []string
+	var cursor uint64
+	for {
+		page, next, err := c.Scan(ctx, cursor, "l*", 5).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, page...)
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	want := []string{"l1", "l10", "l11", "l12", "l2", "l3", "l4", "l5", "l6", "l7", "l8", "l9"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("SCAN returned %q, want %q", keys, want)
+	}
+
+	if _, err := c.Get(ctx, "l1").Result(); err == nil || err.Error() != errWrongType {
+		t.Errorf("GET of a list: %v, want %s", err, errWrongType)
+	}
+	if _, err := c.Get(ctx, "missing").Result(); err != redis.Nil {
+		t.Errorf("GET of a missing key: %v, want %v", err, redis.Nil)
+	}
+	if err := c.Set(ctx, "k1", "v", 0).Err(); err == nil {
+		t.Error("SET succeeded on a read-only file")
+	}
+}
@@ -0,0 +1,207 @@
+package rdbfile
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// Flags of the entries of a stream listpack
+const (
+	streamItemDeleted    = 1
+	streamItemSameFields = 2
+)
+
+// readStream reads a stream: its entries in listpacks keyed by master ID,
+// then its metadata and consumer groups, which are skipped
+func readStream(r *reader, t byte) ([]streamEntry, error) {
+	nodes, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	var entries []streamEntry
+	for i := 0; i < nodes; i++ {
+		key, err := r.readString()
+		if err != nil {
+			return nil, err
+		}
+		if len(key) != 16 {
+			return nil, errCorrupt
+		}
+		master := streamID{
+			ms:  binary.BigEndian.Uint64([]byte(key[:8])),
+			seq: binary.BigEndian.Uint64([]byte(key[8:])),
+		}
+		lp, err := readCompact(r, listpackEntries)
+		if err != nil {
+			return nil, err
+		}
+		if entries, err = appendStreamEntries(entries, master, lp); err != nil {
+			return nil, err
+		}
+	}
+
+	// Length and last ID, then for newer versions the first ID, the
+	// maximal deleted ID and the number of entries ever added
+	metadata := 3
+	if t >= typeStreamListpacks2 {
+		metadata += 5
+	}
+	for i := 0; i < metadata; i++ {
+		if _, err := r.readUint(); err != nil {
+			return nil, err
+		}
+	}
+
+	groups, err := r.readLen()
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < groups; i++ {
+		if err := skipConsumerGroup(r, t); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// appendStreamEntries decodes the entries of a stream listpack. It starts
+// with the master entry: the entry count, the deleted count and the master
+// fields. Each entry then holds its flags, its ID as a difference from the
+// master ID, its fields unless they are the master fields, its values and
+// the number of listpack elements it used.
+func appendStreamEntries(entries []streamEntry, master streamID, lp []string) ([]streamEntry, error) {
+	p := &streamParser{lp: lp}
+	p.next() // count
+	p.next() // deleted
+	n := p.nextInt()
+	if n < 0 || n > int64(len(lp)) {
+		return nil, errCorrupt
+	}
+	masterFields := make([]string, n)
+	for i := range masterFields {
+		masterFields[i] = p.next()
+	}
+	p.next() // master entry terminator
+
+	for p.err == nil && p.pos < len(lp) {
+		flags := p.nextInt()
+		// The sequence difference is negative for entries of a later millisecond
+		id := streamID{ms: master.ms + uint64(p.nextInt())}
+		id.seq = uint64(int64(master.seq) + p.nextInt())
+
+		var fields []string
+		if flags&streamItemSameFields != 0 {
+			fields = make([]string, 0, 2*len(masterFields))
+			for _, name := range masterFields {
+				fields = append(fields, name, p.next())
+			}
+		} else {
+			n := p.nextInt()
+			if n < 0 || n > int64(len(lp)) {
+				return nil, errCorrupt
+			}
+			fields = make([]string, 0, 2*n)
+			for j := int64(0); j < n; j++ {
+				fields = append(fields, p.next(), p.next())
+			}
+		}
+		p.next() // lp-count
+
+		if flags&streamItemDeleted == 0 {
+			entries = append(entries, streamEntry{id: id, fields: fields})
+		}
+	}
+	return entries, p.err
+}
+
+// streamParser walks the elements of a stream listpack, remembering the
+// first error
+type streamParser struct {
+	lp  []string
+	pos int
+	err error
+}
+
+func (p *streamParser) next() string {
+	if p.err != nil {
+		return ""
+	}
+	if p.pos >= len(p.lp) {
+		p.err = errCorrupt
+		return ""
+	}
+	s := p.lp[p.pos]
+	p.pos++
+	return s
+}
+
+func (p *streamParser) nextInt() int64 {
+	s := p.next()
+	if p.err != nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		p.err = errCorrupt
+	}
+	return n
+}
+
+// skipConsumerGroup skips a consumer group with its pending entries and
+// consumers
+func skipConsumerGroup(r *reader, t byte) error {
+	if _, err := r.readString(); err != nil {
+		return err
+	}
+	// Last delivered ID, and the entries read count in newer versions
+	ids := 2
+	if t >= typeStreamListpacks2 {
+		ids++
+	}
+	for i := 0; i < ids; i++ {
+		if _, err := r.readUint(); err != nil {
+			return err
+		}
+	}
+
+	// Pending entries: raw ID, delivery time and delivery count
+	pending, err := r.readLen()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < pending; i++ {
+		if _, err := r.readBytes(16 + 8); err != nil {
+			return err
+		}
+		if _, err := r.readUint(); err != nil {
+			return err
+		}
+	}
+
+	// Consumers: name, seen time, active time in the third version and the
+	// raw IDs of their pending entries
+	consumers, err := r.readLen()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < consumers; i++ {
+		if _, err := r.readString(); err != nil {
+			return err
+		}
+		times := 8
+		if t >= typeStreamListpacks3 {
+			times += 8
+		}
+		if _, err := r.readBytes(times); err != nil {
+			return err
+		}
+		n, err := r.readLen()
+		if err != nil {
+			return err
+		}
+		if _, err := r.readBytes(16 * n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// aclLogCount is the number of ACL LOG entries fetched from each node
+const aclLogCount = 50
+
+// ACLUser is a user as described by ACL GETUSER, with its rule from ACL LIST
+type ACLUser struct {
+	Name      string
+	Rule      string // the ACL LIST line
+	Flags     []string
+	Passwords int // number of password hashes
+	Commands  string
+	Keys      string
+	Channels  string
+	Selectors []string // Redis 7 selectors, one rule string each
+}
+
+// Enabled reports whether the user can authenticate
+func (u ACLUser) Enabled() bool {
+	return u.hasFlag("on")
+}
+
+// NoPass reports whether the user accepts any password
+func (u ACLUser) NoPass() bool {
+	return u.hasFlag("nopass")
+}
+
+func (u ACLUser) hasFlag(flag string) bool {
+	for _, f := range u.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// ACLLogEntry is a denied command reported by ACL LOG
+type ACLLogEntry struct {
+	Node       string
+	Count      int64
+	Reason     string // command, key, channel or auth
+	Context    string // toplevel, multi, lua or module
+	Object     string // the denied command, key or channel
+	Username   string
+	AgeSeconds float64
+	ClientAddr string
+	ClientName string
+}
+
+// ACLStatus is the ACL configuration seen by the current connection
+type ACLStatus struct {
+	WhoAmI string
+	Users  []ACLUser
+	// UsersErr and LogErr are set when the current user may not run ACL
+	// LIST, ACL GETUSER or ACL LOG: WHOAMI is still reported
+	UsersErr error
+	Log      []ACLLogEntry
+	LogErr   error
+}
+
+// IsNoPerm reports whether err is an ACL permission error
+func IsNoPerm(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "NOPERM")
+}
+
+// GetACL reads the current user, the users with their permissions and the
+// ACL log. ACLs are per node: in cluster mode users are read from the master
+// with the lowest address and the log is gathered from every master.
+func GetACL(rdb redis.UniversalClient) (*ACLStatus, error) {
+	ctx := context.TODO()
+
+	whoami, err := rdb.Do(ctx, "acl", "whoami").Text()
+	if err != nil {
+		var redisErr redis.Error
+		if errors.As(err, &redisErr) && !IsNoPerm(err) {
+			return nil, fmt.Errorf("ACLs need Redis 6 or later: %w", err)
+		}
+		return nil, err
+	}
+
+	status := &ACLStatus{WhoAmI: whoami}
+	users := rdb
+	var cluster *redis.ClusterClient
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		cluster = rdb
+	case *failoverRouter:
+		cluster = rdb.ClusterClient
+	}
+	if cluster != nil {
+		// Keyless commands would each go to a random node
+		var master *redis.Client
+		if master, status.UsersErr = firstMaster(ctx, cluster); master != nil {
+			users = master
+		}
+	}
+	if status.UsersErr == nil {
+		status.Users, status.UsersErr = getACLUsers(ctx, users)
+	}
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		var mu sync.Mutex
+		status.LogErr = rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			entries, err := getACLLog(ctx, client, client.Options().Addr)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			status.Log = append(status.Log, entries...)
+			mu.Unlock()
+			return nil
+		})
+	default:
+		status.Log, status.LogErr = getACLLog(ctx, rdb, nodeAddr(rdb))
+	}
+	sort.SliceStable(status.Log, func(i, j int) bool { return status.Log[i].AgeSeconds < status.Log[j].AgeSeconds })
+
+	return status, nil
+}
+
+// getACLUsers runs ACL LIST, then ACL GETUSER for every user in one pipeline.
+// rdb must send them all to the same node.
+func getACLUsers(ctx context.Context, rdb redis.UniversalClient) ([]ACLUser, error) {
+	rules, err := rdb.Do(ctx, "acl", "list").StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]ACLUser, 0, len(rules))
+	for _, rule := range rules {
+		// "user <name> <rules...>"
+		fields := strings.Fields(rule)
+		if len(fields) < 2 {
+			continue
+		}
+		users = append(users, ACLUser{Name: fields[1], Rule: rule})
+	}
+
+	pipe := rdb.Pipeline()
+	cmds := make([]*redis.Cmd, len(users))
+	for i, u := range users {
+		cmds[i] = redis.NewCmd(ctx, "acl", "getuser", u.Name)
+		_ = pipe.Process(ctx, cmds[i])
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		if cmds[i].Err() == nil {
+			parseACLUser(&users[i], cmds[i].Val())
+		}
+	}
+	return users, nil
+}
+
+// parseACLUser fills a user from an ACL GETUSER reply. Redis 6 replies with
+// lists for keys and channels where Redis 7 replies with rule strings.
+func parseACLUser(u *ACLUser, reply interface{}) {
+	attrs := pairs(reply)
+	u.Flags = toStrings(attrs["flags"])
+	u.Passwords = len(toStrings(attrs["passwords"]))
+	u.Commands, _ = attrs["commands"].(string)
+	u.Keys = strings.Join(toStrings(attrs["keys"]), " ")
+	u.Channels = strings.Join(toStrings(attrs["channels"]), " ")
+
+	selectors, _ := attrs["selectors"].([]interface{})
+	for _, s := range selectors {
+		sel := pairs(s)
+		var parts []string
+		for _, name := range []string{"commands", "keys", "channels"} {
+			if v := strings.Join(toStrings(sel[name]), " "); v != "" {
+				parts = append(parts, v)
+			}
+		}
+		u.Selectors = append(u.Selectors, "("+strings.Join(parts, " ")+")")
+	}
+}
+
+// getACLLog runs ACL LOG on a single node
+func getACLLog(ctx context.Context, rdb redis.UniversalClient, node string) ([]ACLLogEntry, error) {
+	reply, err := rdb.Do(ctx, "acl", "log", aclLogCount).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]ACLLogEntry, 0, len(reply))
+	for _, r := range reply {
+		attrs := pairs(r)
+		entry := ACLLogEntry{
+			Node:     node,
+			Count:    toInt64(attrs["count"]),
+			Reason:   toString(attrs["reason"]),
+			Context:  toString(attrs["context"]),
+			Object:   toString(attrs["object"]),
+			Username: toString(attrs["username"]),
+		}
+		entry.AgeSeconds, _ = strconv.ParseFloat(toString(attrs["age-seconds"]), 64)
+
+		// client-info is formatted like a CLIENT LIST line
+		if clients := parseClientList(toString(attrs["client-info"]), node); len(clients) > 0 {
+			entry.ClientAddr = clients[0].Addr
+			entry.ClientName = clients[0].Name
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// toStrings converts a reply holding a string or a list of strings
+func toStrings(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []interface{}:
+		s := make([]string, 0, len(v))
+		for _, item := range v {
+			s = append(s, toString(item))
+		}
+		return s
+	default:
+		return nil
+	}
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func toInt64(v interface{}) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
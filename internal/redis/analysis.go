@@ -0,0 +1,203 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// KeyStat holds the metadata gathered for a key during a keyspace analysis
+type KeyStat struct {
+	Key        string
+	Type       string
+	Bytes      int64 // MEMORY USAGE, 0 when unavailable
+	Elements   int64 // string length or number of elements
+	TTLSeconds int64 // -1 when the key does not expire
+}
+
+// ScanStatsOptions configures ScanKeyStats
+type ScanStatsOptions struct {
+	Match     string        // SCAN pattern, empty for every key
+	Scope     KeyScope      // cluster node and slot range, zero for every key
+	Limit     int           // stop after this many keys, 0 for a full scan
+	BatchSize int           // keys per pipeline
+	Pause     time.Duration // sleep between SCAN pages to throttle the load
+	Memory    bool          // run MEMORY USAGE
+	Elements  bool          // run the type-specific cardinality command
+}
+
+// ScanKeyStats scans the keyspace with GetKeys and gathers TYPE, TTL and,
+// optionally, MEMORY USAGE and element counts in pipelined batches. fn is
+// called with each batch; returning an error stops the scan.
+func ScanKeyStats(ctx context.Context, rdb redis.UniversalClient, opts ScanStatsOptions, fn func([]KeyStat) error) error {
+	return scanBatches(ctx, rdb, opts, func(keys []string) error {
+		stats, err := keyStats(ctx, rdb, keys, opts)
+		if err != nil {
+			return err
+		}
+		return fn(stats)
+	})
+}
+
+// errScanLimit stops a scan once enough keys were found
+var errScanLimit = errors.New("scan limit reached")
+
+// scanBatches scans the keys of opts.Match and opts.Scope and calls fn with
+// batches of opts.BatchSize keys, stopping after opts.Limit keys. Keys are
+// streamed from SCAN, which is throttled by opts.Pause between pages, and
+// cancelling ctx stops the scan itself. The batch is reused: fn must not
+// keep it. Returning an error from fn stops the scan.
+func scanBatches(ctx context.Context, rdb redis.UniversalClient, opts ScanStatsOptions, fn func([]string) error) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	batch := make([]string, 0, opts.BatchSize)
+	total := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err := scanPages(ctx, rdb, opts.Scope, 0, opts.Match, int64(opts.BatchSize), func(keys []string) error {
+		for _, key := range keys {
+			batch = append(batch, key)
+			total++
+			if len(batch) == opts.BatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+			if opts.Limit > 0 && total >= opts.Limit {
+				return errScanLimit
+			}
+		}
+
+		if opts.Pause > 0 {
+			select {
+			case <-time.After(opts.Pause):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errScanLimit {
+		return err
+	}
+
+	return flush()
+}
+
+// keyStats gathers the statistics of a batch of keys in at most two pipelines.
+// Keys deleted since they were scanned are left out.
+func keyStats(ctx context.Context, rdb redis.UniversalClient, keys []string, opts ScanStatsOptions) ([]KeyStat, error) {
+	pipe := rdb.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+	memCmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		typeCmds[i] = pipe.Type(ctx, key)
+		ttlCmds[i] = pipe.PTTL(ctx, key)
+		if opts.Memory {
+			memCmds[i] = pipe.MemoryUsage(ctx, key)
+		}
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return nil, err
+	}
+
+	stats := make([]KeyStat, 0, len(keys))
+	for i, key := range keys {
+		keyType := typeCmds[i].Val()
+		if keyType == "" || keyType == "none" {
+			continue
+		}
+
+		stat := KeyStat{Key: key, Type: keyType, TTLSeconds: -1}
+		if ttl := ttlCmds[i].Val(); ttl > 0 {
+			stat.TTLSeconds = int64(ttl / time.Second)
+		}
+		if opts.Memory {
+			stat.Bytes = memCmds[i].Val()
+		}
+		stats = append(stats, stat)
+	}
+
+	if !opts.Elements || len(stats) == 0 {
+		return stats, nil
+	}
+
+	pipe = rdb.Pipeline()
+	countCmds := make([]*redis.IntCmd, len(stats))
+	for i, stat := range stats {
+		countCmds[i] = queueElementCount(ctx, pipe, stat.Key, stat.Type)
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return nil, err
+	}
+	for i, cmd := range countCmds {
+		if cmd != nil {
+			stats[i].Elements = cmd.Val()
+		}
+	}
+
+	return stats, nil
+}
+
+// queueElementCount queues the type-specific cardinality command of a key:
+// the length of a string or the number of elements of a collection. It
+// returns nil for unknown types.
+func queueElementCount(ctx context.Context, pipe redis.Pipeliner, key, keyType string) *redis.IntCmd {
+	switch keyType {
+	case "string":
+		return pipe.StrLen(ctx, key)
+	case "list":
+		return pipe.LLen(ctx, key)
+	case "set":
+		return pipe.SCard(ctx, key)
+	case "zset":
+		return pipe.ZCard(ctx, key)
+	case "hash":
+		return pipe.HLen(ctx, key)
+	case "stream":
+		return pipe.XLen(ctx, key)
+	}
+	return nil
+}
+
+// ElementsLabel names what the element count of a type measures
+func ElementsLabel(keyType string) string {
+	switch keyType {
+	case "string":
+		return "length"
+	case "hash":
+		return "fields"
+	case "stream":
+		return "entries"
+	default:
+		return "members"
+	}
+}
+
+// execPipeline runs a pipeline, ignoring per-command server errors (such as
+// MEMORY USAGE on old servers or a key that expired) which are checked by the
+// caller through each command's value
+func execPipeline(ctx context.Context, pipe redis.Pipeliner) error {
+	_, err := pipe.Exec(ctx)
+	if err == nil || err == redis.Nil {
+		return nil
+	}
+	if _, ok := err.(redis.Error); ok {
+		return nil
+	}
+	return err
+}
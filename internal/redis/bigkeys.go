@@ -0,0 +1,89 @@
+package redis
+
+import "sort"
+
+// TypeSummary totals the keys of one type
+type TypeSummary struct {
+	Type     string
+	Keys     int64
+	Bytes    int64
+	Elements int64
+}
+
+// BigKeysReport collects, per type, the biggest keys by memory and by
+// element count, like redis-cli --bigkeys and --memkeys
+type BigKeysReport struct {
+	Scanned     int64
+	TopBytes    map[string][]KeyStat
+	TopElements map[string][]KeyStat
+
+	topN   int
+	byType map[string]*TypeSummary
+}
+
+// NewBigKeysReport creates a report keeping the topN biggest keys per type
+func NewBigKeysReport(topN int) *BigKeysReport {
+	return &BigKeysReport{
+		TopBytes:    make(map[string][]KeyStat),
+		TopElements: make(map[string][]KeyStat),
+		topN:        topN,
+		byType:      make(map[string]*TypeSummary),
+	}
+}
+
+// Add accounts for a batch of key statistics
+func (r *BigKeysReport) Add(stats []KeyStat) {
+	for _, s := range stats {
+		r.Scanned++
+
+		summary, ok := r.byType[s.Type]
+		if !ok {
+			summary = &TypeSummary{Type: s.Type}
+			r.byType[s.Type] = summary
+		}
+		summary.Keys++
+		summary.Bytes += s.Bytes
+		summary.Elements += s.Elements
+
+		r.TopBytes[s.Type] = insertTop(r.TopBytes[s.Type], s, r.topN, func(a, b KeyStat) bool {
+			return a.Bytes > b.Bytes
+		})
+		r.TopElements[s.Type] = insertTop(r.TopElements[s.Type], s, r.topN, func(a, b KeyStat) bool {
+			return a.Elements > b.Elements
+		})
+	}
+}
+
+// Summaries returns the per-type totals, largest memory first
+func (r *BigKeysReport) Summaries() []TypeSummary {
+	summaries := make([]TypeSummary, 0, len(r.byType))
+	for _, s := range r.byType {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Bytes != summaries[j].Bytes {
+			return summaries[i].Bytes > summaries[j].Bytes
+		}
+		return summaries[i].Type < summaries[j].Type
+	})
+	return summaries
+}
+
+// insertTop inserts s into list, which is kept ordered by before and at most n long
+func insertTop(list []KeyStat, s KeyStat, n int, before func(a, b KeyStat) bool) []KeyStat {
+	if n <= 0 {
+		return list
+	}
+	if len(list) == n && !before(s, list[n-1]) {
+		return list
+	}
+
+	i := sort.Search(len(list), func(i int) bool { return before(s, list[i]) })
+	list = append(list, KeyStat{})
+	copy(list[i+1:], list[i:])
+	list[i] = s
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -4,7 +4,9 @@ package redis
 import (
 	"context"
 	"fmt"
-	"os"
+	"sort"
+	"strconv"
+	"sync"
 	"sync/atomic"
 
 	"github.com/go-redis/redis/v8"
@@ -14,8 +16,12 @@ import (
 type DatabaseStats struct {
 	DB         int
 	Keys       int64
+	Expires    int64
 	AvgTTL     string
+	SampledTTL string // average TTL of randomly sampled keys, empty when not sampled
 	SampleSize int
+
+	avgTTLMillis int64
 }
 
 // ServerStats contains overall Redis server statistics
@@ -23,6 +29,7 @@ type ServerStats struct {
 	Version                string
 	UptimeSeconds          int64
 	UsedMemory             string
+	UsedMemoryBytes        int64
 	UsedMemoryPeak         string
 	MemFragmentationRatio  float64
 	ConnectedClients       int64
@@ -30,6 +37,36 @@ type ServerStats struct {
 	OpsPerSec              int64
 	EvictedKeys            int64
 	ExpiredKeys            int64
+	KeyspaceHits           int64
+	KeyspaceMisses         int64
+}
+
+// InfoField is a single key/value pair from INFO
+type InfoField struct {
+	Key   string
+	Value string
+}
+
+// InfoSection is a named group of INFO fields, such as "Server" or "Keyspace"
+type InfoSection struct {
+	Name   string
+	Fields []InfoField
+}
+
+// GetInfo retrieves every INFO section
+func GetInfo(rdb redis.UniversalClient) ([]InfoSection, error) {
+	ctx := context.TODO()
+
+	info, err := rdb.Info(ctx, "everything").Result()
+	if err != nil || info == "" {
+		// Servers older than 2.6 do not know the "everything" section
+		info, err = rdb.Info(ctx).Result()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parseInfoSections(info), nil
 }
 
 // GetServerStats retrieves server-level statistics from Redis INFO command
@@ -47,16 +84,6 @@ func GetServerStats(rdb redis.UniversalClient) (*ServerStats, error) {
 	// Parse INFO output
 	lines := parseInfo(info)
 
-	// DEBUG: Write parsed info to file for inspection
-	if debugFile, err := os.Create("/tmp/redis-viewer-debug.txt"); err == nil {
-		fmt.Fprintf(debugFile, "=== Raw INFO Output ===\n%s\n\n", info)
-		fmt.Fprintf(debugFile, "=== Parsed Lines ===\n")
-		for k, v := range lines {
-			fmt.Fprintf(debugFile, "%s: %s\n", k, v)
-		}
-		debugFile.Close()
-	}
-
 	// Server info
 	if val, ok := lines["redis_version"]; ok {
 		stats.Version = val
@@ -66,17 +93,20 @@ func GetServerStats(rdb redis.UniversalClient) (*ServerStats, error) {
 	}
 
 	// Memory info
+	if val, ok := lines["used_memory"]; ok {
+		stats.UsedMemoryBytes = parseInt64(val)
+	}
 	if val, ok := lines["used_memory_human"]; ok && val != "" {
 		stats.UsedMemory = val
 	} else if val, ok := lines["used_memory"]; ok {
 		// Fallback: format bytes to human readable
-		stats.UsedMemory = formatBytes(parseInt64(val))
+		stats.UsedMemory = FormatBytes(parseInt64(val))
 	}
 	if val, ok := lines["used_memory_peak_human"]; ok && val != "" {
 		stats.UsedMemoryPeak = val
 	} else if val, ok := lines["used_memory_peak"]; ok {
 		// Fallback: format bytes to human readable
-		stats.UsedMemoryPeak = formatBytes(parseInt64(val))
+		stats.UsedMemoryPeak = FormatBytes(parseInt64(val))
 	}
 	if val, ok := lines["mem_fragmentation_ratio"]; ok {
 		stats.MemFragmentationRatio = parseFloat64(val)
@@ -100,52 +130,112 @@ func GetServerStats(rdb redis.UniversalClient) (*ServerStats, error) {
 	if val, ok := lines["expired_keys"]; ok {
 		stats.ExpiredKeys = parseInt64(val)
 	}
+	if val, ok := lines["keyspace_hits"]; ok {
+		stats.KeyspaceHits = parseInt64(val)
+	}
+	if val, ok := lines["keyspace_misses"]; ok {
+		stats.KeyspaceMisses = parseInt64(val)
+	}
 
 	return stats, nil
 }
 
-// GetDatabaseStats retrieves statistics for a specific database
-func GetDatabaseStats(rdb redis.UniversalClient, db int, sampleSize int) (*DatabaseStats, error) {
+// GetDatabaseCount returns the number of logical databases configured on the
+// server. Cluster mode only supports database 0.
+func GetDatabaseCount(rdb redis.UniversalClient) (int, error) {
 	ctx := context.TODO()
 
-	stats := &DatabaseStats{
-		DB:         db,
-		SampleSize: sampleSize,
+	if _, ok := rdb.(*redis.ClusterClient); ok {
+		return 1, nil
+	}
+
+	res, err := rdb.ConfigGet(ctx, "databases").Result()
+	if err != nil {
+		return 0, err
+	}
+	if len(res) < 2 {
+		return 0, fmt.Errorf("unexpected CONFIG GET databases reply: %v", res)
 	}
 
-	// Get key count for current database
+	count, err := strconv.Atoi(fmt.Sprint(res[1]))
+	if err != nil {
+		return 0, fmt.Errorf("parse databases count: %w", err)
+	}
+	return count, nil
+}
+
+// GetDatabaseStats retrieves key statistics for every non-empty database from
+// INFO keyspace. When sampleSize is positive, the TTL of sampleSize random keys
+// of the database the client is connected to (currentDB) is also averaged.
+func GetDatabaseStats(rdb redis.UniversalClient, currentDB int, sampleSize int) ([]*DatabaseStats, error) {
+	ctx := context.TODO()
+
+	var byDB map[int]*DatabaseStats
+
 	switch rdb := rdb.(type) {
 	case *redis.ClusterClient:
-		var totalKeys int64
+		// Sum the keyspace of every master
+		var mu sync.Mutex
+		byDB = make(map[int]*DatabaseStats)
 		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
-			size, err := client.DBSize(ctx).Result()
+			info, err := client.Info(ctx, "keyspace").Result()
 			if err != nil {
 				return err
 			}
-			atomic.AddInt64(&totalKeys, size)
+			mu.Lock()
+			defer mu.Unlock()
+			for db, stats := range parseKeyspace(info) {
+				total, ok := byDB[db]
+				if !ok {
+					byDB[db] = stats
+					continue
+				}
+				// avg_ttl is weighted by the number of keys with an expiry
+				if expires := total.Expires + stats.Expires; expires > 0 {
+					total.avgTTLMillis = (total.avgTTLMillis*total.Expires + stats.avgTTLMillis*stats.Expires) / expires
+				}
+				total.Keys += stats.Keys
+				total.Expires += stats.Expires
+			}
 			return nil
 		})
 		if err != nil {
 			return nil, err
 		}
-		stats.Keys = totalKeys
 	default:
-		size, err := rdb.DBSize(ctx).Result()
+		info, err := rdb.Info(ctx, "keyspace").Result()
 		if err != nil {
 			return nil, err
 		}
-		stats.Keys = size
+		byDB = parseKeyspace(info)
 	}
 
-	// Calculate average TTL by sampling keys
-	if stats.Keys > 0 && sampleSize > 0 {
-		avgTTL, err := calculateAverageTTL(rdb, sampleSize)
-		if err == nil {
-			stats.AvgTTL = avgTTL
+	dbs := make([]*DatabaseStats, 0, len(byDB))
+	for _, stats := range byDB {
+		if stats.Expires == 0 {
+			stats.AvgTTL = "No TTL"
+		} else {
+			stats.AvgTTL = FormatSeconds(stats.avgTTLMillis / 1000)
 		}
+		dbs = append(dbs, stats)
 	}
+	sort.Slice(dbs, func(i, j int) bool { return dbs[i].DB < dbs[j].DB })
 
-	return stats, nil
+	// Sample only when extra detail is requested, it costs two round trips per key
+	if sampleSize > 0 {
+		for _, stats := range dbs {
+			if stats.DB != currentDB {
+				continue
+			}
+			sampled, err := calculateAverageTTL(rdb, sampleSize)
+			if err == nil {
+				stats.SampledTTL = sampled
+				stats.SampleSize = sampleSize
+			}
+		}
+	}
+
+	return dbs, nil
 }
 
 // calculateAverageTTL samples random keys and calculates their average TTL
@@ -199,5 +289,5 @@ func calculateAverageTTL(rdb redis.UniversalClient, sampleSize int) (string, err
 	}
 
 	avgSeconds := totalTTL / keysWithTTL
-	return formatSeconds(avgSeconds), nil
+	return FormatSeconds(avgSeconds), nil
 }
@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ClientInfo is a connection reported by CLIENT LIST
+type ClientInfo struct {
+	Node        string // node the client is connected to
+	ID          int64
+	Addr        string
+	Name        string
+	User        string
+	AgeSeconds  int64
+	IdleSeconds int64
+	DB          int
+	Flags       string
+	Cmd         string
+	Memory      int64 // tot-mem, or the output buffer size on servers older than 6.0
+}
+
+// IP returns the source IP of the client address
+func (c ClientInfo) IP() string {
+	for i := len(c.Addr) - 1; i >= 0; i-- {
+		if c.Addr[i] == ':' {
+			return c.Addr[:i]
+		}
+	}
+	return c.Addr
+}
+
+// GetClients retrieves the connected clients. In cluster mode every master
+// is queried.
+func GetClients(rdb redis.UniversalClient) ([]ClientInfo, error) {
+	ctx := context.TODO()
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		var (
+			clients []ClientInfo
+			mu      sync.Mutex
+		)
+		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			list, err := client.ClientList(ctx).Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			clients = append(clients, parseClientList(list, client.Options().Addr)...)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return clients, nil
+	default:
+		list, err := rdb.ClientList(ctx).Result()
+		if err != nil {
+			return nil, err
+		}
+		return parseClientList(list, nodeAddr(rdb)), nil
+	}
+}
+
+// KillClient closes the connection with the given client ID on node. Node is
+// ignored outside cluster mode.
+func KillClient(rdb redis.UniversalClient, node string, id int64) error {
+	ctx := context.TODO()
+	idArg := strconv.FormatInt(id, 10)
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		var found int32
+		var killed int64
+		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			if client.Options().Addr != node {
+				return nil
+			}
+			atomic.StoreInt32(&found, 1)
+			n, err := client.ClientKillByFilter(ctx, "ID", idArg).Result()
+			atomic.AddInt64(&killed, n)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if atomic.LoadInt32(&found) == 0 {
+			return fmt.Errorf("node %s is not a cluster master", node)
+		}
+		if atomic.LoadInt64(&killed) == 0 {
+			return fmt.Errorf("client %d is no longer connected", id)
+		}
+		return nil
+	default:
+		n, err := rdb.ClientKillByFilter(ctx, "ID", idArg).Result()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("client %d is no longer connected", id)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,335 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrNotCluster is returned by cluster-only operations on other deployments
+var ErrNotCluster = errors.New("not connected to a Redis Cluster")
+
+// SlotRange is an inclusive range of hash slots
+type SlotRange struct {
+	Start, End int
+}
+
+func (r SlotRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// SlotMigration is a slot being moved between two nodes, as reported by
+// the node on either side
+type SlotMigration struct {
+	Slot      int
+	NodeID    string // the other node
+	Importing bool   // true when the slot moves into this node
+}
+
+// ClusterNode is a node of a Redis Cluster as described by CLUSTER NODES,
+// completed with CLUSTER SHARDS and per-node INFO where available
+type ClusterNode struct {
+	ID         string
+	Addr       string
+	Flags      []string
+	MasterID   string // empty for masters
+	LinkState  string
+	Epoch      int64
+	Slots      []SlotRange
+	Migrations []SlotMigration
+
+	Health     string // from CLUSTER SHARDS, empty on servers before 7.0
+	ReplOffset int64  // from CLUSTER SHARDS, -1 when unknown
+	Keys       int64  // DBSIZE, -1 when the node could not be queried
+	UsedMemory int64  // INFO used_memory, -1 when the node could not be queried
+
+	Replicas []*ClusterNode // for masters
+}
+
+// IsMaster reports whether the node serves slots as a master
+func (n *ClusterNode) IsMaster() bool {
+	return n.hasFlag("master")
+}
+
+// Failing reports whether the cluster, or this node itself, considers the
+// node to be down
+func (n *ClusterNode) Failing() bool {
+	return n.hasFlag("fail") || n.hasFlag("fail?") || n.hasFlag("noaddr") ||
+		n.LinkState == "disconnected" || n.Health == "failed"
+}
+
+// SlotCount returns the number of slots served by the node
+func (n *ClusterNode) SlotCount() int {
+	count := 0
+	for _, r := range n.Slots {
+		count += r.End - r.Start + 1
+	}
+	return count
+}
+
+func (n *ClusterNode) hasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterTopology is the layout of a Redis Cluster
+type ClusterTopology struct {
+	State        string         // cluster_state from CLUSTER INFO
+	SlotsCovered int            // slots assigned to a master
+	Masters      []*ClusterNode // ordered by first slot, replicas attached
+	Orphans      []*ClusterNode // replicas whose master is unknown
+}
+
+// Nodes returns every node, masters followed by their replicas
+func (t *ClusterTopology) Nodes() []*ClusterNode {
+	var nodes []*ClusterNode
+	for _, m := range t.Masters {
+		nodes = append(nodes, m)
+		nodes = append(nodes, m.Replicas...)
+	}
+	return append(nodes, t.Orphans...)
+}
+
+// Migrations returns every slot in migration, as reported by the source node
+// or, failing that, by the importing node
+func (t *ClusterTopology) Migrations() []ClusterMigration {
+	seen := make(map[int]bool)
+	var migrations []ClusterMigration
+	for _, n := range t.Masters {
+		for _, m := range n.Migrations {
+			if !m.Importing {
+				seen[m.Slot] = true
+				migrations = append(migrations, ClusterMigration{Slot: m.Slot, From: n.ID, To: m.NodeID})
+			}
+		}
+	}
+	for _, n := range t.Masters {
+		for _, m := range n.Migrations {
+			if m.Importing && !seen[m.Slot] {
+				migrations = append(migrations, ClusterMigration{Slot: m.Slot, From: m.NodeID, To: n.ID})
+			}
+		}
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Slot < migrations[j].Slot })
+	return migrations
+}
+
+// ClusterMigration is a slot moving from one node to another
+type ClusterMigration struct {
+	Slot     int
+	From, To string // node IDs
+}
+
+// GetClusterTopology reads CLUSTER NODES, CLUSTER SHARDS and CLUSTER INFO and
+// queries every node for its key count and memory usage
+func GetClusterTopology(rdb redis.UniversalClient) (*ClusterTopology, error) {
+	ctx := context.TODO()
+
+	cluster, ok := rdb.(*redis.ClusterClient)
+	if !ok {
+		return nil, ErrNotCluster
+	}
+
+	raw, err := cluster.ClusterNodes(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+	nodes := parseClusterNodes(raw)
+
+	byID := make(map[string]*ClusterNode, len(nodes))
+	byAddr := make(map[string]*ClusterNode, len(nodes))
+	for _, n := range nodes {
+		byID[n.ID] = n
+		byAddr[n.Addr] = n
+	}
+
+	// CLUSTER SHARDS only exists since Redis 7.0
+	if shards, err := cluster.Do(ctx, "cluster", "shards").Result(); err == nil {
+		applyClusterShards(shards, byID)
+	}
+
+	topology := &ClusterTopology{}
+	if info, err := cluster.ClusterInfo(ctx).Result(); err == nil {
+		fields := parseInfo(info)
+		topology.State = fields["cluster_state"]
+	}
+
+	var mu sync.Mutex
+	_ = cluster.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+		keys, keysErr := client.DBSize(ctx).Result()
+		info, infoErr := client.Info(ctx, "memory").Result()
+
+		mu.Lock()
+		defer mu.Unlock()
+		n, ok := byAddr[client.Options().Addr]
+		if !ok {
+			return nil
+		}
+		if keysErr == nil {
+			n.Keys = keys
+		}
+		if infoErr == nil {
+			n.UsedMemory = parseInt64(parseInfo(info)["used_memory"])
+		}
+		return nil
+	})
+
+	for _, n := range nodes {
+		if n.MasterID == "" {
+			if n.IsMaster() {
+				topology.Masters = append(topology.Masters, n)
+				topology.SlotsCovered += n.SlotCount()
+			}
+			continue
+		}
+		if master, ok := byID[n.MasterID]; ok {
+			master.Replicas = append(master.Replicas, n)
+		} else {
+			topology.Orphans = append(topology.Orphans, n)
+		}
+	}
+
+	sort.SliceStable(topology.Masters, func(i, j int) bool {
+		a, b := topology.Masters[i], topology.Masters[j]
+		if len(a.Slots) == 0 || len(b.Slots) == 0 {
+			return len(a.Slots) > len(b.Slots)
+		}
+		return a.Slots[0].Start < b.Slots[0].Start
+	})
+	for _, m := range topology.Masters {
+		sort.Slice(m.Replicas, func(i, j int) bool { return m.Replicas[i].Addr < m.Replicas[j].Addr })
+	}
+
+	return topology, nil
+}
+
+// parseClusterNodes parses the output of CLUSTER NODES:
+//
+//	<id> <ip:port@cport[,hostname]> <flags> <master> <ping-sent> <pong-recv> <config-epoch> <link-state> <slot> ...
+func parseClusterNodes(raw string) []*ClusterNode {
+	var nodes []*ClusterNode
+	for _, line := range strings.Split(raw, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+
+		addr := fields[1]
+		if i := strings.IndexAny(addr, "@,"); i >= 0 {
+			addr = addr[:i]
+		}
+		n := &ClusterNode{
+			ID:         fields[0],
+			Addr:       addr,
+			Flags:      strings.Split(fields[2], ","),
+			LinkState:  fields[7],
+			Epoch:      parseInt64(fields[6]),
+			ReplOffset: -1,
+			Keys:       -1,
+			UsedMemory: -1,
+		}
+		if fields[3] != "-" {
+			n.MasterID = fields[3]
+		}
+
+		for _, slot := range fields[8:] {
+			if strings.HasPrefix(slot, "[") {
+				if m, ok := parseSlotMigration(slot); ok {
+					n.Migrations = append(n.Migrations, m)
+				}
+				continue
+			}
+			if r, ok := parseSlotRange(slot); ok {
+				n.Slots = append(n.Slots, r)
+			}
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// parseSlotRange parses "0-5460" or "42"
+func parseSlotRange(s string) (SlotRange, bool) {
+	start, end := s, s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		start, end = s[:i], s[i+1:]
+	}
+	a, err := strconv.Atoi(start)
+	if err != nil {
+		return SlotRange{}, false
+	}
+	b, err := strconv.Atoi(end)
+	if err != nil {
+		return SlotRange{}, false
+	}
+	return SlotRange{Start: a, End: b}, true
+}
+
+// parseSlotMigration parses "[42->-<node id>]" (migrating) or
+// "[42-<-<node id>]" (importing)
+func parseSlotMigration(s string) (SlotMigration, bool) {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	sep, importing := "->-", false
+	if strings.Contains(s, "-<-") {
+		sep, importing = "-<-", true
+	}
+	parts := strings.SplitN(s, sep, 2)
+	if len(parts) != 2 {
+		return SlotMigration{}, false
+	}
+	slot, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return SlotMigration{}, false
+	}
+	return SlotMigration{Slot: slot, NodeID: parts[1], Importing: importing}, true
+}
+
+// applyClusterShards copies the health and replication offset reported by
+// CLUSTER SHARDS onto the nodes. Each shard is a flat list of "slots" and
+// "nodes" pairs; each node a flat list of attribute pairs.
+func applyClusterShards(reply interface{}, byID map[string]*ClusterNode) {
+	shards, _ := reply.([]interface{})
+	for _, shard := range shards {
+		shardFields := pairs(shard)
+		nodes, _ := shardFields["nodes"].([]interface{})
+		for _, node := range nodes {
+			attrs := pairs(node)
+			id, _ := attrs["id"].(string)
+			n, ok := byID[id]
+			if !ok {
+				continue
+			}
+			if health, ok := attrs["health"].(string); ok {
+				n.Health = health
+			}
+			if offset, ok := attrs["replication-offset"].(int64); ok {
+				n.ReplOffset = offset
+			}
+		}
+	}
+}
+
+// pairs turns a flat [name, value, ...] reply into a map
+func pairs(v interface{}) map[string]interface{} {
+	list, _ := v.([]interface{})
+	m := make(map[string]interface{}, len(list)/2)
+	for i := 0; i+1 < len(list); i += 2 {
+		if name, ok := list[i].(string); ok {
+			m[name] = list[i+1]
+		}
+	}
+	return m
+}
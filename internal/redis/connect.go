@@ -0,0 +1,238 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/hawkins/redis-viewer/internal/config"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/rdbfile"
+)
+
+// Options are the client options of a connection: the go-redis universal
+// options plus what they cannot express for sentinel deployments
+type Options struct {
+	redis.UniversalOptions
+
+	// ReplicaOnly sends every command to a replica chosen by the sentinels
+	ReplicaOnly bool
+	// SentinelRouteByLatency and SentinelRouteRandomly spread read-only
+	// commands over the master and its replicas, writes still go to the master
+	SentinelRouteByLatency bool
+	SentinelRouteRandomly  bool
+
+	// RDBFile serves the keys of an RDB file instead of connecting to the
+	// configured servers
+	RDBFile string
+}
+
+// NewOptions builds the client options for the configured connection
+func NewOptions(cfg config.Config) *Options {
+	return &Options{
+		UniversalOptions: redis.UniversalOptions{
+			Addrs:        cfg.Addrs,
+			DB:           cfg.DB,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			MaxRetries:   constant.MaxRetries,
+			MaxRedirects: constant.MaxRedirects,
+			MasterName:   cfg.MasterName,
+
+			SentinelUsername: cfg.Sentinel.Username,
+			SentinelPassword: cfg.Sentinel.Password,
+
+			ReadOnly:       cfg.Cluster.ReadOnly,
+			RouteByLatency: cfg.Cluster.RouteByLatency,
+			RouteRandomly:  cfg.Cluster.RouteRandomly,
+		},
+		ReplicaOnly:            cfg.Sentinel.ReplicaOnly,
+		SentinelRouteByLatency: cfg.Sentinel.RouteByLatency,
+		SentinelRouteRandomly:  cfg.Sentinel.RouteRandomly,
+		RDBFile:                cfg.RDB,
+	}
+}
+
+// TargetOptions resolves the second connection of a diff or copy: "N" is
+// database N of the base connection, "name" a profile of the configuration
+// and "name/N" database N of that profile
+func TargetOptions(cfg config.Config, base *Options, spec string) (*Options, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, errors.New("no target given")
+	}
+	if db, err := strconv.Atoi(spec); err == nil {
+		if db < 0 {
+			return nil, fmt.Errorf("invalid database %d", db)
+		}
+		// The copy keeps the dialer of an RDB file already loaded
+		opts := *base
+		opts.DB = db
+		return &opts, nil
+	}
+
+	name, db := spec, -1
+	if i := strings.LastIndex(spec, "/"); i >= 0 {
+		n, err := strconv.Atoi(spec[i+1:])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid database in target %q", spec)
+		}
+		name, db = spec[:i], n
+	}
+
+	// Viper lowercases the keys of maps
+	profile, ok := cfg.Profiles[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(cfg.Profiles))
+		for n := range cfg.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("unknown profile %q: no profiles are configured", name)
+		}
+		return nil, fmt.Errorf("unknown profile %q, configured profiles: %s", name, strings.Join(names, ", "))
+	}
+
+	opts := NewOptions(cfg)
+	opts.Addrs = profile.Addrs
+	opts.DB = profile.DB
+	opts.Username = profile.Username
+	opts.Password = profile.Password
+	opts.MasterName = profile.MasterName
+	opts.RDBFile = profile.RDB
+	if db >= 0 {
+		opts.DB = db
+	}
+	return opts, nil
+}
+
+// SameDatabase reports whether both options point at the same database of
+// the same servers or RDB file
+func (o *Options) SameDatabase(other *Options) bool {
+	if o.DB != other.DB || o.RDBFile != other.RDBFile || o.MasterName != other.MasterName {
+		return false
+	}
+	if o.RDBFile != "" {
+		return true
+	}
+	addrs := append([]string(nil), o.Addrs...)
+	otherAddrs := append([]string(nil), other.Addrs...)
+	sort.Strings(addrs)
+	sort.Strings(otherAddrs)
+	return strings.Join(addrs, ",") == strings.Join(otherAddrs, ",")
+}
+
+// ReadsFromReplicas reports whether some reads may be served by replicas
+func (o *Options) ReadsFromReplicas() bool {
+	if o.MasterName != "" {
+		return o.ReplicaOnly || o.SentinelRouteByLatency || o.SentinelRouteRandomly
+	}
+	return len(o.Addrs) > 1 && (o.ReadOnly || o.RouteByLatency || o.RouteRandomly)
+}
+
+// Connect creates a client, attaches the command logger and checks the
+// connection with PING
+func Connect(opts *Options) (redis.UniversalClient, error) {
+	if opts.RDBFile != "" && opts.Dialer == nil {
+		if err := opts.openRDB(); err != nil {
+			return nil, err
+		}
+	}
+
+	rdb, err := newClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	AttachLogger(rdb)
+
+	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
+		_ = rdb.Close()
+		return nil, fmt.Errorf("connect to redis failed: %w", err)
+	}
+
+	return rdb, nil
+}
+
+// openRDB parses the RDB file and points the options at a server of its
+// content. The dialer is kept by copies of the options, so the file is
+// parsed once however many clients are created.
+func (o *Options) openRDB() error {
+	start := time.Now()
+	file, err := rdbfile.Load(o.RDBFile)
+	if err != nil {
+		return fmt.Errorf("load RDB file: %w", err)
+	}
+	logger.Info("rdb file loaded", "path", o.RDBFile, "version", file.Version, "keys", file.Keys(),
+		"expired", file.Expired, "duration", time.Since(start))
+
+	o.Addrs = []string{o.RDBFile}
+	o.MasterName = ""
+	o.ReplicaOnly = false
+	o.Dialer = rdbfile.NewServer(file).Dial
+	return nil
+}
+
+func newClient(opts *Options) (redis.UniversalClient, error) {
+	if opts.MasterName == "" || !opts.ReadsFromReplicas() {
+		return redis.NewUniversalClient(&opts.UniversalOptions), nil
+	}
+
+	universal := opts.UniversalOptions
+	failover := universal.Failover()
+	failover.SlaveOnly = opts.ReplicaOnly
+	if !opts.SentinelRouteByLatency && !opts.SentinelRouteRandomly {
+		return redis.NewFailoverClient(failover), nil
+	}
+
+	// The cluster flavour of the failover client routes reads but has no
+	// notion of databases
+	if opts.DB != 0 {
+		return nil, errors.New("sentinel route_by_latency and route_randomly only support database 0")
+	}
+	failover.RouteByLatency = opts.SentinelRouteByLatency
+	failover.RouteRandomly = opts.SentinelRouteRandomly
+	return &failoverRouter{redis.NewFailoverClusterClient(failover)}, nil
+}
+
+// failoverRouter is the client of a sentinel deployment spreading reads over
+// the master and its replicas. go-redis implements it as a cluster client of
+// a single shard; the distinct type keeps it off the code paths that switch
+// on *redis.ClusterClient for CLUSTER commands and per-master fan-out.
+type failoverRouter struct {
+	*redis.ClusterClient
+}
+
+// scanClient returns the node that key scans run on: a replica answering
+// PING, or the master when there is none. A SCAN cursor is only valid on the
+// node that returned it, so the pages cannot be routed like other reads.
+func (r *failoverRouter) scanClient(ctx context.Context) (*redis.Client, error) {
+	var replica *redis.Client
+	for _, client := range healthyReplicas(ctx, r.ClusterClient) {
+		if replica == nil || client.Options().Addr < replica.Options().Addr {
+			replica = client
+		}
+	}
+	if replica != nil {
+		return replica, nil
+	}
+
+	var master *redis.Client
+	err := r.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		master = client
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if master == nil {
+		return nil, errors.New("no sentinel master known")
+	}
+	return master, nil
+}
@@ -0,0 +1,188 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CopyConflict decides what happens to keys that already exist in the target
+type CopyConflict int
+
+const (
+	// CopySkip leaves existing keys untouched
+	CopySkip CopyConflict = iota
+	// CopyReplace overwrites existing keys
+	CopyReplace
+	// CopyRename writes the key under its name followed by a suffix
+	CopyRename
+)
+
+// CopyConflicts lists the policies in the order the UI cycles through them
+var CopyConflicts = []CopyConflict{CopySkip, CopyReplace, CopyRename}
+
+// String implements fmt.Stringer
+func (c CopyConflict) String() string {
+	switch c {
+	case CopyReplace:
+		return "replace"
+	case CopyRename:
+		return "rename"
+	}
+	return "skip"
+}
+
+// ParseCopyConflict parses a policy name
+func ParseCopyConflict(s string) (CopyConflict, error) {
+	for _, c := range CopyConflicts {
+		if strings.EqualFold(s, c.String()) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid conflict policy %q: use skip, replace or rename", s)
+}
+
+// CopyOptions configures CopyKeys and CopyMatching
+type CopyOptions struct {
+	Conflict CopyConflict
+	// Suffix is appended to the name of existing keys under CopyRename
+	Suffix    string
+	BatchSize int           // keys per pipeline
+	Pause     time.Duration // sleep between batches to throttle the load
+	DryRun    bool          // report what would be written without writing
+	// SameDatabase is set when dst is src, where the copies would be
+	// scanned again: CopyMatching then lists every key before writing
+	SameDatabase bool
+}
+
+// CopyStats counts the outcome of a copy. Renamed keys are also counted as
+// created, or as failures when the renamed key exists too.
+type CopyStats struct {
+	RestoreStats
+	Renamed int
+}
+
+// Add accumulates the counts of another batch
+func (s *CopyStats) Add(o CopyStats) {
+	s.RestoreStats.Add(o.RestoreStats)
+	s.Renamed += o.Renamed
+}
+
+// CopyKeys copies keys from src to dst with DUMP and RESTORE, keeping their
+// TTL, in batches of opts.BatchSize. progress, when set, receives the number
+// of keys processed so far.
+func CopyKeys(ctx context.Context, src, dst redis.UniversalClient, keys []string, opts CopyOptions,
+	progress func(done int)) (CopyStats, error) {
+	var stats CopyStats
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	for start := 0; start < len(keys); start += opts.BatchSize {
+		if start > 0 && opts.Pause > 0 {
+			select {
+			case <-time.After(opts.Pause):
+			case <-ctx.Done():
+				return stats, ctx.Err()
+			}
+		}
+		end := start + opts.BatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batchStats, err := copyBatch(ctx, src, dst, keys[start:end], opts)
+		stats.Add(batchStats)
+		if err != nil {
+			return stats, err
+		}
+		if progress != nil {
+			progress(end)
+		}
+	}
+	return stats, nil
+}
+
+// CopyMatching scans the keys of match in src and copies them to dst like
+// CopyKeys
+func CopyMatching(ctx context.Context, src, dst redis.UniversalClient, match string, opts CopyOptions,
+	progress func(done int)) (CopyStats, error) {
+	var stats CopyStats
+	done := 0
+	scanOpts := ScanStatsOptions{Match: match, BatchSize: opts.BatchSize, Pause: opts.Pause}
+	if opts.SameDatabase {
+		// SCAN may return the keys written so far, which would be copied
+		// again under a longer name
+		var keys []string
+		seen := make(map[string]struct{})
+		err := scanBatches(ctx, src, scanOpts, func(batch []string) error {
+			for _, key := range batch {
+				if _, ok := seen[key]; !ok {
+					seen[key] = struct{}{}
+					keys = append(keys, key)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+		return CopyKeys(ctx, src, dst, keys, opts, progress)
+	}
+	err := scanBatches(ctx, src, scanOpts, func(keys []string) error {
+		batchStats, err := copyBatch(ctx, src, dst, keys, opts)
+		stats.Add(batchStats)
+		if err != nil {
+			return err
+		}
+		done += len(keys)
+		if progress != nil {
+			progress(done)
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// copyBatch dumps a batch of keys from src and restores them in dst. Under
+// CopyRename the keys existing in dst are checked first and the records
+// renamed; a renamed key that exists too is reported as a failure.
+func copyBatch(ctx context.Context, src, dst redis.UniversalClient, keys []string, opts CopyOptions) (CopyStats, error) {
+	var stats CopyStats
+	if err := ctx.Err(); err != nil {
+		return stats, err
+	}
+
+	records, err := LoadRecords(ctx, src, keys, true)
+	if err != nil || len(records) == 0 {
+		return stats, err
+	}
+
+	policy := ConflictSkip
+	switch opts.Conflict {
+	case CopyReplace:
+		policy = ConflictReplace
+	case CopyRename:
+		policy = ConflictFail
+		pipe := dst.Pipeline()
+		existsCmds := make([]*redis.IntCmd, len(records))
+		for i, r := range records {
+			existsCmds[i] = pipe.Exists(ctx, r.Key)
+		}
+		if err := execPipeline(ctx, pipe); err != nil {
+			return stats, err
+		}
+		for i := range records {
+			if existsCmds[i].Val() > 0 {
+				records[i].Key += opts.Suffix
+				stats.Renamed++
+			}
+		}
+	}
+
+	restored, err := RestoreRecords(ctx, dst, records, policy, opts.DryRun)
+	stats.RestoreStats = restored
+	return stats, err
+}
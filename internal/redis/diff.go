@@ -0,0 +1,318 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// DiffKind tells on which side of a comparison a key differs
+type DiffKind int
+
+const (
+	DiffOnlyA   DiffKind = iota // the key only exists in A
+	DiffOnlyB                   // the key only exists in B
+	DiffChanged                 // the key exists in both with a different type, value or TTL
+)
+
+// Marker returns the one-character marker of the kind, as in a unified diff
+func (k DiffKind) Marker() string {
+	switch k {
+	case DiffOnlyA:
+		return "-"
+	case DiffOnlyB:
+		return "+"
+	}
+	return "~"
+}
+
+// String implements fmt.Stringer
+func (k DiffKind) String() string {
+	switch k {
+	case DiffOnlyA:
+		return "only in A"
+	case DiffOnlyB:
+		return "only in B"
+	}
+	return "changed"
+}
+
+// Change names the kind as a change from A to B, when A is a snapshot of B
+func (k DiffKind) Change() string {
+	switch k {
+	case DiffOnlyA:
+		return "removed"
+	case DiffOnlyB:
+		return "added"
+	}
+	return "modified"
+}
+
+// KeyDiff is a key that differs between two keyspaces. A or B is nil when
+// the key is missing from that side.
+type KeyDiff struct {
+	Key  string
+	Kind DiffKind
+
+	// What differs when Kind is DiffChanged
+	Type  bool
+	Value bool
+	TTL   bool
+
+	A, B *KeyRecord
+}
+
+// Details describes what differs, e.g. "type string → hash, ttl 10s → none"
+func (d KeyDiff) Details() string {
+	switch d.Kind {
+	case DiffOnlyA:
+		return d.A.Type + ", ttl " + FormatTTLMillis(d.A.TTLMillis)
+	case DiffOnlyB:
+		return d.B.Type + ", ttl " + FormatTTLMillis(d.B.TTLMillis)
+	}
+
+	var details []string
+	if d.Type {
+		details = append(details, fmt.Sprintf("type %s → %s", d.A.Type, d.B.Type))
+	} else if d.Value {
+		details = append(details, "value")
+	}
+	if d.TTL {
+		details = append(details, fmt.Sprintf("ttl %s → %s", FormatTTLMillis(d.A.TTLMillis), FormatTTLMillis(d.B.TTLMillis)))
+	}
+	return strings.Join(details, ", ")
+}
+
+// FormatTTLMillis formats the TTL of a record, "none" when it does not expire
+func FormatTTLMillis(ttl int64) string {
+	if ttl < 0 {
+		return "none"
+	}
+	if ttl < 1000 {
+		return fmt.Sprintf("%dms", ttl)
+	}
+	return FormatSeconds(ttl / 1000)
+}
+
+// DiffOptions configures DiffKeyspaces
+type DiffOptions struct {
+	Match     string        // SCAN pattern, empty for every key
+	BatchSize int           // keys per pipeline
+	Pause     time.Duration // sleep between SCAN pages to throttle the load
+	// TTLTolerance is the largest gap between two TTLs still considered
+	// equal, since both sides keep counting down while they are read
+	TTLTolerance time.Duration
+}
+
+// DiffSummary counts the outcome of DiffKeyspaces
+type DiffSummary struct {
+	Compared  int // keys found in both keyspaces
+	Identical int
+	Changed   int
+	OnlyA     int
+	OnlyB     int
+}
+
+// Differences returns the number of keys reported
+func (s DiffSummary) Differences() int {
+	return s.Changed + s.OnlyA + s.OnlyB
+}
+
+// DiffKeyspaces compares the keys of opts.Match in a and b. The keys of a
+// are scanned first and loaded from both sides in pipelined batches, then b
+// is scanned for the keys a does not have. fn is called with every key that
+// differs; returning an error stops the comparison. progress, when set,
+// receives the number of keys scanned so far on both sides.
+func DiffKeyspaces(ctx context.Context, a, b redis.UniversalClient, opts DiffOptions,
+	fn func(KeyDiff) error, progress func(scanned int)) (DiffSummary, error) {
+	var summary DiffSummary
+	scanOpts := ScanStatsOptions{Match: opts.Match, BatchSize: opts.BatchSize, Pause: opts.Pause}
+	seen := make(map[string]struct{})
+	scanned := 0
+
+	err := scanBatches(ctx, a, scanOpts, func(keys []string) error {
+		recordsA, err := LoadRecords(ctx, a, keys, false)
+		if err != nil {
+			return err
+		}
+		recordsB, err := LoadRecords(ctx, b, keys, false)
+		if err != nil {
+			return err
+		}
+		byKeyA, byKeyB := recordsByKey(recordsA), recordsByKey(recordsB)
+
+		for _, key := range keys {
+			seen[key] = struct{}{}
+			ra, rb := byKeyA[key], byKeyB[key]
+			var diff KeyDiff
+			switch {
+			case ra == nil && rb == nil:
+				// Deleted since it was scanned
+				continue
+			case rb == nil:
+				summary.OnlyA++
+				diff = KeyDiff{Key: key, Kind: DiffOnlyA, A: ra}
+			case ra == nil:
+				summary.OnlyB++
+				diff = KeyDiff{Key: key, Kind: DiffOnlyB, B: rb}
+			default:
+				summary.Compared++
+				diff = compareRecords(ra, rb, opts.TTLTolerance)
+				if !diff.Type && !diff.Value && !diff.TTL {
+					summary.Identical++
+					continue
+				}
+				summary.Changed++
+			}
+			if err := fn(diff); err != nil {
+				return err
+			}
+		}
+
+		scanned += len(keys)
+		if progress != nil {
+			progress(scanned)
+		}
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	err = scanBatches(ctx, b, scanOpts, func(keys []string) error {
+		var missing []string
+		for _, key := range keys {
+			if _, ok := seen[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		scanned += len(keys)
+		if len(missing) > 0 {
+			records, err := LoadRecords(ctx, b, missing, false)
+			if err != nil {
+				return err
+			}
+			for i := range records {
+				summary.OnlyB++
+				if err := fn(KeyDiff{Key: records[i].Key, Kind: DiffOnlyB, B: &records[i]}); err != nil {
+					return err
+				}
+			}
+		}
+		if progress != nil {
+			progress(scanned)
+		}
+		return nil
+	})
+	return summary, err
+}
+
+// DiffRecords compares records saved earlier, as side A, with the live keys
+// of opts.Match in rdb, as side B: keys only in A were removed since, keys
+// only in B were added. fn and progress are called like in DiffKeyspaces.
+func DiffRecords(ctx context.Context, records []KeyRecord, rdb redis.UniversalClient, opts DiffOptions,
+	fn func(KeyDiff) error, progress func(scanned int)) (DiffSummary, error) {
+	var summary DiffSummary
+	saved := recordsByKey(records)
+	seen := make(map[string]struct{}, len(records))
+	scanned := 0
+
+	scanOpts := ScanStatsOptions{Match: opts.Match, BatchSize: opts.BatchSize, Pause: opts.Pause}
+	err := scanBatches(ctx, rdb, scanOpts, func(keys []string) error {
+		live, err := LoadRecords(ctx, rdb, keys, false)
+		if err != nil {
+			return err
+		}
+		for i := range live {
+			rb := &live[i]
+			seen[rb.Key] = struct{}{}
+			ra := saved[rb.Key]
+			var diff KeyDiff
+			if ra == nil {
+				summary.OnlyB++
+				diff = KeyDiff{Key: rb.Key, Kind: DiffOnlyB, B: rb}
+			} else {
+				summary.Compared++
+				diff = compareRecords(ra, rb, opts.TTLTolerance)
+				if !diff.Type && !diff.Value && !diff.TTL {
+					summary.Identical++
+					continue
+				}
+				summary.Changed++
+			}
+			if err := fn(diff); err != nil {
+				return err
+			}
+		}
+
+		scanned += len(keys)
+		if progress != nil {
+			progress(scanned)
+		}
+		return nil
+	})
+	if err != nil {
+		return summary, err
+	}
+
+	for i := range records {
+		if _, ok := seen[records[i].Key]; ok {
+			continue
+		}
+		summary.OnlyA++
+		if err := fn(KeyDiff{Key: records[i].Key, Kind: DiffOnlyA, A: &records[i]}); err != nil {
+			return summary, err
+		}
+	}
+	return summary, nil
+}
+
+// recordsByKey indexes records by key
+func recordsByKey(records []KeyRecord) map[string]*KeyRecord {
+	m := make(map[string]*KeyRecord, len(records))
+	for i := range records {
+		m[records[i].Key] = &records[i]
+	}
+	return m
+}
+
+// compareRecords compares the type, value and TTL of a key on both sides
+func compareRecords(a, b *KeyRecord, ttlTolerance time.Duration) KeyDiff {
+	diff := KeyDiff{Key: a.Key, Kind: DiffChanged, A: a, B: b}
+	diff.Type = a.Type != b.Type
+	diff.Value = !diff.Type && !equalValues(a, b)
+
+	switch {
+	case a.TTLMillis < 0 || b.TTLMillis < 0:
+		diff.TTL = (a.TTLMillis < 0) != (b.TTLMillis < 0)
+	default:
+		gap := a.TTLMillis - b.TTLMillis
+		if gap < 0 {
+			gap = -gap
+		}
+		diff.TTL = time.Duration(gap)*time.Millisecond > ttlTolerance
+	}
+	return diff
+}
+
+// equalValues compares the values of two records of the same type. Set
+// members come in no particular order.
+func equalValues(a, b *KeyRecord) bool {
+	if a.Type == "set" {
+		return reflect.DeepEqual(sortedMembers(a.Value), sortedMembers(b.Value))
+	}
+	return reflect.DeepEqual(a.Value, b.Value)
+}
+
+// sortedMembers returns a sorted copy of a set value
+func sortedMembers(value interface{}) []string {
+	members, _ := value.([]string)
+	sorted := append([]string(nil), members...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/hawkins/redis-viewer/internal/logger"
+)
+
+const (
+	// logged commands are truncated to keep log lines readable
+	maxLoggedArgs   = 8
+	maxLoggedArgLen = 64
+)
+
+type startTimeKey struct{}
+
+// loggingHook logs every command sent to Redis with its duration and error
+type loggingHook struct{}
+
+// AttachLogger logs the commands issued by rdb when logging is enabled
+func AttachLogger(rdb redis.UniversalClient) {
+	if !logger.Enabled(logger.LevelError) {
+		return
+	}
+	rdb.AddHook(loggingHook{})
+}
+
+func (loggingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, startTimeKey{}, time.Now()), nil
+}
+
+func (loggingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	logCommand(cmd, elapsed(ctx), false)
+	return nil
+}
+
+func (loggingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, startTimeKey{}, time.Now()), nil
+}
+
+func (loggingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	d := elapsed(ctx)
+	logger.Debug("redis pipeline", "commands", len(cmds), "duration", d)
+	for _, cmd := range cmds {
+		logCommand(cmd, d, true)
+	}
+	return nil
+}
+
+// elapsed returns the time since BeforeProcess stored its start time in ctx
+func elapsed(ctx context.Context) time.Duration {
+	if start, ok := ctx.Value(startTimeKey{}).(time.Time); ok {
+		return time.Since(start)
+	}
+	return 0
+}
+
+// logCommand logs a finished command, at error level when it failed
+func logCommand(cmd redis.Cmder, d time.Duration, pipelined bool) {
+	err := cmd.Err()
+	failed := err != nil && err != redis.Nil
+	if !failed && !logger.Enabled(logger.LevelDebug) {
+		return
+	}
+
+	keyvals := []interface{}{"cmd", formatArgs(cmd), "duration", d}
+	if pipelined {
+		keyvals = append(keyvals, "pipeline", true)
+	}
+	if failed {
+		logger.Error("redis command failed", append(keyvals, "err", err)...)
+		return
+	}
+	logger.Debug("redis command", keyvals...)
+}
+
+// formatArgs renders a command for the log, truncating long arguments and
+// hiding credentials
+func formatArgs(cmd redis.Cmder) string {
+	args := cmd.Args()
+
+	switch strings.ToLower(cmd.Name()) {
+	case "auth", "hello", "migrate", "acl":
+		// These may carry passwords
+		return strings.ToUpper(cmd.Name()) + " [redacted]"
+	case "config":
+		if len(args) > 2 && strings.EqualFold(fmt.Sprint(args[1]), "set") {
+			param := strings.ToLower(fmt.Sprint(args[2]))
+			if strings.Contains(param, "pass") || strings.Contains(param, "auth") {
+				return "CONFIG SET " + param + " [redacted]"
+			}
+		}
+	}
+
+	parts := make([]string, 0, maxLoggedArgs+1)
+	for i, arg := range args {
+		if i == maxLoggedArgs {
+			parts = append(parts, fmt.Sprintf("...(%d more)", len(args)-i))
+			break
+		}
+		s := fmt.Sprint(arg)
+		if len(s) > maxLoggedArgLen {
+			s = s[:maxLoggedArgLen] + "..."
+		}
+		if i == 0 {
+			s = strings.ToUpper(s)
+		}
+		parts = append(parts, s)
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,87 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// KeyMetadata describes how a key is stored. Fields the server could not
+// report are set to -1 (or left empty for Encoding).
+type KeyMetadata struct {
+	Bytes            int64  // MEMORY USAGE
+	Encoding         string // OBJECT ENCODING
+	IdleSeconds      int64  // OBJECT IDLETIME, only under an LRU or no-eviction policy
+	Freq             int64  // OBJECT FREQ, only under an LFU maxmemory-policy
+	Elements         int64  // string length or number of elements
+	SerializedLength int64  // length of the DUMP payload, -1 until loaded with GetSerializedLength
+	SerializedErr    string // why GetSerializedLength failed
+	Dumpable         bool   // whether GetSerializedLength applies to the type of the key
+
+	// Cluster placement, Slot is -1 outside of cluster mode
+	Slot int    // CLUSTER KEYSLOT
+	Node string // address of the master owning the slot
+}
+
+// GetKeyMetadata gathers the storage details of a key in a single pipeline.
+// OBJECT IDLETIME and OBJECT FREQ are both sent: the server rejects whichever
+// does not apply to its maxmemory-policy, so exactly one of them is reported.
+func GetKeyMetadata(ctx context.Context, rdb redis.UniversalClient, key, keyType string) (*KeyMetadata, error) {
+	pipe := rdb.Pipeline()
+	memCmd := pipe.MemoryUsage(ctx, key)
+	encodingCmd := pipe.ObjectEncoding(ctx, key)
+	idleCmd := pipe.ObjectIdleTime(ctx, key)
+	freqCmd := redis.NewIntCmd(ctx, "object", "freq", key)
+	_ = pipe.Process(ctx, freqCmd)
+	countCmd := queueElementCount(ctx, pipe, key, keyType)
+	cluster, isCluster := rdb.(*redis.ClusterClient)
+	var slotCmd *redis.IntCmd
+	if isCluster {
+		slotCmd = pipe.ClusterKeySlot(ctx, key)
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return nil, err
+	}
+
+	meta := &KeyMetadata{
+		Bytes:            -1,
+		Encoding:         encodingCmd.Val(),
+		IdleSeconds:      -1,
+		Freq:             -1,
+		Elements:         -1,
+		SerializedLength: -1,
+		Dumpable:         countCmd != nil,
+		Slot:             -1,
+	}
+	if memCmd.Err() == nil {
+		meta.Bytes = memCmd.Val()
+	}
+	if idleCmd.Err() == nil {
+		meta.IdleSeconds = int64(idleCmd.Val().Seconds())
+	}
+	if freqCmd.Err() == nil {
+		meta.Freq = freqCmd.Val()
+	}
+	if countCmd != nil && countCmd.Err() == nil {
+		meta.Elements = countCmd.Val()
+	}
+	if slotCmd != nil && slotCmd.Err() == nil {
+		meta.Slot = int(slotCmd.Val())
+		if master, err := cluster.MasterForKey(ctx, key); err == nil {
+			meta.Node = master.Options().Addr
+		}
+	}
+
+	return meta, nil
+}
+
+// GetSerializedLength returns the length of the DUMP payload of a key. The
+// whole value is serialized and transferred, so it is only loaded on demand
+// rather than with the rest of the metadata.
+func GetSerializedLength(ctx context.Context, rdb redis.UniversalClient, key string) (int64, error) {
+	payload, err := rdb.Dump(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(payload)), nil
+}
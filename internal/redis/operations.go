@@ -3,7 +3,6 @@ package redis
 import (
 	"context"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -17,40 +16,21 @@ type KeyMessage struct {
 
 // CountKeys counts all keys matching the given pattern
 func CountKeys(rdb redis.UniversalClient, match string) (int, error) {
-	ctx := context.TODO()
-
-	switch rdb := rdb.(type) {
-	case *redis.ClusterClient:
-		var count int64
-
-		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
-			iter := client.Scan(ctx, 0, match, 0).Iterator()
-			for iter.Next(ctx) {
-				atomic.AddInt64(&count, 1)
-			}
-			if err := iter.Err(); err != nil {
-				return err
-			}
-			return nil
-		})
-		if err != nil {
-			return 0, err
-		}
-
-		return int(count), nil
-	default:
-		var count int
-
-		iter := rdb.Scan(ctx, 0, match, 0).Iterator()
-		for iter.Next(ctx) {
-			count++
-		}
-		if err := iter.Err(); err != nil {
-			return 0, err
-		}
+	return CountKeysInScope(rdb, KeyScope{}, match)
+}
 
-		return count, nil
+// CountKeysInScope counts the keys matching the given pattern within a
+// cluster key scope. Scopes other than the zero value need a cluster.
+func CountKeysInScope(rdb redis.UniversalClient, scope KeyScope, match string) (int, error) {
+	count := 0
+	err := scanPages(context.TODO(), rdb, scope, 0, match, 0, func(keys []string) error {
+		count += len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
+	return count, nil
 }
 
 // GetKeys scans and retrieves keys asynchronously via a channel
@@ -59,71 +39,120 @@ func GetKeys(
 	cursor uint64,
 	match string,
 	count int64,
+) <-chan KeyMessage {
+	return GetKeysInScope(rdb, KeyScope{}, cursor, match, count)
+}
+
+// GetKeysInScope is GetKeys restricted to a cluster key scope: only the
+// scope's shard is scanned and keys outside its slot range are dropped.
+// Scopes other than the zero value need a cluster. Keys are sent as SCAN
+// returns them; an error, if any, is the last message.
+func GetKeysInScope(
+	rdb redis.UniversalClient,
+	scope KeyScope,
+	cursor uint64,
+	match string,
+	count int64,
 ) <-chan KeyMessage {
 	res := make(chan KeyMessage, 1)
 
 	go func() {
-		ctx := context.TODO()
-
-		switch rdb := rdb.(type) {
-		case *redis.ClusterClient:
-			// Collect all keys from all masters
-			var allKeys []string
-			var keysMutex sync.Mutex
-
-			err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
-				cursor := uint64(0)
-				for {
-					keys, nextCursor, err := client.Scan(ctx, cursor, match, 0).Result()
-					if err != nil {
-						return err
-					}
-					if len(keys) > 0 {
-						keysMutex.Lock()
-						allKeys = append(allKeys, keys...)
-						keysMutex.Unlock()
-					}
-					cursor = nextCursor
-					if cursor == 0 {
-						break
-					}
-				}
-				return nil
-			})
+		defer close(res)
+
+		err := scanPages(context.TODO(), rdb, scope, cursor, match, count, func(keys []string) error {
+			for _, key := range keys {
+				res <- KeyMessage{key, nil}
+			}
+			return nil
+		})
+		if err != nil {
+			res <- KeyMessage{"", err}
+		}
+	}()
+
+	return res
+}
+
+// scanPages scans the keys matching match within scope and calls fn with the
+// keys of every SCAN page, count being the COUNT hint. The shards of a
+// cluster are scanned concurrently from the start, on replicas when they
+// serve reads; cursor is only used on other deployments. fn is never called
+// concurrently and the next page of a node is only requested once fn
+// returned. Returning an error from fn, or cancelling ctx, stops the scan on
+// every node.
+func scanPages(ctx context.Context, rdb redis.UniversalClient, scope KeyScope, cursor uint64, match string,
+	count int64, fn func(keys []string) error) error {
+	cluster, ok := rdb.(*redis.ClusterClient)
+	if !ok {
+		if !scope.IsZero() {
+			return ErrNotCluster
+		}
+		if router, ok := rdb.(*failoverRouter); ok {
+			client, err := router.scanClient(ctx)
 			if err != nil {
-				res <- KeyMessage{"", err}
-			} else {
-				for _, key := range allKeys {
-					res <- KeyMessage{key, nil}
-				}
+				return err
 			}
-		default:
-			// Scan until no more keys
-			var allKeys []string
-			cursor := uint64(0)
-			for {
-				keys, nextCursor, err := rdb.Scan(ctx, cursor, match, count).Result()
-				if err != nil {
-					res <- KeyMessage{"", err}
-					break
-				}
+			return scanNode(ctx, client, cursor, match, count, fn)
+		}
+		return scanNode(ctx, rdb, cursor, match, count, fn)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		stopErr error
+	)
+	err := forEachScanNode(ctx, cluster, scope, func(ctx context.Context, client *redis.Client) error {
+		return scanNode(ctx, client, 0, match, count, func(keys []string) error {
+			if scope.Slots != nil {
+				inScope := keys[:0]
 				for _, key := range keys {
-					allKeys = append(allKeys, key)
-				}
-				cursor = nextCursor
-				if cursor == 0 {
-					break
+					if scope.Contains(key) {
+						inScope = append(inScope, key)
+					}
 				}
+				keys = inScope
 			}
-			for _, key := range allKeys {
-				res <- KeyMessage{key, nil}
-			}
-		}
 
-		close(res)
-	}()
+			mu.Lock()
+			defer mu.Unlock()
+			if stopErr != nil {
+				return stopErr
+			}
+			if err := fn(keys); err != nil {
+				// Stop the other nodes too
+				stopErr = err
+				cancel()
+				return err
+			}
+			return nil
+		})
+	})
+	if stopErr != nil {
+		return stopErr
+	}
+	return err
+}
 
-	return res
+// scanNode runs SCAN on a single node from cursor until the end of the
+// keyspace, calling fn with every page
+func scanNode(ctx context.Context, client redis.Cmdable, cursor uint64, match string, count int64,
+	fn func(keys []string) error) error {
+	for {
+		keys, next, err := client.Scan(ctx, cursor, match, count).Result()
+		if err != nil {
+			return err
+		}
+		if err := fn(keys); err != nil {
+			return err
+		}
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
 }
 
 // DeleteKey deletes a single key
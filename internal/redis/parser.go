@@ -1,6 +1,9 @@
 package redis
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 // parseInfo parses Redis INFO command output into a map
 func parseInfo(info string) map[string]string {
@@ -25,6 +28,131 @@ func parseInfo(info string) map[string]string {
 	return result
 }
 
+// parseInfoSections parses Redis INFO command output into its sections,
+// keeping the order in which sections and fields are reported
+func parseInfoSections(info string) []InfoSection {
+	var sections []InfoSection
+	lines := strings.Split(info, "\n")
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			sections = append(sections, InfoSection{Name: strings.TrimSpace(strings.TrimPrefix(line, "#"))})
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if len(sections) == 0 {
+			// Fields before any header (should not happen with a real server)
+			sections = append(sections, InfoSection{Name: "Default"})
+		}
+		last := &sections[len(sections)-1]
+		last.Fields = append(last.Fields, InfoField{
+			Key:   strings.TrimSpace(parts[0]),
+			Value: strings.TrimSpace(parts[1]),
+		})
+	}
+
+	return sections
+}
+
+// parseKeyspace parses the INFO keyspace section, where each database is
+// reported as "db0:keys=1,expires=0,avg_ttl=0"
+func parseKeyspace(info string) map[int]*DatabaseStats {
+	result := make(map[int]*DatabaseStats)
+
+	for name, value := range parseInfo(info) {
+		if !strings.HasPrefix(name, "db") {
+			continue
+		}
+		db, err := strconv.Atoi(name[2:])
+		if err != nil {
+			continue
+		}
+
+		stats := &DatabaseStats{DB: db}
+		for _, pair := range strings.Split(value, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "keys":
+				stats.Keys = parseInt64(kv[1])
+			case "expires":
+				stats.Expires = parseInt64(kv[1])
+			case "avg_ttl":
+				stats.avgTTLMillis = parseInt64(kv[1])
+			}
+		}
+		result[db] = stats
+	}
+
+	return result
+}
+
+// parseClientList parses CLIENT LIST output, one client per line of
+// space-separated "field=value" pairs
+func parseClientList(list string, node string) []ClientInfo {
+	var clients []ClientInfo
+
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		client := ClientInfo{Node: node}
+		var omem int64
+		hasTotMem := false
+		for _, field := range strings.Fields(line) {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "id":
+				client.ID = parseInt64(kv[1])
+			case "addr":
+				client.Addr = kv[1]
+			case "name":
+				client.Name = kv[1]
+			case "user":
+				client.User = kv[1]
+			case "age":
+				client.AgeSeconds = parseInt64(kv[1])
+			case "idle":
+				client.IdleSeconds = parseInt64(kv[1])
+			case "db":
+				client.DB = int(parseInt64(kv[1]))
+			case "flags":
+				client.Flags = kv[1]
+			case "cmd":
+				client.Cmd = kv[1]
+			case "tot-mem":
+				client.Memory = parseInt64(kv[1])
+				hasTotMem = true
+			case "omem":
+				omem = parseInt64(kv[1])
+			}
+		}
+		if !hasTotMem {
+			client.Memory = omem
+		}
+		clients = append(clients, client)
+	}
+
+	return clients
+}
+
 // parseInt64 parses a string to int64
 func parseInt64(s string) int64 {
 	var result int64
@@ -66,8 +194,8 @@ func parseFloat64(s string) float64 {
 	return result
 }
 
-// formatSeconds formats seconds into a human-readable string
-func formatSeconds(seconds int64) string {
+// FormatSeconds formats seconds into a human-readable string
+func FormatSeconds(seconds int64) string {
 	if seconds <= 0 {
 		return "0s"
 	}
@@ -138,8 +266,8 @@ func joinStrings(parts []string, sep string) string {
 	return string(result)
 }
 
-// formatBytes formats bytes into a human-readable string
-func formatBytes(bytes int64) string {
+// FormatBytes formats bytes into a human-readable string
+func FormatBytes(bytes int64) string {
 	const unit = 1024
 	if bytes < unit {
 		return formatInt(bytes) + "B"
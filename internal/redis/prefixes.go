@@ -0,0 +1,183 @@
+package redis
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// PrefixStats aggregates the keys sharing a prefix
+type PrefixStats struct {
+	Prefix  string // includes the trailing delimiter, empty for the root
+	Depth   int    // number of segments in Prefix, 0 for the root
+	Keys    int64
+	Bytes   int64
+	WithTTL int64 // keys with an expiry
+
+	children map[string]*PrefixStats
+}
+
+// TTLCoverage returns the percentage of keys with an expiry
+func (p *PrefixStats) TTLCoverage() float64 {
+	if p.Keys == 0 {
+		return 0
+	}
+	return float64(p.WithTTL) / float64(p.Keys) * 100
+}
+
+// Children returns the sub-prefixes, unsorted
+func (p *PrefixStats) Children() []*PrefixStats {
+	children := make([]*PrefixStats, 0, len(p.children))
+	for _, c := range p.children {
+		children = append(children, c)
+	}
+	return children
+}
+
+// HasChildren reports whether the prefix has sub-prefixes
+func (p *PrefixStats) HasChildren() bool {
+	return len(p.children) > 0
+}
+
+func (p *PrefixStats) add(s KeyStat) {
+	p.Keys++
+	p.Bytes += s.Bytes
+	if s.TTLSeconds >= 0 {
+		p.WithTTL++
+	}
+}
+
+// Other returns the totals of the keys directly under p, i.e. those not
+// covered by any sub-prefix. Its Prefix is left empty.
+func (p *PrefixStats) Other() PrefixStats {
+	other := PrefixStats{Depth: p.Depth + 1, Keys: p.Keys, Bytes: p.Bytes, WithTTL: p.WithTTL}
+	for _, c := range p.children {
+		other.Keys -= c.Keys
+		other.Bytes -= c.Bytes
+		other.WithTTL -= c.WithTTL
+	}
+	return other
+}
+
+func (p *PrefixStats) child(prefix string) *PrefixStats {
+	if p.children == nil {
+		p.children = make(map[string]*PrefixStats)
+	}
+	c, ok := p.children[prefix]
+	if !ok {
+		c = &PrefixStats{Prefix: prefix, Depth: p.Depth + 1}
+		p.children[prefix] = c
+	}
+	return c
+}
+
+// PrefixSort is the order in which prefixes are listed
+type PrefixSort int
+
+const (
+	PrefixSortBytes PrefixSort = iota
+	PrefixSortKeys
+	PrefixSortTTL
+	PrefixSortName
+)
+
+func (s PrefixSort) String() string {
+	switch s {
+	case PrefixSortKeys:
+		return "keys"
+	case PrefixSortTTL:
+		return "TTL coverage"
+	case PrefixSortName:
+		return "name"
+	default:
+		return "memory"
+	}
+}
+
+// SortPrefixes orders prefixes, largest first except when sorting by name
+func SortPrefixes(prefixes []*PrefixStats, by PrefixSort) {
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		a, b := prefixes[i], prefixes[j]
+		switch by {
+		case PrefixSortKeys:
+			if a.Keys != b.Keys {
+				return a.Keys > b.Keys
+			}
+		case PrefixSortTTL:
+			if a.TTLCoverage() != b.TTLCoverage() {
+				return a.TTLCoverage() > b.TTLCoverage()
+			}
+		case PrefixSortName:
+		default:
+			if a.Bytes != b.Bytes {
+				return a.Bytes > b.Bytes
+			}
+		}
+		return a.Prefix < b.Prefix
+	})
+}
+
+// PrefixReport aggregates key counts, memory and TTL coverage per key prefix
+// as a tree, splitting keys on Delimiter up to MaxDepth levels
+type PrefixReport struct {
+	Root      *PrefixStats
+	Delimiter string
+	MaxDepth  int
+}
+
+// NewPrefixReport creates an empty prefix report
+func NewPrefixReport(delimiter string, maxDepth int) *PrefixReport {
+	if maxDepth < 1 {
+		maxDepth = 1
+	}
+	return &PrefixReport{
+		Root:      &PrefixStats{},
+		Delimiter: delimiter,
+		MaxDepth:  maxDepth,
+	}
+}
+
+// Add accounts for a batch of key statistics. A key is counted under each of
+// its prefixes up to MaxDepth; the last segment of a key is never a prefix,
+// so keys without a delimiter only count towards the root.
+func (r *PrefixReport) Add(stats []KeyStat) {
+	for _, s := range stats {
+		r.Root.add(s)
+
+		var segments []string
+		if r.Delimiter != "" {
+			segments = strings.Split(s.Key, r.Delimiter)
+		}
+		depth := len(segments) - 1
+		if depth > r.MaxDepth {
+			depth = r.MaxDepth
+		}
+
+		node := r.Root
+		prefix := ""
+		for i := 0; i < depth; i++ {
+			prefix += segments[i] + r.Delimiter
+			node = node.child(prefix)
+			node.add(s)
+		}
+	}
+}
+
+// AnalysePrefixes scans the keyspace with ScanKeyStats and aggregates the keys
+// per prefix. progress, when set, receives the number of keys analysed so far.
+func AnalysePrefixes(ctx context.Context, rdb redis.UniversalClient, opts ScanStatsOptions,
+	delimiter string, maxDepth int, progress func(scanned int)) (*PrefixReport, error) {
+	opts.Memory = true
+
+	report := NewPrefixReport(delimiter, maxDepth)
+	err := ScanKeyStats(ctx, rdb, opts, func(stats []KeyStat) error {
+		report.Add(stats)
+		if progress != nil {
+			progress(int(report.Root.Keys))
+		}
+		return nil
+	})
+	return report, err
+}
@@ -0,0 +1,257 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// KeyRecord is a key with its type, TTL and either its value or its DUMP
+// payload, as written by exports
+type KeyRecord struct {
+	Key  string `json:"key"`
+	Type string `json:"type"`
+	// TTLMillis is the remaining time to live, -1 when the key does not expire
+	TTLMillis int64 `json:"ttl_ms"`
+	// Value depends on Type: a string, a list of strings for lists and sets,
+	// a field map for hashes, a list of ZMember or of StreamEntry. It is nil
+	// for types whose value cannot be read, such as module types.
+	Value interface{} `json:"value,omitempty"`
+	// Dump is the DUMP payload, binary safe and restorable with RESTORE
+	Dump []byte `json:"dump,omitempty"`
+}
+
+// ZMember is a sorted set member with its score
+type ZMember struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// zmemberJSON is the JSON form of a ZMember. JSON has no infinity: infinite
+// scores are written as the strings "+inf" and "-inf" that ZADD accepts.
+type zmemberJSON struct {
+	Member string          `json:"member"`
+	Score  json.RawMessage `json:"score"`
+}
+
+// MarshalJSON implements json.Marshaler
+func (m ZMember) MarshalJSON() ([]byte, error) {
+	score := strconv.FormatFloat(m.Score, 'g', -1, 64)
+	if math.IsInf(m.Score, 0) {
+		score = `"` + strings.ToLower(score) + `"`
+	}
+	return json.Marshal(zmemberJSON{Member: m.Member, Score: json.RawMessage(score)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *ZMember) UnmarshalJSON(b []byte) error {
+	var raw zmemberJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	score, err := strconv.ParseFloat(strings.Trim(string(raw.Score), `"`), 64)
+	if err != nil {
+		return fmt.Errorf("invalid score %s", raw.Score)
+	}
+	m.Member, m.Score = raw.Member, score
+	return nil
+}
+
+// StreamEntry is a stream entry with its fields, in the order they were
+// added. A field may appear more than once.
+type StreamEntry struct {
+	ID     string        `json:"id"`
+	Fields []StreamField `json:"fields"`
+}
+
+// StreamField is a field of a stream entry
+type StreamField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Fields written as an object by
+// earlier versions are read in the order of their names.
+func (e *StreamEntry) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		ID     string          `json:"id"`
+		Fields json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	e.ID, e.Fields = raw.ID, nil
+	if len(raw.Fields) == 0 {
+		return nil
+	}
+	if raw.Fields[0] != '{' {
+		return json.Unmarshal(raw.Fields, &e.Fields)
+	}
+	var fields map[string]string
+	if err := json.Unmarshal(raw.Fields, &fields); err != nil {
+		return err
+	}
+	for _, name := range SortedFields(fields) {
+		e.Fields = append(e.Fields, StreamField{Name: name, Value: fields[name]})
+	}
+	return nil
+}
+
+// ScanRecords scans the keys of opts in batches and loads their records,
+// with DUMP payloads instead of values when dump is set. fn is called with
+// each batch; returning an error stops the scan.
+func ScanRecords(ctx context.Context, rdb redis.UniversalClient, opts ScanStatsOptions, dump bool,
+	fn func([]KeyRecord) error) error {
+	return scanBatches(ctx, rdb, opts, func(keys []string) error {
+		records, err := LoadRecords(ctx, rdb, keys, dump)
+		if err != nil {
+			return err
+		}
+		return fn(records)
+	})
+}
+
+// LoadRecords loads the records of keys in two pipelines: TYPE and PTTL,
+// then the type-specific read or DUMP. Keys deleted in between are left out;
+// keys of types without a read command keep a nil Value, so that callers can
+// report them.
+func LoadRecords(ctx context.Context, rdb redis.UniversalClient, keys []string, dump bool) ([]KeyRecord, error) {
+	pipe := rdb.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(keys))
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		typeCmds[i] = pipe.Type(ctx, key)
+		ttlCmds[i] = pipe.PTTL(ctx, key)
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return nil, err
+	}
+
+	records := make([]KeyRecord, 0, len(keys))
+	for i, key := range keys {
+		keyType := typeCmds[i].Val()
+		if keyType == "" || keyType == "none" {
+			continue
+		}
+		record := KeyRecord{Key: key, Type: keyType, TTLMillis: -1}
+		if ttl := ttlCmds[i].Val(); ttl > 0 {
+			record.TTLMillis = int64(ttl / time.Millisecond)
+		}
+		records = append(records, record)
+	}
+	if len(records) == 0 {
+		return records, nil
+	}
+
+	pipe = rdb.Pipeline()
+	valueCmds := make([]redis.Cmder, len(records))
+	for i, r := range records {
+		if dump {
+			valueCmds[i] = pipe.Dump(ctx, r.Key)
+		} else {
+			valueCmds[i] = queueValue(ctx, pipe, r.Key, r.Type)
+		}
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return nil, err
+	}
+
+	loaded := records[:0]
+	for i, r := range records {
+		cmd := valueCmds[i]
+		if cmd == nil {
+			loaded = append(loaded, r)
+			continue
+		}
+		if cmd.Err() != nil {
+			// Expired or replaced by another type since TYPE
+			continue
+		}
+		if dump {
+			r.Dump = []byte(cmd.(*redis.StringCmd).Val())
+		} else {
+			r.Value = commandValue(cmd)
+		}
+		loaded = append(loaded, r)
+	}
+	return loaded, nil
+}
+
+// queueValue queues the command reading the whole value of a key, nil for
+// unsupported types
+func queueValue(ctx context.Context, pipe redis.Pipeliner, key, keyType string) redis.Cmder {
+	switch keyType {
+	case "string":
+		return pipe.Get(ctx, key)
+	case "list":
+		return pipe.LRange(ctx, key, 0, -1)
+	case "set":
+		return pipe.SMembers(ctx, key)
+	case "zset":
+		return pipe.ZRangeWithScores(ctx, key, 0, -1)
+	case "hash":
+		return pipe.HGetAll(ctx, key)
+	case "stream":
+		// XMessage keeps the fields in a map, the raw reply keeps their order
+		cmd := redis.NewSliceCmd(ctx, "xrange", key, "-", "+")
+		_ = pipe.Process(ctx, cmd)
+		return cmd
+	}
+	return nil
+}
+
+// commandValue converts the reply of a queueValue command to a record value
+func commandValue(cmd redis.Cmder) interface{} {
+	switch cmd := cmd.(type) {
+	case *redis.StringCmd:
+		return cmd.Val()
+	case *redis.StringSliceCmd:
+		return cmd.Val()
+	case *redis.StringStringMapCmd:
+		return cmd.Val()
+	case *redis.ZSliceCmd:
+		members := make([]ZMember, len(cmd.Val()))
+		for i, z := range cmd.Val() {
+			members[i] = ZMember{Member: toString(z.Member), Score: z.Score}
+		}
+		return members
+	case *redis.SliceCmd:
+		return streamEntries(cmd.Val())
+	}
+	return nil
+}
+
+// streamEntries converts an XRANGE reply, a list of [id, [name, value, ...]]
+func streamEntries(reply []interface{}) []StreamEntry {
+	entries := make([]StreamEntry, 0, len(reply))
+	for _, item := range reply {
+		entry, ok := item.([]interface{})
+		if !ok || len(entry) != 2 {
+			continue
+		}
+		values, _ := entry[1].([]interface{})
+		fields := make([]StreamField, 0, len(values)/2)
+		for j := 0; j+1 < len(values); j += 2 {
+			fields = append(fields, StreamField{Name: toString(values[j]), Value: toString(values[j+1])})
+		}
+		entries = append(entries, StreamEntry{ID: toString(entry[0]), Fields: fields})
+	}
+	return entries
+}
+
+// SortedFields returns the field names of a hash value in order
+func SortedFields(hash map[string]string) []string {
+	fields := make([]string, 0, len(hash))
+	for field := range hash {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
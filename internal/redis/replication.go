@@ -0,0 +1,257 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ReplicaLink is a replica as seen from its master in INFO replication
+type ReplicaLink struct {
+	Addr   string
+	State  string // online, wait_bgsave, send_bulk...
+	Offset int64
+	Behind int64 // bytes of replication stream not yet acknowledged
+	Lag    int64 // seconds since the last acknowledgement
+}
+
+// ReplicationInfo is the INFO replication section of a node
+type ReplicationInfo struct {
+	Node   string
+	Role   string // master or slave
+	Offset int64  // master_repl_offset
+
+	// Master side
+	Replicas []ReplicaLink
+
+	// Replica side
+	MasterAddr     string
+	LinkStatus     string // up or down
+	LastIOSeconds  int64
+	SyncInProgress bool
+}
+
+// GetReplication reads INFO replication from the server, from every master
+// in cluster mode
+func GetReplication(rdb redis.UniversalClient) ([]ReplicationInfo, error) {
+	ctx := context.TODO()
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		var (
+			infos []ReplicationInfo
+			mu    sync.Mutex
+		)
+		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			raw, err := client.Info(ctx, "replication").Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			infos = append(infos, parseReplication(raw, client.Options().Addr))
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].Node < infos[j].Node })
+		return infos, nil
+	default:
+		raw, err := rdb.Info(ctx, "replication").Result()
+		if err != nil {
+			return nil, err
+		}
+		return []ReplicationInfo{parseReplication(raw, nodeAddr(rdb))}, nil
+	}
+}
+
+// parseReplication parses INFO replication. Replicas are listed by the master
+// as "slaveN:ip=...,port=...,state=...,offset=...,lag=...".
+func parseReplication(raw, node string) ReplicationInfo {
+	fields := parseInfo(raw)
+	info := ReplicationInfo{
+		Node:           node,
+		Role:           fields["role"],
+		Offset:         parseInt64(fields["master_repl_offset"]),
+		LinkStatus:     fields["master_link_status"],
+		LastIOSeconds:  parseInt64(fields["master_last_io_seconds_ago"]),
+		SyncInProgress: fields["master_sync_in_progress"] == "1",
+	}
+	if host := fields["master_host"]; host != "" {
+		info.MasterAddr = net.JoinHostPort(host, fields["master_port"])
+	}
+
+	for i := 0; ; i++ {
+		raw, ok := fields[fmt.Sprintf("slave%d", i)]
+		if !ok {
+			break
+		}
+		attrs := make(map[string]string)
+		for _, kv := range strings.Split(raw, ",") {
+			if parts := strings.SplitN(kv, "=", 2); len(parts) == 2 {
+				attrs[parts[0]] = parts[1]
+			}
+		}
+		link := ReplicaLink{
+			Addr:   net.JoinHostPort(attrs["ip"], attrs["port"]),
+			State:  attrs["state"],
+			Offset: parseInt64(attrs["offset"]),
+			Lag:    parseInt64(attrs["lag"]),
+		}
+		if info.Offset > link.Offset {
+			link.Behind = info.Offset - link.Offset
+		}
+		info.Replicas = append(info.Replicas, link)
+	}
+
+	return info
+}
+
+// SentinelPeer is a replica or another sentinel as reported by a sentinel
+type SentinelPeer struct {
+	Addr  string
+	Flags string
+	// Replicas
+	LinkStatus string
+	Offset     int64
+	Priority   int64
+	// Sentinels
+	LastOKPingMillis int64
+	VotedLeader      string
+}
+
+// SentinelMaster is a master monitored by the sentinels
+type SentinelMaster struct {
+	Name              string
+	Addr              string
+	Flags             string
+	Quorum            int64
+	ConfigEpoch       int64
+	NumReplicas       int64
+	NumOtherSentinels int64
+	QuorumStatus      string // SENTINEL CKQUORUM reply or error
+	QuorumOK          bool
+
+	Replicas  []SentinelPeer
+	Sentinels []SentinelPeer
+}
+
+// FailoverInProgress reports whether the sentinels are failing the master over
+func (m SentinelMaster) FailoverInProgress() bool {
+	return strings.Contains(m.Flags, "failover_in_progress")
+}
+
+// Down reports whether the master is subjectively or objectively down
+func (m SentinelMaster) Down() bool {
+	return strings.Contains(m.Flags, "s_down") || strings.Contains(m.Flags, "o_down")
+}
+
+// SentinelStatus is the view of the sentinel that answered
+type SentinelStatus struct {
+	Sentinel string // address of the sentinel queried
+	Masters  []SentinelMaster
+}
+
+// GetSentinelStatus queries the first reachable sentinel of opts.Addrs for
+// SENTINEL MASTERS, and for each master its REPLICAS, SENTINELS and CKQUORUM
+func GetSentinelStatus(opts *Options) (*SentinelStatus, error) {
+	if opts.MasterName == "" {
+		return nil, errors.New("not connected through sentinel (master_name is not set)")
+	}
+
+	ctx := context.TODO()
+	var lastErr error
+	for _, addr := range opts.Addrs {
+		sentinel := redis.NewSentinelClient(&redis.Options{
+			Addr:       addr,
+			Username:   opts.SentinelUsername,
+			Password:   opts.SentinelPassword,
+			MaxRetries: opts.MaxRetries,
+		})
+		status, err := sentinelStatus(ctx, sentinel, addr)
+		_ = sentinel.Close()
+		if err == nil {
+			return status, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("no sentinel reachable: %w", lastErr)
+}
+
+func sentinelStatus(ctx context.Context, sentinel *redis.SentinelClient, addr string) (*SentinelStatus, error) {
+	masters, err := sentinel.Masters(ctx).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SentinelStatus{Sentinel: addr}
+	for _, m := range masters {
+		attrs := stringPairs(m)
+		master := SentinelMaster{
+			Name:              attrs["name"],
+			Addr:              net.JoinHostPort(attrs["ip"], attrs["port"]),
+			Flags:             attrs["flags"],
+			Quorum:            parseInt64(attrs["quorum"]),
+			ConfigEpoch:       parseInt64(attrs["config-epoch"]),
+			NumReplicas:       parseInt64(attrs["num-slaves"]),
+			NumOtherSentinels: parseInt64(attrs["num-other-sentinels"]),
+		}
+
+		if replicas, err := sentinel.Slaves(ctx, master.Name).Result(); err == nil {
+			for _, r := range replicas {
+				attrs := stringPairs(r)
+				master.Replicas = append(master.Replicas, SentinelPeer{
+					Addr:       net.JoinHostPort(attrs["ip"], attrs["port"]),
+					Flags:      attrs["flags"],
+					LinkStatus: attrs["master-link-status"],
+					Offset:     parseInt64(attrs["slave-repl-offset"]),
+					Priority:   parseInt64(attrs["slave-priority"]),
+				})
+			}
+		}
+
+		if sentinels, err := sentinel.Sentinels(ctx, master.Name).Result(); err == nil {
+			for _, s := range sentinels {
+				attrs := stringPairs(s)
+				master.Sentinels = append(master.Sentinels, SentinelPeer{
+					Addr:             net.JoinHostPort(attrs["ip"], attrs["port"]),
+					Flags:            attrs["flags"],
+					LastOKPingMillis: parseInt64(attrs["last-ok-ping-reply"]),
+					VotedLeader:      attrs["voted-leader"],
+				})
+			}
+		}
+
+		// CKQUORUM replies with an error when the quorum cannot be reached
+		quorum, err := sentinel.CkQuorum(ctx, master.Name).Result()
+		if err != nil {
+			master.QuorumStatus = err.Error()
+		} else {
+			master.QuorumStatus = quorum
+			master.QuorumOK = true
+		}
+
+		status.Masters = append(status.Masters, master)
+	}
+
+	sort.Slice(status.Masters, func(i, j int) bool { return status.Masters[i].Name < status.Masters[j].Name })
+	return status, nil
+}
+
+// stringPairs turns a flat [name, value, ...] reply of strings into a map
+func stringPairs(v interface{}) map[string]string {
+	m := make(map[string]string)
+	for name, value := range pairs(v) {
+		if s, ok := value.(string); ok {
+			m[name] = s
+		}
+	}
+	return m
+}
@@ -0,0 +1,220 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ConflictPolicy decides what happens to records whose key already exists
+type ConflictPolicy int
+
+const (
+	// ConflictFail leaves existing keys untouched and counts them as failures
+	ConflictFail ConflictPolicy = iota
+	// ConflictSkip leaves existing keys untouched
+	ConflictSkip
+	// ConflictReplace overwrites existing keys
+	ConflictReplace
+)
+
+// ErrKeyExists is reported for existing keys under ConflictFail
+var ErrKeyExists = errors.New("key already exists")
+
+// RestoreFailure is a record that could not be written
+type RestoreFailure struct {
+	Key string
+	Err error
+}
+
+// RestoreStats counts the outcome of RestoreRecords. In a dry run Created
+// and Replaced count the keys that would have been written.
+type RestoreStats struct {
+	Created  int
+	Replaced int
+	Skipped  int
+	Failures []RestoreFailure
+}
+
+// Failed returns the number of records that could not be written
+func (s RestoreStats) Failed() int {
+	return len(s.Failures)
+}
+
+// Add accumulates the counts of another batch
+func (s *RestoreStats) Add(o RestoreStats) {
+	s.Created += o.Created
+	s.Replaced += o.Replaced
+	s.Skipped += o.Skipped
+	s.Failures = append(s.Failures, o.Failures...)
+}
+
+// RestoreRecords writes a batch of records: with RESTORE for records
+// holding a DUMP payload, with the type-specific write commands and PEXPIRE
+// otherwise. Existence is checked in one pipeline, then the writes of the
+// batch are sent in a single MULTI/EXEC pipeline so that replacing a key is
+// atomic. Nothing is written in a dry run.
+func RestoreRecords(ctx context.Context, rdb redis.UniversalClient, records []KeyRecord,
+	policy ConflictPolicy, dryRun bool) (RestoreStats, error) {
+	var stats RestoreStats
+
+	pipe := rdb.Pipeline()
+	existsCmds := make([]*redis.IntCmd, len(records))
+	for i, r := range records {
+		existsCmds[i] = pipe.Exists(ctx, r.Key)
+	}
+	if err := execPipeline(ctx, pipe); err != nil {
+		return stats, err
+	}
+
+	type write struct {
+		record KeyRecord
+		exists bool
+		cmds   []redis.Cmder
+	}
+	var writes []write
+	for i, r := range records {
+		exists := existsCmds[i].Val() > 0
+		if exists {
+			switch policy {
+			case ConflictSkip:
+				stats.Skipped++
+				continue
+			case ConflictFail:
+				stats.Failures = append(stats.Failures, RestoreFailure{Key: r.Key, Err: ErrKeyExists})
+				continue
+			}
+		}
+		if err := checkRecord(r); err != nil {
+			stats.Failures = append(stats.Failures, RestoreFailure{Key: r.Key, Err: err})
+			continue
+		}
+		writes = append(writes, write{record: r, exists: exists})
+	}
+
+	if dryRun {
+		for _, w := range writes {
+			if w.exists {
+				stats.Replaced++
+			} else {
+				stats.Created++
+			}
+		}
+		return stats, nil
+	}
+	if len(writes) == 0 {
+		return stats, nil
+	}
+
+	tx := rdb.TxPipeline()
+	for i := range writes {
+		writes[i].cmds = queueRestore(ctx, tx, writes[i].record, writes[i].exists)
+	}
+	if err := execPipeline(ctx, tx); err != nil {
+		return stats, err
+	}
+
+	for _, w := range writes {
+		var err error
+		for _, cmd := range w.cmds {
+			if err = cmd.Err(); err != nil {
+				break
+			}
+		}
+		switch {
+		case err != nil:
+			stats.Failures = append(stats.Failures, RestoreFailure{Key: w.record.Key, Err: err})
+		case w.exists:
+			stats.Replaced++
+		default:
+			stats.Created++
+		}
+	}
+	return stats, nil
+}
+
+// checkRecord rejects records that cannot be written
+func checkRecord(r KeyRecord) error {
+	if r.Dump != nil {
+		return nil
+	}
+	switch v := r.Value.(type) {
+	case string:
+		return nil
+	case []string:
+		if len(v) > 0 {
+			return nil
+		}
+	case map[string]string:
+		if len(v) > 0 {
+			return nil
+		}
+	case []ZMember:
+		if len(v) > 0 {
+			return nil
+		}
+	case []StreamEntry:
+		if len(v) > 0 {
+			return nil
+		}
+	case nil:
+		return errors.New("record has neither a value nor a dump")
+	default:
+		return fmt.Errorf("unsupported %s value", r.Type)
+	}
+	return fmt.Errorf("empty %s cannot be created", r.Type)
+}
+
+// queueRestore queues the commands recreating a record and returns them
+func queueRestore(ctx context.Context, pipe redis.Pipeliner, r KeyRecord, exists bool) []redis.Cmder {
+	ttl := time.Duration(0)
+	if r.TTLMillis > 0 {
+		ttl = time.Duration(r.TTLMillis) * time.Millisecond
+	}
+
+	if r.Dump != nil {
+		if exists {
+			return []redis.Cmder{pipe.RestoreReplace(ctx, r.Key, ttl, string(r.Dump))}
+		}
+		return []redis.Cmder{pipe.Restore(ctx, r.Key, ttl, string(r.Dump))}
+	}
+
+	var cmds []redis.Cmder
+	if exists {
+		cmds = append(cmds, pipe.Del(ctx, r.Key))
+	}
+	switch v := r.Value.(type) {
+	case string:
+		// SET with PX sets the value and the TTL at once
+		return append(cmds, pipe.Set(ctx, r.Key, v, ttl))
+	case []string:
+		if r.Type == "set" {
+			cmds = append(cmds, pipe.SAdd(ctx, r.Key, v))
+		} else {
+			cmds = append(cmds, pipe.RPush(ctx, r.Key, v))
+		}
+	case map[string]string:
+		cmds = append(cmds, pipe.HSet(ctx, r.Key, v))
+	case []ZMember:
+		members := make([]*redis.Z, len(v))
+		for i, m := range v {
+			members[i] = &redis.Z{Member: m.Member, Score: m.Score}
+		}
+		cmds = append(cmds, pipe.ZAdd(ctx, r.Key, members...))
+	case []StreamEntry:
+		for _, e := range v {
+			values := make([]string, 0, 2*len(e.Fields))
+			for _, f := range e.Fields {
+				values = append(values, f.Name, f.Value)
+			}
+			cmds = append(cmds, pipe.XAdd(ctx, &redis.XAddArgs{Stream: r.Key, ID: e.ID, Values: values}))
+		}
+	}
+	if ttl > 0 {
+		cmds = append(cmds, pipe.PExpire(ctx, r.Key, ttl))
+	}
+	return cmds
+}
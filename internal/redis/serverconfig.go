@@ -0,0 +1,309 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ConfigParam is a server configuration parameter reported by CONFIG GET
+type ConfigParam struct {
+	Name       string
+	Value      string
+	Default    string
+	HasDefault bool // false for parameters without a known default
+	// Inconsistent is set in cluster mode when masters report different values
+	Inconsistent bool
+}
+
+// Modified reports whether the value differs from the known default
+func (p ConfigParam) Modified() bool {
+	return p.HasDefault && p.Value != p.Default
+}
+
+// configDefaults are the defaults as CONFIG GET reports them, for the
+// parameters most often tuned. Only defaults shared by Redis 5 to 7 are
+// listed, so that a parameter is never reported as modified for running
+// with the default of its version. Parameters depending on the build or on
+// the deployment (port, maxclients, dir, bind...) are left out as well.
+var configDefaults = map[string]string{
+	"activedefrag":                  "no",
+	"activerehashing":               "yes",
+	"active-expire-effort":          "1",
+	"appendfilename":                "appendonly.aof",
+	"appendfsync":                   "everysec",
+	"appendonly":                    "no",
+	"busy-reply-threshold":          "5000",
+	"client-output-buffer-limit":    "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+	"client-query-buffer-limit":     "1073741824",
+	"cluster-node-timeout":          "15000",
+	"cluster-require-full-coverage": "yes",
+	"databases":                     "16",
+	"dbfilename":                    "dump.rdb",
+	"dynamic-hz":                    "yes",
+	"hash-max-listpack-entries":     "128",
+	"hash-max-listpack-value":       "64",
+	"hash-max-ziplist-entries":      "128",
+	"hash-max-ziplist-value":        "64",
+	"hz":                            "10",
+	"io-threads":                    "1",
+	"latency-monitor-threshold":     "0",
+	"lfu-decay-time":                "1",
+	"lfu-log-factor":                "10",
+	"list-compress-depth":           "0",
+	"list-max-listpack-size":        "-2",
+	"list-max-ziplist-size":         "-2",
+	"loglevel":                      "notice",
+	"lua-time-limit":                "5000",
+	"maxmemory":                     "0",
+	"maxmemory-clients":             "0",
+	"maxmemory-policy":              "noeviction",
+	"maxmemory-samples":             "5",
+	"min-replicas-max-lag":          "10",
+	"min-replicas-to-write":         "0",
+	"notify-keyspace-events":        "",
+	"proto-max-bulk-len":            "536870912",
+	"protected-mode":                "yes",
+	"rdbchecksum":                   "yes",
+	"rdbcompression":                "yes",
+	"repl-backlog-size":             "1048576",
+	"repl-backlog-ttl":              "3600",
+	"repl-timeout":                  "60",
+	"replica-read-only":             "yes",
+	"replica-serve-stale-data":      "yes",
+	"set-max-intset-entries":        "512",
+	"slowlog-log-slower-than":       "10000",
+	"slowlog-max-len":               "128",
+	"stop-writes-on-bgsave-error":   "yes",
+	"stream-node-max-bytes":         "4096",
+	"stream-node-max-entries":       "100",
+	"tcp-backlog":                   "511",
+	"tcp-keepalive":                 "300",
+	"timeout":                       "0",
+	"zset-max-listpack-entries":     "128",
+	"zset-max-listpack-value":       "64",
+	"zset-max-ziplist-entries":      "128",
+	"zset-max-ziplist-value":        "64",
+}
+
+// configShapes are example values of parameters whose default depends on
+// the version or the deployment, giving the shape ValidateConfigValue
+// expects of their value
+var configShapes = map[string]string{
+	"lazyfree-lazy-eviction":   "no",
+	"lazyfree-lazy-expire":     "no",
+	"lazyfree-lazy-server-del": "no",
+	"lazyfree-lazy-user-del":   "no",
+	"maxclients":               "10000",
+	"port":                     "6379",
+	"repl-diskless-sync":       "no",
+}
+
+// configEnums are the accepted values of enumerated parameters
+var configEnums = map[string][]string{
+	"appendfsync":      {"always", "everysec", "no"},
+	"loglevel":         {"debug", "verbose", "notice", "warning", "nothing"},
+	"maxmemory-policy": {"volatile-lru", "allkeys-lru", "volatile-lfu", "allkeys-lfu", "volatile-random", "allkeys-random", "volatile-ttl", "noeviction"},
+}
+
+// configMemory are the parameters taking a memory size with an optional unit
+var configMemory = map[string]bool{
+	"active-defrag-ignore-bytes": true,
+	"client-query-buffer-limit":  true,
+	"maxmemory":                  true,
+	"maxmemory-clients":          true,
+	"proto-max-bulk-len":         true,
+	"repl-backlog-size":          true,
+	"stream-node-max-bytes":      true,
+}
+
+// GetConfig reads every parameter with CONFIG GET *, sorted by name. In
+// cluster mode the values of the first master by address are reported and
+// parameters the masters disagree on are flagged.
+func GetConfig(rdb redis.UniversalClient) ([]ConfigParam, error) {
+	ctx := context.TODO()
+
+	var values map[string]string
+	inconsistent := make(map[string]bool)
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		var (
+			nodes = make(map[string]map[string]string)
+			mu    sync.Mutex
+		)
+		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			raw, err := client.ConfigGet(ctx, "*").Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			nodes[client.Options().Addr] = configValues(raw)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		addrs := make([]string, 0, len(nodes))
+		for addr := range nodes {
+			addrs = append(addrs, addr)
+		}
+		sort.Strings(addrs)
+		for _, addr := range addrs {
+			if values == nil {
+				values = nodes[addr]
+				continue
+			}
+			for name, value := range nodes[addr] {
+				if values[name] != value {
+					inconsistent[name] = true
+				}
+			}
+		}
+	default:
+		raw, err := rdb.ConfigGet(ctx, "*").Result()
+		if err != nil {
+			return nil, err
+		}
+		values = configValues(raw)
+	}
+
+	params := make([]ConfigParam, 0, len(values))
+	for name, value := range values {
+		def, ok := configDefaults[name]
+		params = append(params, ConfigParam{
+			Name:         name,
+			Value:        value,
+			Default:      def,
+			HasDefault:   ok,
+			Inconsistent: inconsistent[name],
+		})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params, nil
+}
+
+// configValues turns a CONFIG GET reply into a map
+func configValues(raw []interface{}) map[string]string {
+	values := make(map[string]string, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		name, _ := raw[i].(string)
+		value, _ := raw[i+1].(string)
+		values[name] = value
+	}
+	return values
+}
+
+// ValidateConfigValue checks a value before CONFIG SET. Only the shape of
+// the value is checked, from what is known about the parameter: the server
+// remains the judge of ranges and of parameters that cannot be set at runtime.
+func ValidateConfigValue(name, value string) error {
+	name = strings.ToLower(name)
+
+	if allowed, ok := configEnums[name]; ok {
+		for _, v := range allowed {
+			if strings.EqualFold(value, v) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of %s", name, strings.Join(allowed, ", "))
+	}
+
+	if configMemory[name] {
+		if _, err := parseMemory(value); err != nil && !(name == "maxmemory-clients" && strings.HasSuffix(value, "%")) {
+			return fmt.Errorf("%s must be a size such as 100mb: %w", name, err)
+		}
+		return nil
+	}
+
+	def, ok := configDefaults[name]
+	if !ok {
+		def, ok = configShapes[name]
+	}
+	if !ok {
+		return nil
+	}
+	switch {
+	case def == "yes" || def == "no":
+		if !strings.EqualFold(value, "yes") && !strings.EqualFold(value, "no") {
+			return fmt.Errorf("%s must be yes or no", name)
+		}
+	case isInteger(def):
+		if !isInteger(value) {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+	}
+	return nil
+}
+
+// parseMemory parses a memory size the way redis.conf does: a number with an
+// optional k, kb, m, mb, g or gb unit (k is 1000, kb is 1024)
+func parseMemory(s string) (int64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	units := []struct {
+		suffix string
+		mul    int64
+	}{
+		{"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10},
+		{"g", 1000 * 1000 * 1000}, {"m", 1000 * 1000}, {"k", 1000}, {"b", 1},
+	}
+	mul := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s, mul = strings.TrimSuffix(s, u.suffix), u.mul
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mul, nil
+}
+
+func isInteger(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+// SetConfig changes a parameter with CONFIG SET, on every node in cluster
+// mode so that replicas keep the setting after a failover
+func SetConfig(rdb redis.UniversalClient, name, value string) error {
+	ctx := context.TODO()
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		return rdb.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+			if err := client.ConfigSet(ctx, name, value).Err(); err != nil {
+				return fmt.Errorf("%s: %w", client.Options().Addr, err)
+			}
+			return nil
+		})
+	default:
+		return rdb.ConfigSet(ctx, name, value).Err()
+	}
+}
+
+// RewriteConfig persists the running configuration to the config file with
+// CONFIG REWRITE, on every node in cluster mode
+func RewriteConfig(rdb redis.UniversalClient) error {
+	ctx := context.TODO()
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		return rdb.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+			if err := client.ConfigRewrite(ctx).Err(); err != nil {
+				return fmt.Errorf("%s: %w", client.Options().Addr, err)
+			}
+			return nil
+		})
+	default:
+		return rdb.ConfigRewrite(ctx).Err()
+	}
+}
@@ -0,0 +1,209 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SlotCount is the number of hash slots of a Redis Cluster
+const SlotCount = 16384
+
+// KeySlot returns the hash slot of a key, as CLUSTER KEYSLOT does: CRC16 of
+// the key, or of its hash tag when it has a non-empty {...} section
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % SlotCount)
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum used for key slots
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for b := 0; b < 8; b++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// KeyScope restricts cluster scans to a single master and/or a range of hash
+// slots. The zero value covers the whole keyspace.
+type KeyScope struct {
+	Node  string     // master address, empty for every master
+	Slots *SlotRange // nil for every slot
+}
+
+// IsZero reports whether the scope covers the whole keyspace
+func (s KeyScope) IsZero() bool {
+	return s.Node == "" && s.Slots == nil
+}
+
+// Contains reports whether a key falls in the slot range of the scope
+func (s KeyScope) Contains(key string) bool {
+	if s.Slots == nil {
+		return true
+	}
+	slot := KeySlot(key)
+	return slot >= s.Slots.Start && slot <= s.Slots.End
+}
+
+func (s KeyScope) String() string {
+	var parts []string
+	if s.Node != "" {
+		parts = append(parts, "node "+s.Node)
+	}
+	if s.Slots != nil {
+		parts = append(parts, "slots "+s.Slots.String())
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseKeyScope parses a node address (host:port), a slot or slot range
+// ("42", "0-5460"), or both separated by a space. An empty string is the
+// whole keyspace.
+func ParseKeyScope(s string) (KeyScope, error) {
+	var scope KeyScope
+	for _, field := range strings.Fields(s) {
+		if strings.Contains(field, ":") {
+			if scope.Node != "" {
+				return KeyScope{}, fmt.Errorf("more than one node in %q", s)
+			}
+			scope.Node = field
+			continue
+		}
+
+		r, ok := parseSlotRange(field)
+		if !ok || r.Start < 0 || r.End >= SlotCount || r.Start > r.End {
+			return KeyScope{}, fmt.Errorf("invalid slot range %q: use 0-%d", field, SlotCount-1)
+		}
+		if scope.Slots != nil {
+			return KeyScope{}, fmt.Errorf("more than one slot range in %q", s)
+		}
+		scope.Slots = &r
+	}
+	return scope, nil
+}
+
+// forEachMasterInScope runs fn on every master of the scope, concurrently
+// like ForEachMaster. It fails when the scope names an unknown master.
+func forEachMasterInScope(ctx context.Context, rdb *redis.ClusterClient, scope KeyScope,
+	fn func(ctx context.Context, client *redis.Client) error) error {
+	var found int32
+	err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		if scope.Node != "" && client.Options().Addr != scope.Node {
+			return nil
+		}
+		atomic.StoreInt32(&found, 1)
+		return fn(ctx, client)
+	})
+	if err == nil && scope.Node != "" && atomic.LoadInt32(&found) == 0 {
+		return fmt.Errorf("%s is not a cluster master", scope.Node)
+	}
+	return err
+}
+
+// firstMaster returns the master with the lowest address, so that commands
+// reading per-node state such as ACLs see a single, stable node
+func firstMaster(ctx context.Context, rdb *redis.ClusterClient) (*redis.Client, error) {
+	var (
+		first *redis.Client
+		mu    sync.Mutex
+	)
+	err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if first == nil || client.Options().Addr < first.Options().Addr {
+			first = client
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if first == nil {
+		return nil, errors.New("no master known")
+	}
+	return first, nil
+}
+
+// forEachScanNode runs fn on one node of every shard of the scope. When the
+// client lets replicas serve reads, a replica of each master is scanned so
+// that browsing and analysis scans stay off the masters; masters without a
+// replica answering PING are scanned themselves.
+func forEachScanNode(ctx context.Context, rdb *redis.ClusterClient, scope KeyScope,
+	fn func(ctx context.Context, client *redis.Client) error) error {
+	if opt := rdb.Options(); !opt.ReadOnly && !opt.RouteByLatency && !opt.RouteRandomly {
+		return forEachMasterInScope(ctx, rdb, scope, fn)
+	}
+
+	slots, err := rdb.ClusterSlots(ctx).Result()
+	if err != nil {
+		return err
+	}
+	replicasOf := make(map[string][]string)
+	for _, slot := range slots {
+		if len(slot.Nodes) == 0 {
+			continue
+		}
+		master := slot.Nodes[0].Addr
+		for _, node := range slot.Nodes[1:] {
+			if !containsString(replicasOf[master], node.Addr) {
+				replicasOf[master] = append(replicasOf[master], node.Addr)
+			}
+		}
+	}
+	replicas := healthyReplicas(ctx, rdb)
+
+	return forEachMasterInScope(ctx, rdb, scope, func(ctx context.Context, master *redis.Client) error {
+		for _, addr := range replicasOf[master.Options().Addr] {
+			if replica, ok := replicas[addr]; ok {
+				return fn(ctx, replica)
+			}
+		}
+		return fn(ctx, master)
+	})
+}
+
+// healthyReplicas returns the replicas known to a cluster client that answer
+// PING, by address
+func healthyReplicas(ctx context.Context, rdb *redis.ClusterClient) map[string]*redis.Client {
+	var mu sync.Mutex
+	replicas := make(map[string]*redis.Client)
+	_ = rdb.ForEachSlave(ctx, func(ctx context.Context, client *redis.Client) error {
+		if client.Ping(ctx).Err() != nil {
+			return nil
+		}
+		mu.Lock()
+		replicas[client.Options().Addr] = client
+		mu.Unlock()
+		return nil
+	})
+	return replicas
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package redis
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SlowLogEntry is a SLOWLOG entry together with the node that recorded it
+type SlowLogEntry struct {
+	Node       string
+	ID         int64
+	Time       time.Time
+	Duration   time.Duration
+	Args       []string
+	ClientAddr string
+	ClientName string
+}
+
+// GetSlowLog retrieves up to count entries from SLOWLOG GET. In cluster mode
+// every master is queried.
+func GetSlowLog(rdb redis.UniversalClient, count int64) ([]SlowLogEntry, error) {
+	ctx := context.TODO()
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		var (
+			entries []SlowLogEntry
+			mu      sync.Mutex
+		)
+		err := rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			logs, err := client.SlowLogGet(ctx, count).Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			entries = append(entries, toSlowLogEntries(client.Options().Addr, logs)...)
+			mu.Unlock()
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return entries, nil
+	default:
+		// SlowLogGet is not part of the UniversalClient interface
+		cmd := redis.NewSlowLogCmd(ctx, "slowlog", "get", count)
+		_ = rdb.Process(ctx, cmd)
+		logs, err := cmd.Result()
+		if err != nil {
+			return nil, err
+		}
+		return toSlowLogEntries(nodeAddr(rdb), logs), nil
+	}
+}
+
+// ResetSlowLog clears the slow log, on every master in cluster mode
+func ResetSlowLog(rdb redis.UniversalClient) error {
+	ctx := context.TODO()
+
+	switch rdb := rdb.(type) {
+	case *redis.ClusterClient:
+		return rdb.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return client.Do(ctx, "slowlog", "reset").Err()
+		})
+	default:
+		return rdb.Do(ctx, "slowlog", "reset").Err()
+	}
+}
+
+func toSlowLogEntries(node string, logs []redis.SlowLog) []SlowLogEntry {
+	entries := make([]SlowLogEntry, len(logs))
+	for i, l := range logs {
+		entries[i] = SlowLogEntry{
+			Node:       node,
+			ID:         l.ID,
+			Time:       l.Time,
+			Duration:   l.Duration,
+			Args:       l.Args,
+			ClientAddr: l.ClientAddr,
+			ClientName: l.ClientName,
+		}
+	}
+	return entries
+}
+
+// nodeAddr returns the address a non-cluster client is connected to
+func nodeAddr(rdb redis.UniversalClient) string {
+	if client, ok := rdb.(*redis.Client); ok {
+		return client.Options().Addr
+	}
+	return ""
+}
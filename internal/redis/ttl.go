@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// TTLBucket counts the keys whose remaining TTL is below MaxSeconds and at
+// least the previous bucket's bound. The last bucket has no upper bound.
+type TTLBucket struct {
+	Label      string
+	MaxSeconds int64 // exclusive upper bound, 0 for the last bucket
+	Keys       int64
+	Bytes      int64
+}
+
+// ExpiryForecast counts the keys that expire within a time window
+type ExpiryForecast struct {
+	Label   string
+	Seconds int64
+	Keys    int64
+	Bytes   int64
+}
+
+// TTLReport is the TTL distribution of a set of keys
+type TTLReport struct {
+	Scanned    int64
+	Bytes      int64
+	WithoutTTL int64
+	NoTTLBytes int64
+	Buckets    []TTLBucket
+	Forecasts  []ExpiryForecast
+
+	// TotalKeys is the DBSIZE when a sample of the whole keyspace was
+	// analysed, 0 otherwise. It scales sampled counts to the keyspace.
+	TotalKeys int64
+}
+
+// NewTTLReport creates an empty TTL report
+func NewTTLReport() *TTLReport {
+	return &TTLReport{
+		Buckets: []TTLBucket{
+			{Label: "< 1 minute", MaxSeconds: 60},
+			{Label: "1-10 minutes", MaxSeconds: 600},
+			{Label: "10-60 minutes", MaxSeconds: 3600},
+			{Label: "1-6 hours", MaxSeconds: 6 * 3600},
+			{Label: "6-24 hours", MaxSeconds: 86400},
+			{Label: "1-7 days", MaxSeconds: 7 * 86400},
+			{Label: "7-30 days", MaxSeconds: 30 * 86400},
+			{Label: "> 30 days"},
+		},
+		Forecasts: []ExpiryForecast{
+			{Label: "Next minute", Seconds: 60},
+			{Label: "Next hour", Seconds: 3600},
+			{Label: "Next day", Seconds: 86400},
+		},
+	}
+}
+
+// Add accounts for a batch of key statistics
+func (r *TTLReport) Add(stats []KeyStat) {
+	for _, s := range stats {
+		r.Scanned++
+		r.Bytes += s.Bytes
+
+		if s.TTLSeconds < 0 {
+			r.WithoutTTL++
+			r.NoTTLBytes += s.Bytes
+			continue
+		}
+
+		for i := range r.Buckets {
+			b := &r.Buckets[i]
+			if b.MaxSeconds == 0 || s.TTLSeconds < b.MaxSeconds {
+				b.Keys++
+				b.Bytes += s.Bytes
+				break
+			}
+		}
+		for i := range r.Forecasts {
+			f := &r.Forecasts[i]
+			if s.TTLSeconds < f.Seconds {
+				f.Keys++
+				f.Bytes += s.Bytes
+			}
+		}
+	}
+}
+
+// Estimate scales a count from the sample to the whole keyspace. It returns
+// n unchanged when the report covers every key or the keyspace size is unknown.
+func (r *TTLReport) Estimate(n int64) int64 {
+	if r.TotalKeys <= r.Scanned || r.Scanned == 0 {
+		return n
+	}
+	return int64(float64(n) * float64(r.TotalKeys) / float64(r.Scanned))
+}
+
+// Sampled reports whether the report covers only part of the keyspace
+func (r *TTLReport) Sampled() bool {
+	return r.TotalKeys > r.Scanned
+}
+
+// WithTTL returns the number of keys that expire
+func (r *TTLReport) WithTTL() int64 {
+	return r.Scanned - r.WithoutTTL
+}
+
+// AnalyseTTL scans the keyspace with ScanKeyStats, including MEMORY USAGE,
+// and builds the TTL distribution of the keys. progress, when set, receives
+// the number of keys analysed so far.
+func AnalyseTTL(ctx context.Context, rdb redis.UniversalClient, opts ScanStatsOptions, progress func(scanned int)) (*TTLReport, error) {
+	opts.Memory = true
+
+	report := NewTTLReport()
+	err := ScanKeyStats(ctx, rdb, opts, func(stats []KeyStat) error {
+		report.Add(stats)
+		if progress != nil {
+			progress(int(report.Scanned))
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	if opts.Limit > 0 && opts.Match == "" && opts.Scope.IsZero() && report.Scanned >= int64(opts.Limit) {
+		size, err := rdb.DBSize(ctx).Result()
+		if err != nil {
+			return report, err
+		}
+		report.TotalKeys = size
+	}
+	return report, nil
+}
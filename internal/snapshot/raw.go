@@ -0,0 +1,114 @@
+package snapshot
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
+
+// errRawMismatch is returned when the raw strings of a record do not fit its
+// value
+var errRawMismatch = errors.New("raw value does not match the value")
+
+// valueStrings lists the strings of a value in a fixed order: hash fields
+// sorted by name, each followed by its value, and stream fields in order
+func valueStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case map[string]string:
+		var s []string
+		for _, field := range redis.SortedFields(v) {
+			s = append(s, field, v[field])
+		}
+		return s
+	case []redis.ZMember:
+		s := make([]string, len(v))
+		for i, m := range v {
+			s[i] = m.Member
+		}
+		return s
+	case []redis.StreamEntry:
+		var s []string
+		for _, e := range v {
+			for _, f := range e.Fields {
+				s = append(s, f.Name, f.Value)
+			}
+		}
+		return s
+	}
+	return nil
+}
+
+// rawStrings returns the strings of a value as bytes when one of them is not
+// valid UTF-8, which JSON would replace with U+FFFD, and nil otherwise
+func rawStrings(value interface{}) [][]byte {
+	s := valueStrings(value)
+	valid := true
+	for _, str := range s {
+		if !utf8.ValidString(str) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return nil
+	}
+	raw := make([][]byte, len(s))
+	for i, str := range s {
+		raw[i] = []byte(str)
+	}
+	return raw
+}
+
+// withRawStrings returns value with its strings replaced by raw, in the order
+// of valueStrings. Hashes are rebuilt from raw alone, as fields that differ
+// in their invalid bytes only were merged when the value was decoded.
+func withRawStrings(value interface{}, raw [][]byte) (interface{}, error) {
+	if hash, ok := value.(map[string]string); ok {
+		if len(raw)%2 != 0 {
+			return nil, errRawMismatch
+		}
+		hash = make(map[string]string, len(raw)/2)
+		for i := 0; i < len(raw); i += 2 {
+			hash[string(raw[i])] = string(raw[i+1])
+		}
+		return hash, nil
+	}
+	if len(raw) != len(valueStrings(value)) {
+		return nil, errRawMismatch
+	}
+
+	switch v := value.(type) {
+	case string:
+		return string(raw[0]), nil
+	case []string:
+		s := make([]string, len(raw))
+		for i, b := range raw {
+			s[i] = string(b)
+		}
+		return s, nil
+	case []redis.ZMember:
+		members := make([]redis.ZMember, len(v))
+		for i, m := range v {
+			members[i] = redis.ZMember{Member: string(raw[i]), Score: m.Score}
+		}
+		return members, nil
+	case []redis.StreamEntry:
+		entries := make([]redis.StreamEntry, len(v))
+		n := 0
+		for i, e := range v {
+			fields := make([]redis.StreamField, len(e.Fields))
+			for j := range e.Fields {
+				fields[j] = redis.StreamField{Name: string(raw[n]), Value: string(raw[n+1])}
+				n += 2
+			}
+			entries[i] = redis.StreamEntry{ID: e.ID, Fields: fields}
+		}
+		return entries, nil
+	}
+	return value, nil
+}
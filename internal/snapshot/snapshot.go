@@ -0,0 +1,206 @@
+// Package snapshot saves the keys matching a pattern to a local file and
+// compares live data with it later
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	encodingjson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	redisv8 "github.com/go-redis/redis/v8"
+	"github.com/hawkins/redis-viewer/internal/export"
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
+
+// Extension is the file name extension of snapshots
+const Extension = ".snapshot.jsonl"
+
+// ErrNotSnapshot is returned when a file does not start with a snapshot header
+var ErrNotSnapshot = errors.New("not a snapshot file")
+
+// Header describes a snapshot. It is the first line of the file, under a
+// "snapshot" key; the records follow in the jsonl export format, see record.
+type Header struct {
+	Created time.Time `json:"created"`
+	DB      int       `json:"db"`
+	Addrs   []string  `json:"addrs,omitempty"`
+	// Match is the SCAN pattern of the saved keys, empty for every key
+	Match string `json:"match,omitempty"`
+}
+
+// headerLine is the JSON form of the first line of a snapshot
+type headerLine struct {
+	Snapshot *Header `json:"snapshot"`
+}
+
+// record is the JSON form of a saved key: the jsonl export record, followed
+// by the key and the strings of the value as base64 when they are not valid
+// UTF-8, so that binary data compares byte for byte
+type record struct {
+	redis.KeyRecord
+	// ExpireAt is when the key expires, in Unix milliseconds, from the time
+	// its TTL was read rather than the start of the snapshot
+	ExpireAt int64    `json:"expire_at_ms,omitempty"`
+	RawKey   []byte   `json:"raw_key,omitempty"`
+	Raw      [][]byte `json:"raw,omitempty"`
+}
+
+// Snapshot is a loaded snapshot file
+type Snapshot struct {
+	Header
+	Path    string
+	Records []redis.KeyRecord
+
+	expireAt []int64 // ExpireAt of each record, 0 for keys without a TTL
+}
+
+// Save scans the keys of opts.Match in rdb and writes them with their type,
+// value and TTL to a new file at path, which must not exist. progress, when
+// set, receives the number of keys saved so far.
+func Save(ctx context.Context, rdb redisv8.UniversalClient, path string, header Header, opts redis.ScanStatsOptions,
+	progress func(saved int)) (int, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	enc := encodingjson.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	header.Match = opts.Match
+	if err := enc.Encode(headerLine{Snapshot: &header}); err != nil {
+		return 0, err
+	}
+
+	saved := 0
+	err = redis.ScanRecords(ctx, rdb, opts, false, func(records []redis.KeyRecord) error {
+		// The TTLs of the batch were read just before
+		readAt := unixMillis(time.Now())
+		for _, r := range records {
+			saving := record{KeyRecord: r, Raw: rawStrings(r.Value)}
+			if r.TTLMillis >= 0 {
+				saving.ExpireAt = readAt + r.TTLMillis
+			}
+			if !utf8.ValidString(r.Key) {
+				saving.RawKey = []byte(r.Key)
+			}
+			if err := enc.Encode(saving); err != nil {
+				return err
+			}
+		}
+		saved += len(records)
+		if progress != nil {
+			progress(saved)
+		}
+		return nil
+	})
+	if err != nil {
+		return saved, err
+	}
+	return saved, buf.Flush()
+}
+
+// Load reads a snapshot file
+func Load(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	line, err := br.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	var hl headerLine
+	if encodingjson.Unmarshal(line, &hl) != nil || hl.Snapshot == nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrNotSnapshot)
+	}
+
+	s := &Snapshot{Header: *hl.Snapshot, Path: path}
+	dec := encodingjson.NewDecoder(br)
+	for n := 1; ; n++ {
+		var line encodingjson.RawMessage
+		if err := dec.Decode(&line); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: record %d: %w", path, n, err)
+		}
+		r, expireAt, err := parseRecord(line, n)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if expireAt == 0 && r.TTLMillis >= 0 {
+			// Saved without the time its TTL was read
+			expireAt = unixMillis(s.Created) + r.TTLMillis
+		}
+		s.Records = append(s.Records, r)
+		s.expireAt = append(s.expireAt, expireAt)
+	}
+	return s, nil
+}
+
+// parseRecord decodes a saved record and when it expires, numbered n in
+// error messages
+func parseRecord(line []byte, n int) (redis.KeyRecord, int64, error) {
+	r, err := export.ParseRecord(line, n)
+	if err != nil {
+		return r, 0, err
+	}
+	var saved struct {
+		ExpireAt int64    `json:"expire_at_ms"`
+		RawKey   []byte   `json:"raw_key"`
+		Raw      [][]byte `json:"raw"`
+	}
+	if err := encodingjson.Unmarshal(line, &saved); err != nil {
+		return r, 0, fmt.Errorf("record %d: %w", n, err)
+	}
+	if saved.RawKey != nil {
+		r.Key = string(saved.RawKey)
+	}
+	if saved.Raw != nil {
+		if r.Value, err = withRawStrings(r.Value, saved.Raw); err != nil {
+			return r, 0, fmt.Errorf("record %d (%s): %w", n, r.Key, err)
+		}
+	}
+	return r, saved.ExpireAt, nil
+}
+
+// RecordsAt returns the records with their TTL as it would be at t, so that
+// keys whose TTL merely counted down since the snapshot compare equal. Keys
+// that have expired by then keep a TTL of 0.
+func (s *Snapshot) RecordsAt(t time.Time) []redis.KeyRecord {
+	now := unixMillis(t)
+	records := make([]redis.KeyRecord, len(s.Records))
+	for i, r := range s.Records {
+		if r.TTLMillis >= 0 {
+			r.TTLMillis = s.expireAt[i] - now
+			if r.TTLMillis < 0 {
+				r.TTLMillis = 0
+			}
+		}
+		records[i] = r
+	}
+	return records
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// Diff compares the live keys of the snapshot pattern in rdb with the
+// snapshot: keys only in the snapshot were removed since, keys only in rdb
+// were added. fn and progress are called like in redis.DiffKeyspaces.
+func (s *Snapshot) Diff(ctx context.Context, rdb redisv8.UniversalClient, opts redis.DiffOptions,
+	fn func(redis.KeyDiff) error, progress func(scanned int)) (redis.DiffSummary, error) {
+	opts.Match = s.Match
+	return redis.DiffRecords(ctx, s.RecordsAt(time.Now()), rdb, opts, fn, progress)
+}
@@ -22,6 +22,10 @@ var (
 
 	LoadingStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#888888"))
+
+	ChangedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
+			Bold(true)
 )
 
 // Status bar styles
@@ -50,6 +54,18 @@ var (
 	WrapIndicatorStyle = StatusNugget.Copy().
 				Background(lipgloss.Color("#50FA7B"))
 
+	AutoRefreshIndicatorStyle = StatusNugget.Copy().
+					Background(lipgloss.Color("#FF8700"))
+
+	ReadOnlyIndicatorStyle = StatusNugget.Copy().
+				Background(lipgloss.Color("#0087D7"))
+
+	ReplicaIndicatorStyle = StatusNugget.Copy().
+				Background(lipgloss.Color("#00875F"))
+
+	RDBIndicatorStyle = StatusNugget.Copy().
+				Background(lipgloss.Color("#AF5F00"))
+
 	StatusText = StatusBarStyle.Copy()
 
 	DatetimeStyle = StatusNugget.Copy().
@@ -96,6 +112,9 @@ var (
 
 	StatsRowStyle = lipgloss.NewStyle().Width(15)
 
+	StatsChartStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#50FA7B"))
+
 	StatsFooterStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.AdaptiveColor{Light: "#9B9B9B", Dark: "#5C5C5C"})
 
@@ -110,3 +129,38 @@ var (
 			Foreground(lipgloss.Color("#FF0000")).
 			Bold(true)
 )
+
+// Table styles
+var (
+	TableHeaderStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#6124DF"))
+
+	TableRowStyle = lipgloss.NewStyle()
+
+	TableSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFFDF5")).
+				Background(lipgloss.Color("#FF5F87"))
+
+	TableEmptyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.AdaptiveColor{Light: "#9B9B9B", Dark: "#5C5C5C"})
+)
+
+// Keyspace diff styles
+var (
+	DiffRemovedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FF5F5F"))
+
+	DiffAddedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#73C991"))
+)
+
+// INFO browser styles
+var (
+	InfoSectionStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#A550DF"))
+
+	InfoDeltaStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700"))
+)
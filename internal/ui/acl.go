@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// ACLData holds the ACL screen contents
+type ACLData struct {
+	status     *redis.ACLStatus
+	loading    bool
+	refreshing bool
+	err        error
+}
+
+// aclLogColumnWidths are the ACL log table column widths, the last column takes the rest
+var aclLogColumnWidths = []int{10, 16, 10, 10, 28, 6, 22}
+
+func (a *App) openACL() tea.Cmd {
+	a.state = StateACL
+	a.aclData = &ACLData{loading: true}
+	a.aclViewport.GotoTop()
+	return a.aclCmd()
+}
+
+func (a *App) handleACLState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.aclViewport, cmd = a.aclViewport.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "A":
+			a.state = StateDefault
+		case "r":
+			if a.aclData != nil && !a.aclData.loading {
+				a.aclData.refreshing = true
+			}
+			cmd = a.aclCmd()
+		case "a":
+			a.toggleAutoRefresh()
+		default:
+			a.aclViewport, cmd = a.aclViewport.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// refreshACLContent renders the users and the ACL log into the viewport
+func (a *App) refreshACLContent() {
+	d := a.aclData
+	if d == nil || d.err != nil {
+		a.aclViewport.SetContent("")
+		return
+	}
+
+	label := func(s string) string {
+		return styles.StatsLabelStyle.Render(s)
+	}
+	// Rules can be long: wrap them next to their label
+	wrapped := func(s string) string {
+		width := a.aclViewport.Width - lipgloss.Width(label(""))
+		if width < 20 {
+			width = 20
+		}
+		lines := strings.Split(wordwrap.String(s, width), "\n")
+		return strings.Join(lines, "\n"+label(""))
+	}
+
+	lines := []string{label("Connected as:") + d.status.WhoAmI, ""}
+
+	lines = append(lines, styles.InfoSectionStyle.Render(fmt.Sprintf("Users (%d)", len(d.status.Users))))
+	if d.status.UsersErr != nil {
+		lines = append(lines, styles.StatsErrorStyle.Render(fmt.Sprintf("Cannot list users: %v", d.status.UsersErr)))
+	}
+	for _, u := range d.status.Users {
+		name := u.Name
+		if u.Name == d.status.WhoAmI {
+			name += " (current)"
+		}
+		state := "enabled"
+		if !u.Enabled() {
+			state = "disabled"
+			name = styles.StatsErrorStyle.Render(name)
+		}
+
+		passwords := fmt.Sprintf("%d", u.Passwords)
+		if u.NoPass() {
+			passwords = styles.ChangedStyle.Render("none required (nopass)")
+		}
+
+		lines = append(lines,
+			"",
+			styles.StatsValueStyle.Render(name),
+			label("State:")+state,
+			label("Flags:")+strings.Join(u.Flags, " "),
+			label("Passwords:")+passwords,
+			label("Commands:")+wrapped(u.Commands),
+			label("Keys:")+wrapped(emptyAs(u.Keys, "none")),
+			label("Channels:")+wrapped(emptyAs(u.Channels, "none")),
+		)
+		for i, sel := range u.Selectors {
+			lines = append(lines, label(fmt.Sprintf("Selector %d:", i+1))+wrapped(sel))
+		}
+	}
+
+	lines = append(lines, "", "", styles.InfoSectionStyle.Render(fmt.Sprintf("ACL log (%d entries)", len(d.status.Log))))
+	switch {
+	case d.status.LogErr != nil:
+		lines = append(lines, styles.StatsErrorStyle.Render(fmt.Sprintf("Cannot read the ACL log: %v", d.status.LogErr)))
+	case len(d.status.Log) == 0:
+		lines = append(lines, styles.TableEmptyStyle.Render("No denied commands"))
+	default:
+		nodes := make(map[string]bool)
+		for _, e := range d.status.Log {
+			nodes[e.Node] = true
+		}
+		showNode := len(nodes) > 1
+		nodeTitle := ""
+		if showNode {
+			nodeTitle = "Node"
+		}
+		lines = append(lines, styles.TableHeaderStyle.Render(tableCells(
+			[]string{"Age", "User", "Reason", "Context", "Object", "Count", "Client", nodeTitle}, aclLogColumnWidths)))
+		for _, e := range d.status.Log {
+			client := e.ClientAddr
+			if e.ClientName != "" {
+				client += " " + e.ClientName
+			}
+			node := ""
+			if showNode {
+				node = e.Node
+			}
+			lines = append(lines, tableCells([]string{
+				redis.FormatSeconds(int64(e.AgeSeconds)),
+				e.Username,
+				e.Reason,
+				e.Context,
+				e.Object,
+				formatNumber(e.Count),
+				client,
+				node,
+			}, aclLogColumnWidths))
+		}
+	}
+
+	a.aclViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// emptyAs returns fallback for an empty string
+func emptyAs(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func (a App) aclView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.aclData == nil || a.aclData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading ACLs...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.aclData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading ACLs: %v", a.aclData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render("Access Control Lists")
+	footer := styles.StatsFooterStyle.Render("↑/↓ scroll | r reload | a auto-refresh | ESC, q or A close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		a.aclViewport.View(),
+		footer,
+	))
+}
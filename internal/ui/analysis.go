@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
+
+// analysisJob runs a keyspace analysis in the background and streams
+// AnalysisProgressMsg updates followed by a single result message
+type analysisJob struct {
+	msgs    chan tea.Msg
+	cancel  context.CancelFunc
+	scanned int // keys analysed so far, updated from AnalysisProgressMsg
+}
+
+// startAnalysisJob runs fn in a goroutine. fn reports progress through the
+// given callback and returns the result message.
+func startAnalysisJob(fn func(ctx context.Context, progress func(scanned int)) tea.Msg) (*analysisJob, tea.Cmd) {
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &analysisJob{
+		msgs:   make(chan tea.Msg, 1),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(job.msgs)
+
+		progress := func(scanned int) {
+			// Drop updates the UI has not caught up with yet
+			select {
+			case job.msgs <- AnalysisProgressMsg{Job: job, Scanned: scanned}:
+			default:
+			}
+		}
+
+		result := fn(ctx, progress)
+		if ctx.Err() != nil {
+			// Cancelled, nobody is waiting for the result
+			return
+		}
+		select {
+		case job.msgs <- result:
+		case <-ctx.Done():
+		}
+	}()
+
+	return job, job.wait()
+}
+
+// wait returns a command delivering the job's next message
+func (j *analysisJob) wait() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-j.msgs
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// stop cancels the job
+func (j *analysisJob) stop() {
+	if j != nil {
+		j.cancel()
+	}
+}
+
+// scanStatsOptions returns the key analysis settings shared by every report
+func (a App) scanStatsOptions() redis.ScanStatsOptions {
+	return redis.ScanStatsOptions{
+		BatchSize: a.analysisBatchSize,
+		Pause:     a.analysisPause,
+		Scope:     a.keyScope,
+	}
+}
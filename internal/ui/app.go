@@ -1,17 +1,20 @@
 package ui
 
 import (
-	"context"
-	"fmt"
+	"time"
 
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/go-redis/redis/v8"
+	redisv8 "github.com/go-redis/redis/v8"
 	"github.com/hawkins/redis-viewer/internal/config"
 	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/redis"
 	"github.com/hawkins/redis-viewer/internal/ui/components/keylist"
+	"github.com/hawkins/redis-viewer/internal/ui/components/table"
 	"github.com/hawkins/redis-viewer/internal/ui/components/valueview"
 	"github.com/hawkins/redis-viewer/internal/ui/dialogs"
 	"github.com/muesli/termenv"
@@ -31,6 +34,27 @@ const (
 	StateConfirmPurge
 	StateHelp
 	StateStats
+	StateInfo
+	StateSlowlog
+	StateConfirmSlowlogReset
+	StateClients
+	StateConfirmClientKill
+	StateBigKeys
+	StatePrefixes
+	StateTTLReport
+	StateCluster
+	StateKeyScope
+	StateReplication
+	StateConfig
+	StateConfirmConfigSet
+	StateConfirmConfigRewrite
+	StateACL
+	StateExportInput
+	StateDiffInput
+	StateDiff
+	StateCopyInput
+	StateCopy
+	StateSnapshotInput
 )
 
 // FocusedPane represents which pane has focus
@@ -55,34 +79,142 @@ type App struct {
 	switchDBDialog dialogs.SwitchDBDialog
 	ttlInput       textinput.Model
 	createKeyInput textinput.Model
+	keyScopeInput  textinput.Model
 	confirmDialog  dialogs.ConfirmDialog
 
 	// Redis connection
-	rdb       redis.UniversalClient
-	redisOpts *redis.UniversalOptions
+	rdb       redisv8.UniversalClient
+	redisOpts *redis.Options
+	config    config.Config // profiles the diff screen can target
 	db        int
+	readOnly  bool
+
+	listShowSize bool // describe loaded keys with their size in the key list
+	dumpDenied   bool // DUMP failed with NOPERM, so the serialized length is no longer offered
 
 	// Application state
-	state          AppState
-	focused        FocusedPane
-	fuzzyFilter    string
-	fuzzyStrict    bool
-	wordWrap       bool
-	statusMessage  string
-	ready          bool
-	now            string
-	keyToDelete    string
-	keyToSetTTL    string
-	editingKey     string
-	editingTmpFile string
+	state           AppState
+	focused         FocusedPane
+	fuzzyFilter     string
+	fuzzyStrict     bool
+	wordWrap        bool
+	statusMessage   string
+	ready           bool
+	now             string
+	keyToDelete     string
+	keyToSetTTL     string
+	editingKey      string
+	editingTmpFile  string
 	editingIsCreate bool
 
 	// Stats
-	statsData *StatsData
+	statsData   *StatsData
+	statsDetail bool // sample key TTLs of the current database
+
+	// INFO browser
+	infoData           *InfoData
+	infoViewport       viewport.Model
+	infoSearch         textinput.Model
+	infoSearching      bool
+	infoFilter         string
+	infoSectionOffsets []int
+
+	// Slow log
+	slowLogData       *SlowLogData
+	slowLogTable      table.Model
+	slowLogByDuration bool
+
+	// Client list
+	clientsData      *ClientsData
+	clientsTable     table.Model
+	clientsSort      clientSort
+	clientsGroup     clientGrouping
+	clientsSearch    textinput.Model
+	clientsSearching bool
+	clientsFilter    string
+	clientsOpenGroup *clientGroupFilter // group drilled into, nil for every client
+
+	// Keyspace analysis
+	analysisBatchSize int
+	analysisPause     time.Duration
+	bigKeysData       *BigKeysData
+	bigKeysViewport   viewport.Model
+
+	// Key prefix breakdown
+	analysisSampleSize int
+	prefixDelimiter    string
+	prefixDepth        int
+	prefixesData       *PrefixesData
+	prefixesTable      table.Model
+	prefixesSort       redis.PrefixSort
+	prefixesFull       bool
+
+	// TTL distribution
+	ttlData     *TTLData
+	ttlViewport viewport.Model
+	ttlMatch    textinput.Model
+	ttlMatching bool
+	ttlPattern  string
+	ttlFull     bool
+
+	// Cluster topology
+	clusterData     *ClusterData
+	clusterViewport viewport.Model
+
+	// Replication and sentinel status
+	replicationData     *ReplicationData
+	replicationViewport viewport.Model
+
+	// CONFIG browser
+	configData         *ConfigData
+	configTable        table.Model
+	configSearch       textinput.Model
+	configSearching    bool
+	configFilter       string
+	configModifiedOnly bool
+	configInput        textinput.Model
+	configEditing      bool
+
+	// Export dialog
+	exportInput  textinput.Model
+	exportFormat int
+	exportAll    bool
+
+	// Keyspace diff
+	diffInput    textinput.Model
+	diffData     *DiffData
+	diffTable    table.Model
+	diffViewport viewport.Model
+
+	// Key copy
+	copyInput    textinput.Model
+	copyConflict int // index in redis.CopyConflicts
+	copyData     *CopyData
+	copyProgress progress.Model
+
+	// Snapshots
+	snapshotInput   textinput.Model
+	snapshotCompare bool   // the dialog compares with a snapshot instead of saving one
+	snapshotPath    string // last snapshot saved or compared with
+
+	// ACL inspection
+	aclData     *ACLData
+	aclViewport viewport.Model
+
+	// Metrics history charted on the stats page
+	metrics         *MetricsHistory
+	metricsWindow   int
+	metricsSampling bool
+
+	// Auto-refresh
+	autoRefresh     bool
+	refreshInterval time.Duration
+	lastRefresh     time.Time
 
 	// Scan settings
-	offset int64
-	limit  int64
+	offset   int64
+	limit    int64
+	keyScope redis.KeyScope // cluster node and slot range the key list is restricted to
 
 	// Scan state
 	pendingScanItems []keylist.Item
@@ -99,7 +231,9 @@ type App struct {
 type StatsData struct {
 	serverStats interface{}
 	dbStats     interface{}
+	databases   int
 	loading     bool
+	refreshing  bool
 	err         error
 }
 
@@ -107,19 +241,42 @@ type StatsData struct {
 func New(cfg config.Config) (*App, error) {
 	lipgloss.SetColorProfile(termenv.TrueColor)
 
-	opts := &redis.UniversalOptions{
-		Addrs:        cfg.Addrs,
-		DB:           cfg.DB,
-		Username:     cfg.Username,
-		Password:     cfg.Password,
-		MaxRetries:   constant.MaxRetries,
-		MaxRedirects: constant.MaxRedirects,
-		MasterName:   cfg.MasterName,
-	}
-	rdb := redis.NewUniversalClient(opts)
-	_, err := rdb.Ping(context.Background()).Result()
+	opts := redis.NewOptions(cfg)
+	rdb, err := redis.Connect(opts)
 	if err != nil {
-		return nil, fmt.Errorf("connect to redis failed: %w", err)
+		return nil, err
+	}
+
+	refreshInterval := time.Duration(cfg.RefreshInterval) * time.Second
+	if refreshInterval <= 0 {
+		refreshInterval = constant.DefaultRefreshInterval * time.Second
+	}
+
+	metricsWindow := cfg.StatsWindow
+	if metricsWindow <= 0 {
+		metricsWindow = constant.DefaultStatsWindow
+	}
+
+	analysisBatchSize := cfg.AnalysisBatchSize
+	if analysisBatchSize <= 0 {
+		analysisBatchSize = constant.DefaultAnalysisBatchSize
+	}
+	analysisPause := time.Duration(cfg.AnalysisPause) * time.Millisecond
+	if cfg.AnalysisPause < 0 {
+		analysisPause = 0
+	}
+
+	analysisSampleSize := cfg.AnalysisSampleSize
+	if analysisSampleSize < 0 {
+		analysisSampleSize = 0
+	}
+	prefixDelimiter := cfg.PrefixDelimiter
+	if prefixDelimiter == "" {
+		prefixDelimiter = constant.DefaultPrefixDelimiter
+	}
+	prefixDepth := cfg.PrefixDepth
+	if prefixDepth <= 0 {
+		prefixDepth = constant.DefaultPrefixDepth
 	}
 
 	// Initialize components
@@ -142,21 +299,130 @@ func New(cfg config.Config) (*App, error) {
 	createKeyInput.Placeholder = "Key Name"
 	createKeyInput.PlaceholderStyle = lipgloss.NewStyle()
 
+	// Initialize cluster key scope input
+	keyScopeInput := textinput.New()
+	keyScopeInput.Prompt = "> "
+	keyScopeInput.Placeholder = "node address and/or slot range, e.g. 10.0.0.1:6379 0-5460 (empty for all)"
+	keyScopeInput.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize INFO browser search input
+	infoSearch := textinput.New()
+	infoSearch.Prompt = "Search: "
+	infoSearch.Placeholder = "field name or value"
+	infoSearch.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize client list filter input
+	clientsSearch := textinput.New()
+	clientsSearch.Prompt = "Filter: "
+	clientsSearch.Placeholder = "address, name, user, command or flags"
+	clientsSearch.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize TTL report match input
+	ttlMatch := textinput.New()
+	ttlMatch.Prompt = "Match: "
+	ttlMatch.Placeholder = "SCAN pattern, e.g. session:*"
+	ttlMatch.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize CONFIG browser inputs
+	configSearch := textinput.New()
+	configSearch.Prompt = "Filter: "
+	configSearch.Placeholder = "parameter name or value"
+	configSearch.PlaceholderStyle = lipgloss.NewStyle()
+
+	configInput := textinput.New()
+	configInput.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize export path input
+	exportInput := textinput.New()
+	exportInput.Prompt = "> "
+	exportInput.Placeholder = "file path"
+	exportInput.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize diff target input
+	diffInput := textinput.New()
+	diffInput.Prompt = "> "
+	diffInput.Placeholder = "database number, profile or profile/N, then an optional key pattern"
+	diffInput.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize copy target input
+	copyInput := textinput.New()
+	copyInput.Prompt = "> "
+	copyInput.Placeholder = "database number, profile or profile/N"
+	copyInput.PlaceholderStyle = lipgloss.NewStyle()
+
+	// Initialize snapshot file input
+	snapshotInput := textinput.New()
+	snapshotInput.Prompt = "> "
+
+	infoViewport := viewport.New(0, 0)
+	infoViewport.MouseWheelEnabled = true
+
+	bigKeysViewport := viewport.New(0, 0)
+	bigKeysViewport.MouseWheelEnabled = true
+
+	ttlViewport := viewport.New(0, 0)
+	ttlViewport.MouseWheelEnabled = true
+
+	clusterViewport := viewport.New(0, 0)
+	clusterViewport.MouseWheelEnabled = true
+
+	replicationViewport := viewport.New(0, 0)
+	replicationViewport.MouseWheelEnabled = true
+
+	aclViewport := viewport.New(0, 0)
+	aclViewport.MouseWheelEnabled = true
+
+	diffViewport := viewport.New(0, 0)
+	diffViewport.MouseWheelEnabled = true
+
 	app := &App{
-		keyList:         keyListModel,
-		valueView:       valueViewModel,
-		spinner:         s,
-		filterDialog:    dialogs.NewFilterDialog(),
-		switchDBDialog:  dialogs.NewSwitchDBDialog(),
-		ttlInput:        ttlInput,
-		createKeyInput:  createKeyInput,
-		rdb:             rdb,
-		redisOpts:       opts,
-		db:              cfg.DB,
-		limit:           cfg.Limit,
-		keyMap:          DefaultKeyMap(),
-		state:           StateDefault,
-		focused:         PaneList,
+		keyList:             keyListModel,
+		valueView:           valueViewModel,
+		spinner:             s,
+		filterDialog:        dialogs.NewFilterDialog(),
+		switchDBDialog:      dialogs.NewSwitchDBDialog(),
+		ttlInput:            ttlInput,
+		createKeyInput:      createKeyInput,
+		keyScopeInput:       keyScopeInput,
+		infoSearch:          infoSearch,
+		infoViewport:        infoViewport,
+		slowLogTable:        newSlowLogTable(),
+		clientsTable:        table.New(nil),
+		clientsSearch:       clientsSearch,
+		bigKeysViewport:     bigKeysViewport,
+		analysisBatchSize:   analysisBatchSize,
+		analysisPause:       analysisPause,
+		analysisSampleSize:  analysisSampleSize,
+		prefixDelimiter:     prefixDelimiter,
+		prefixDepth:         prefixDepth,
+		prefixesTable:       newPrefixesTable(),
+		ttlViewport:         ttlViewport,
+		ttlMatch:            ttlMatch,
+		clusterViewport:     clusterViewport,
+		replicationViewport: replicationViewport,
+		configTable:         newConfigTable(),
+		configSearch:        configSearch,
+		configInput:         configInput,
+		exportInput:         exportInput,
+		diffInput:           diffInput,
+		diffTable:           newDiffTable(),
+		diffViewport:        diffViewport,
+		copyInput:           copyInput,
+		copyProgress:        progress.New(progress.WithDefaultGradient()),
+		snapshotInput:       snapshotInput,
+		aclViewport:         aclViewport,
+		rdb:                 rdb,
+		redisOpts:           opts,
+		config:              cfg,
+		db:                  cfg.DB,
+		readOnly:            cfg.ReadOnly || opts.ReplicaOnly || opts.RDBFile != "",
+		limit:               cfg.Limit,
+		listShowSize:        cfg.ListShowSize,
+		refreshInterval:     refreshInterval,
+		metricsWindow:       metricsWindow,
+		keyMap:              DefaultKeyMap(),
+		state:               StateDefault,
+		focused:             PaneList,
 	}
 
 	// Set initial focus on the app's keyList component
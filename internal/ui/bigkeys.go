@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+)
+
+// BigKeysData holds the big keys report screen contents
+type BigKeysData struct {
+	job    *analysisJob
+	report *redis.BigKeysReport
+	err    error
+}
+
+func (a *App) openBigKeys() tea.Cmd {
+	a.state = StateBigKeys
+	a.bigKeysViewport.GotoTop()
+	return a.startBigKeys()
+}
+
+// startBigKeys starts a new big keys analysis, cancelling any running one
+func (a *App) startBigKeys() tea.Cmd {
+	if a.bigKeysData != nil {
+		a.bigKeysData.job.stop()
+	}
+
+	rdb := a.rdb
+	opts := a.scanStatsOptions()
+	opts.Memory = true
+	opts.Elements = true
+
+	job, cmd := startAnalysisJob(func(ctx context.Context, progress func(int)) tea.Msg {
+		report := redis.NewBigKeysReport(constant.BigKeysTopN)
+		err := redis.ScanKeyStats(ctx, rdb, opts, func(stats []redis.KeyStat) error {
+			report.Add(stats)
+			progress(int(report.Scanned))
+			return nil
+		})
+		return BigKeysMsg{Report: report, Err: err}
+	})
+	a.bigKeysData = &BigKeysData{job: job}
+	return cmd
+}
+
+func (a *App) handleBigKeysState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.bigKeysViewport, cmd = a.bigKeysViewport.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "B":
+			// Leaving cancels a running analysis
+			if a.bigKeysData != nil && a.bigKeysData.report == nil {
+				a.bigKeysData.job.stop()
+				a.bigKeysData = nil
+			}
+			a.state = StateDefault
+		case "r":
+			a.bigKeysViewport.GotoTop()
+			cmd = a.startBigKeys()
+		default:
+			a.bigKeysViewport, cmd = a.bigKeysViewport.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// refreshBigKeysContent renders the report into the viewport
+func (a *App) refreshBigKeysContent() {
+	if a.bigKeysData == nil || a.bigKeysData.report == nil {
+		a.bigKeysViewport.SetContent("")
+		return
+	}
+	report := a.bigKeysData.report
+
+	header := func(cells ...string) string {
+		return styles.TableHeaderStyle.Render(tableCells(cells, bigKeysColumnWidths))
+	}
+	row := func(cells ...string) string {
+		return tableCells(cells, bigKeysColumnWidths)
+	}
+
+	lines := []string{
+		styles.InfoSectionStyle.Render("Totals by type"),
+		header("Type", "Keys", "Memory", "Elements", "Avg Memory"),
+	}
+	summaries := report.Summaries()
+	var totalKeys, totalBytes int64
+	for _, s := range summaries {
+		totalKeys += s.Keys
+		totalBytes += s.Bytes
+		lines = append(lines, row(
+			s.Type,
+			formatNumber(s.Keys),
+			redis.FormatBytes(s.Bytes),
+			formatNumber(s.Elements),
+			redis.FormatBytes(s.Bytes/s.Keys),
+		))
+	}
+	lines = append(lines, styles.StatsValueStyle.Render(row("total", formatNumber(totalKeys), redis.FormatBytes(totalBytes), "", "")))
+
+	for _, s := range summaries {
+		lines = append(lines, "", styles.InfoSectionStyle.Render(fmt.Sprintf("Top %s keys by memory", s.Type)))
+		lines = append(lines, header("Memory", "Elements", "Key"))
+		for _, k := range report.TopBytes[s.Type] {
+			lines = append(lines, row(redis.FormatBytes(k.Bytes), formatNumber(k.Elements), k.Key))
+		}
+
+		lines = append(lines, "", styles.InfoSectionStyle.Render(fmt.Sprintf("Top %s keys by %s", s.Type, redis.ElementsLabel(s.Type))))
+		label := redis.ElementsLabel(s.Type)
+		lines = append(lines, header(strings.ToUpper(label[:1])+label[1:], "Memory", "Key"))
+		for _, k := range report.TopElements[s.Type] {
+			lines = append(lines, row(formatNumber(k.Elements), redis.FormatBytes(k.Bytes), k.Key))
+		}
+	}
+
+	a.bigKeysViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// bigKeysColumnWidths are the report column widths, the last column takes the rest
+var bigKeysColumnWidths = []int{14, 14, 14, 14, 14}
+
+// tableCells lays out cells in columns of the given widths. Cells beyond the
+// widths are appended unpadded.
+func tableCells(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, c := range cells {
+		if i < len(widths) && i < len(cells)-1 {
+			b.WriteString(lipgloss.NewStyle().Width(widths[i]).Render(c))
+		} else {
+			b.WriteString(c)
+		}
+	}
+	return b.String()
+}
+
+func (a App) bigKeysView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.bigKeysData == nil {
+		return ""
+	}
+
+	if a.bigKeysData.report == nil {
+		progress := styles.StatsLoadingStyle.Render(fmt.Sprintf(
+			"%s Analysing keys... %s done", a.spinner.View(), formatNumber(int64(a.bigKeysData.job.scanned))))
+		hint := styles.StatsFooterStyle.Render("Press ESC to cancel")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, progress, "", hint))
+	}
+
+	if a.bigKeysData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error analysing keys: %v", a.bigKeysData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(
+		fmt.Sprintf("Big Keys (DB %d, %s keys analysed)", a.db, formatNumber(a.bigKeysData.report.Scanned)))
+	footer := styles.StatsFooterStyle.Render("↑/↓ scroll | r re-run | ESC, q or B close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		a.bigKeysViewport.View(),
+		footer,
+	))
+}
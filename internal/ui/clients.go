@@ -0,0 +1,415 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/hawkins/redis-viewer/internal/ui/components/table"
+	"github.com/hawkins/redis-viewer/internal/ui/dialogs"
+)
+
+// ClientsData holds the client list screen contents
+type ClientsData struct {
+	all        []redis.ClientInfo
+	clients    []redis.ClientInfo // filtered and sorted, in display order
+	groups     []clientGroup      // filtered and sorted, in display order when grouping
+	loading    bool
+	refreshing bool
+	err        error
+}
+
+// clientSort is the column the client list is sorted by
+type clientSort int
+
+const (
+	clientSortIdle clientSort = iota
+	clientSortAge
+	clientSortMemory
+	clientSortName
+	clientSortAddr
+	clientSortCount // number of sort modes
+)
+
+func (s clientSort) String() string {
+	return [...]string{"idle", "age", "memory", "name", "address"}[s]
+}
+
+// clientGrouping is how clients are aggregated
+type clientGrouping int
+
+const (
+	clientGroupNone clientGrouping = iota
+	clientGroupName
+	clientGroupIP
+	clientGroupingCount // number of grouping modes
+)
+
+func (g clientGrouping) String() string {
+	return [...]string{"none", "name", "source IP"}[g]
+}
+
+// groupKey returns the name or source IP a client is grouped by
+func (g clientGrouping) groupKey(c redis.ClientInfo) string {
+	if g == clientGroupName {
+		return c.Name
+	}
+	return c.IP()
+}
+
+// clientGroup aggregates the clients sharing a name or source IP
+type clientGroup struct {
+	key      string
+	count    int
+	memory   int64
+	maxIdle  int64
+	totalAge int64
+}
+
+// clientGroupFilter keeps the clients of a single group, matched exactly
+// rather than with the free-text filter. An empty name is the unnamed group.
+type clientGroupFilter struct {
+	by  clientGrouping
+	key string
+}
+
+func (f clientGroupFilter) matches(c redis.ClientInfo) bool {
+	return f.by.groupKey(c) == f.key
+}
+
+func (f clientGroupFilter) String() string {
+	if f.by == clientGroupName {
+		return "name " + groupLabel(f.key)
+	}
+	return "source IP " + f.key
+}
+
+// groupLabel displays a group key, naming the group of unnamed clients
+func groupLabel(key string) string {
+	if key == "" {
+		return "(unnamed)"
+	}
+	return key
+}
+
+func (a *App) openClients() tea.Cmd {
+	a.state = StateClients
+	a.clientsData = &ClientsData{loading: true}
+	a.clientsFilter = ""
+	a.clientsOpenGroup = nil
+	a.clientsSearch.Reset()
+	a.clientsTable.SetCursor(0)
+	return a.clientsCmd()
+}
+
+func (a *App) handleClientsState(msg tea.Msg) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	if a.clientsSearching {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.Type {
+			case tea.KeyEscape:
+				a.clientsSearching = false
+				a.clientsSearch.Blur()
+				a.clientsSearch.Reset()
+				a.clientsFilter = ""
+				a.refreshClientsTable()
+				return nil
+			case tea.KeyEnter:
+				a.clientsSearching = false
+				a.clientsSearch.Blur()
+				return nil
+			}
+		}
+
+		a.clientsSearch, cmd = a.clientsSearch.Update(msg)
+		if a.clientsSearch.Value() != a.clientsFilter {
+			a.clientsFilter = a.clientsSearch.Value()
+			a.refreshClientsTable()
+			a.clientsTable.SetCursor(0)
+		}
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.clientsTable, cmd = a.clientsTable.Update(msg)
+		cmds = append(cmds, cmd)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if a.clientsFilter != "" {
+				a.clientsFilter = ""
+				a.clientsSearch.Reset()
+				a.refreshClientsTable()
+				return nil
+			}
+			if a.clientsOpenGroup != nil {
+				// Back to the group list
+				a.clientsGroup = a.clientsOpenGroup.by
+				a.clientsOpenGroup = nil
+				a.refreshClientsTable()
+				a.clientsTable.SetCursor(0)
+				return nil
+			}
+			a.state = StateDefault
+		case "q", "C":
+			a.state = StateDefault
+		case "/":
+			a.clientsSearching = true
+			a.clientsSearch.SetValue(a.clientsFilter)
+			return a.clientsSearch.Focus()
+		case "r":
+			if a.clientsData != nil && !a.clientsData.loading {
+				a.clientsData.refreshing = true
+			}
+			cmds = append(cmds, a.clientsCmd())
+		case "a":
+			a.toggleAutoRefresh()
+		case "s":
+			a.clientsSort = (a.clientsSort + 1) % clientSortCount
+			a.refreshClientsTable()
+			a.clientsTable.SetCursor(0)
+		case "g":
+			a.clientsGroup = (a.clientsGroup + 1) % clientGroupingCount
+			a.refreshClientsTable()
+			a.clientsTable.SetCursor(0)
+		case "enter":
+			// Drill down into the selected group
+			if a.clientsGroup != clientGroupNone && a.clientsData != nil {
+				if cursor := a.clientsTable.Cursor(); cursor < len(a.clientsData.groups) {
+					a.clientsOpenGroup = &clientGroupFilter{by: a.clientsGroup, key: a.clientsData.groups[cursor].key}
+					a.clientsGroup = clientGroupNone
+					a.refreshClientsTable()
+					a.clientsTable.SetCursor(0)
+				}
+			}
+		case "K":
+			return a.confirmClientKill()
+		default:
+			a.clientsTable, cmd = a.clientsTable.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// confirmClientKill asks for confirmation before killing the selected client
+func (a *App) confirmClientKill() tea.Cmd {
+	if a.denyWrite("killing clients") {
+		return nil
+	}
+	if a.clientsGroup != clientGroupNone {
+		a.statusMessage = "Turn off grouping (g) to select a single client"
+		return nil
+	}
+	if a.clientsData == nil || a.clientsTable.Cursor() >= len(a.clientsData.clients) {
+		return nil
+	}
+
+	client := a.clientsData.clients[a.clientsTable.Cursor()]
+	a.state = StateConfirmClientKill
+	a.confirmDialog = dialogs.NewConfirmDialog(dialogs.ConfirmClientKill, client)
+	a.confirmDialog.SetCallbacks(
+		func() tea.Cmd {
+			a.state = StateClients
+			return a.killClientCmd(client)
+		},
+		func() tea.Cmd {
+			a.state = StateClients
+			return nil
+		},
+	)
+	return nil
+}
+
+// refreshClientsTable filters, sorts and optionally groups the clients and
+// rebuilds the table
+func (a *App) refreshClientsTable() {
+	if a.clientsData == nil {
+		a.clientsTable.SetRows(nil)
+		return
+	}
+
+	filter := strings.ToLower(a.clientsFilter)
+	var clients []redis.ClientInfo
+	nodes := make(map[string]bool)
+	for _, c := range a.clientsData.all {
+		nodes[c.Node] = true
+		if a.clientsOpenGroup != nil && !a.clientsOpenGroup.matches(c) {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(
+			strings.Join([]string{c.Addr, c.Name, c.User, c.Cmd, c.Flags}, " ")), filter) {
+			continue
+		}
+		clients = append(clients, c)
+	}
+
+	sort.SliceStable(clients, func(i, j int) bool {
+		switch a.clientsSort {
+		case clientSortAge:
+			return clients[i].AgeSeconds > clients[j].AgeSeconds
+		case clientSortMemory:
+			return clients[i].Memory > clients[j].Memory
+		case clientSortName:
+			return clients[i].Name < clients[j].Name
+		case clientSortAddr:
+			return clients[i].Addr < clients[j].Addr
+		default:
+			return clients[i].IdleSeconds > clients[j].IdleSeconds
+		}
+	})
+	a.clientsData.clients = clients
+
+	if a.clientsGroup != clientGroupNone {
+		a.refreshClientGroups(clients)
+		return
+	}
+
+	columns := []table.Column{
+		{Title: "ID", Width: 8},
+		{Title: "Address", Width: 22},
+		{Title: "Name"},
+		{Title: "User", Width: 12},
+		{Title: "Age", Width: 12},
+		{Title: "Idle", Width: 12},
+		{Title: "DB", Width: 4},
+		{Title: "Flags", Width: 7},
+		{Title: "Cmd", Width: 16},
+		{Title: "Memory", Width: 10},
+	}
+	showNode := len(nodes) > 1
+	if showNode {
+		columns = append(columns, table.Column{Title: "Node", Width: 22})
+	}
+	a.clientsTable.SetColumns(columns)
+
+	rows := make([]table.Row, len(clients))
+	for i, c := range clients {
+		rows[i] = table.Row{
+			strconv.FormatInt(c.ID, 10),
+			c.Addr,
+			c.Name,
+			c.User,
+			redis.FormatSeconds(c.AgeSeconds),
+			redis.FormatSeconds(c.IdleSeconds),
+			strconv.Itoa(c.DB),
+			c.Flags,
+			c.Cmd,
+			redis.FormatBytes(c.Memory),
+		}
+		if showNode {
+			rows[i] = append(rows[i], c.Node)
+		}
+	}
+	a.clientsTable.SetRows(rows)
+}
+
+// refreshClientGroups aggregates clients by name or source IP
+func (a *App) refreshClientGroups(clients []redis.ClientInfo) {
+	byKey := make(map[string]*clientGroup)
+	var groups []*clientGroup
+	for _, c := range clients {
+		key := a.clientsGroup.groupKey(c)
+		g, ok := byKey[key]
+		if !ok {
+			g = &clientGroup{key: key}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.count++
+		g.memory += c.Memory
+		g.totalAge += c.AgeSeconds
+		if c.IdleSeconds > g.maxIdle {
+			g.maxIdle = c.IdleSeconds
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if a.clientsSort == clientSortMemory {
+			return groups[i].memory > groups[j].memory
+		}
+		return groups[i].count > groups[j].count
+	})
+
+	groupTitle := "Name"
+	if a.clientsGroup == clientGroupIP {
+		groupTitle = "Source IP"
+	}
+	a.clientsTable.SetColumns([]table.Column{
+		{Title: groupTitle},
+		{Title: "Clients", Width: 10},
+		{Title: "Memory", Width: 12},
+		{Title: "Avg Age", Width: 14},
+		{Title: "Max Idle", Width: 14},
+	})
+
+	a.clientsData.groups = make([]clientGroup, len(groups))
+	rows := make([]table.Row, len(groups))
+	for i, g := range groups {
+		a.clientsData.groups[i] = *g
+		rows[i] = table.Row{
+			groupLabel(g.key),
+			formatNumber(int64(g.count)),
+			redis.FormatBytes(g.memory),
+			redis.FormatSeconds(g.totalAge / int64(g.count)),
+			redis.FormatSeconds(g.maxIdle),
+		}
+	}
+	a.clientsTable.SetRows(rows)
+}
+
+func (a App) clientsView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.clientsData == nil || a.clientsData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading clients...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.clientsData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading clients: %v", a.clientsData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(fmt.Sprintf(
+		"Clients (%d of %d shown, sorted by %s, grouped by %s)",
+		len(a.clientsData.clients), len(a.clientsData.all), a.clientsSort, a.clientsGroup))
+
+	var search string
+	if a.clientsSearching {
+		search = a.clientsSearch.View()
+	} else {
+		var filters []string
+		if g := a.clientsOpenGroup; g != nil {
+			filters = append(filters, fmt.Sprintf("Group: %s", g))
+		}
+		if a.clientsFilter != "" {
+			filters = append(filters, fmt.Sprintf("Filter: %s", a.clientsFilter))
+		}
+		if len(filters) > 0 {
+			search = styles.StatsFooterStyle.Render(strings.Join(filters, " | "))
+		}
+	}
+
+	footer := styles.StatsFooterStyle.Render(
+		"↑/↓ select | / filter | s sort | g group | Enter open group | K kill | r reload | a auto-refresh | ESC, q or C close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		search,
+		a.clientsTable.View(),
+		footer,
+	))
+}
@@ -0,0 +1,202 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+)
+
+// ClusterData holds the cluster topology screen contents
+type ClusterData struct {
+	topology   *redis.ClusterTopology
+	loading    bool
+	refreshing bool
+	err        error
+}
+
+// clusterColumnWidths are the topology column widths, the last column takes the rest
+var clusterColumnWidths = []int{24, 10, 12, 10, 12, 12, 22}
+
+func (a *App) openCluster() tea.Cmd {
+	a.state = StateCluster
+	a.clusterData = &ClusterData{loading: true}
+	a.clusterViewport.GotoTop()
+	return a.clusterCmd()
+}
+
+func (a *App) handleClusterState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.clusterViewport, cmd = a.clusterViewport.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "N":
+			a.state = StateDefault
+		case "r":
+			if a.clusterData != nil && !a.clusterData.loading {
+				a.clusterData.refreshing = true
+			}
+			cmd = a.clusterCmd()
+		case "a":
+			a.toggleAutoRefresh()
+		default:
+			a.clusterViewport, cmd = a.clusterViewport.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// refreshClusterContent renders the topology into the viewport
+func (a *App) refreshClusterContent() {
+	if a.clusterData == nil || a.clusterData.topology == nil {
+		a.clusterViewport.SetContent("")
+		return
+	}
+	topology := a.clusterData.topology
+
+	lines := []string{
+		styles.TableHeaderStyle.Render(tableCells(
+			[]string{"Address", "Role", "Slots", "Keys", "Memory", "Health", "Flags", "Slot Ranges"},
+			clusterColumnWidths)),
+	}
+	for _, m := range topology.Masters {
+		lines = append(lines, clusterNodeRow(m, m.Addr))
+		for i, r := range m.Replicas {
+			branch := "├ "
+			if i == len(m.Replicas)-1 {
+				branch = "└ "
+			}
+			lines = append(lines, clusterNodeRow(r, branch+r.Addr))
+		}
+	}
+	for _, n := range topology.Orphans {
+		lines = append(lines, clusterNodeRow(n, "? "+n.Addr))
+	}
+
+	if migrations := topology.Migrations(); len(migrations) > 0 {
+		addrs := make(map[string]string)
+		for _, n := range topology.Nodes() {
+			addrs[n.ID] = n.Addr
+		}
+		nodeName := func(id string) string {
+			if addr, ok := addrs[id]; ok {
+				return addr
+			}
+			return id
+		}
+
+		lines = append(lines, "", styles.InfoSectionStyle.Render(fmt.Sprintf("Slots in migration (%d)", len(migrations))))
+		for _, m := range migrations {
+			lines = append(lines, styles.ChangedStyle.Render(
+				fmt.Sprintf("slot %d: %s → %s", m.Slot, nodeName(m.From), nodeName(m.To))))
+		}
+	}
+
+	a.clusterViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// clusterNodeRow renders a node, highlighting failing nodes and nodes with
+// slots in migration
+func clusterNodeRow(n *redis.ClusterNode, label string) string {
+	role := "replica"
+	if n.IsMaster() {
+		role = "master"
+	}
+
+	keys, memory := "-", "-"
+	if n.Keys >= 0 {
+		keys = formatNumber(n.Keys)
+	}
+	if n.UsedMemory >= 0 {
+		memory = redis.FormatBytes(n.UsedMemory)
+	}
+
+	health := n.Health
+	if health == "" {
+		health = "ok"
+		if n.Failing() {
+			health = "failing"
+		}
+	}
+
+	ranges := make([]string, len(n.Slots))
+	for i, r := range n.Slots {
+		ranges[i] = r.String()
+	}
+
+	slots := ""
+	if n.IsMaster() {
+		slots = formatNumber(int64(n.SlotCount()))
+	}
+
+	row := tableCells([]string{
+		label,
+		role,
+		slots,
+		keys,
+		memory,
+		health,
+		strings.Join(n.Flags, ","),
+		strings.Join(ranges, " "),
+	}, clusterColumnWidths)
+
+	switch {
+	case n.Failing():
+		return styles.StatsErrorStyle.Render(row)
+	case len(n.Migrations) > 0:
+		return styles.ChangedStyle.Render(row)
+	}
+	return row
+}
+
+func (a App) clusterView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.clusterData == nil || a.clusterData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading cluster topology...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.clusterData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading cluster topology: %v", a.clusterData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	topology := a.clusterData.topology
+	var replicas, failing int
+	for _, n := range topology.Nodes() {
+		if !n.IsMaster() {
+			replicas++
+		}
+		if n.Failing() {
+			failing++
+		}
+	}
+
+	state := topology.State
+	if state == "" {
+		state = "unknown"
+	}
+	summary := fmt.Sprintf("Cluster Topology (state %s, %d masters, %d replicas, %s/16384 slots covered",
+		state, len(topology.Masters), replicas, formatNumber(int64(topology.SlotsCovered)))
+	if failing > 0 {
+		summary += fmt.Sprintf(", %d failing", failing)
+	}
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(summary + ")")
+
+	footer := styles.StatsFooterStyle.Render("↑/↓ scroll | r reload | a auto-refresh | ESC, q or N close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		a.clusterViewport.View(),
+		footer,
+	))
+}
@@ -10,7 +10,8 @@ import (
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
-	redisv8 "github.com/go-redis/redis/v8"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/logger"
 	"github.com/hawkins/redis-viewer/internal/redis"
 	"github.com/hawkins/redis-viewer/internal/ui/components/keylist"
 	"github.com/hawkins/redis-viewer/internal/util"
@@ -28,7 +29,8 @@ func (a App) scanCmd() tea.Cmd {
 // scanStreamCmd performs streaming key scan for better performance
 func (a App) scanStreamCmd() tea.Cmd {
 	return func() tea.Msg {
-		keyMessages := redis.GetKeys(a.rdb, cast.ToUint64(a.offset*a.limit), "", a.limit)
+		start := time.Now()
+		keyMessages := redis.GetKeysInScope(a.rdb, a.keyScope, cast.ToUint64(a.offset*a.limit), "", a.limit)
 
 		// Quickly collect all key names (no TYPE/TTL - much faster!)
 		var allKeys []string
@@ -36,6 +38,7 @@ func (a App) scanStreamCmd() tea.Cmd {
 
 		for keyMessage := range keyMessages {
 			if keyMessage.Err != nil {
+				logger.Error("scan failed", "db", a.db, "err", keyMessage.Err)
 				return ErrMsg{Err: keyMessage.Err}
 			}
 
@@ -45,6 +48,8 @@ func (a App) scanStreamCmd() tea.Cmd {
 
 		// Apply filtering
 		filteredKeys := a.applyFilter(allKeys)
+		logger.Info("scan complete", "db", a.db, "scanned", processedCount, "matched", len(filteredKeys),
+			"filter", a.fuzzyFilter, "duration", time.Since(start))
 
 		// Create items WITHOUT fetching TYPE/TTL - this makes scanning MUCH faster
 		var items []list.Item
@@ -94,6 +99,7 @@ func (a App) applyFilter(allKeys []string) []string {
 // loadValueCmd loads the value for a specific key
 func (a App) loadValueCmd(key string, keyType string, ttlSeconds int64) tea.Cmd {
 	return func() tea.Msg {
+		start := time.Now()
 		ctx := context.Background()
 		var (
 			val interface{}
@@ -115,6 +121,14 @@ func (a App) loadValueCmd(key string, keyType string, ttlSeconds int64) tea.Cmd
 			}
 		}
 
+		// Storage details are best effort, the value is shown without them.
+		// They are read before the value, which resets the idle time and
+		// counts as an access for OBJECT FREQ.
+		meta, metaErr := redis.GetKeyMetadata(ctx, a.rdb, key, keyType)
+		if metaErr != nil {
+			logger.Warn("key metadata failed", "key", key, "err", metaErr)
+		}
+
 		// Fetch the value based on key type
 		switch keyType {
 		case "string":
@@ -127,6 +141,8 @@ func (a App) loadValueCmd(key string, keyType string, ttlSeconds int64) tea.Cmd
 			val, err = a.rdb.ZRange(ctx, key, 0, -1).Result()
 		case "hash":
 			val, err = a.rdb.HGetAll(ctx, key).Result()
+		case "stream":
+			val, err = a.rdb.XRange(ctx, key, "-", "+").Result()
 		default:
 			val = ""
 			err = fmt.Errorf("unsupported type: %s", keyType)
@@ -144,16 +160,44 @@ func (a App) loadValueCmd(key string, keyType string, ttlSeconds int64) tea.Cmd
 			}
 		}
 
+		if err != nil {
+			meta = nil
+		}
+
+		logger.Debug("value loaded", "key", key, "type", keyType, "bytes", len(itemValue), "duration", time.Since(start))
+
 		return LoadValueMsg{
 			Key:        key,
 			KeyType:    keyType,
 			Val:        itemValue,
 			Err:        err,
 			TTLSeconds: ttlSeconds,
+			Meta:       meta,
 		}
 	}
 }
 
+// serializedLengthCmd loads the length of the DUMP payload of a key
+func (a App) serializedLengthCmd(key string) tea.Cmd {
+	return func() tea.Msg {
+		length, err := redis.GetSerializedLength(context.Background(), a.rdb, key)
+		return SerializedLengthMsg{Key: key, Length: length, Err: err}
+	}
+}
+
+// refreshValueCmd reloads the value of a key for auto-refresh
+func (a App) refreshValueCmd(key string, keyType string) tea.Cmd {
+	load := a.loadValueCmd(key, keyType, -1)
+	return func() tea.Msg {
+		msg, ok := load().(LoadValueMsg)
+		if !ok {
+			return nil
+		}
+		msg.Refreshed = true
+		return msg
+	}
+}
+
 // displayBatchCmd displays keys in batches for better UX
 func (a App) displayBatchCmd() tea.Cmd {
 	return func() tea.Msg {
@@ -190,7 +234,7 @@ func (a App) displayBatchCmd() tea.Cmd {
 // countCmd counts matching keys
 func (a App) countCmd() tea.Cmd {
 	return func() tea.Msg {
-		count, err := redis.CountKeys(a.rdb, "")
+		count, err := redis.CountKeysInScope(a.rdb, a.keyScope, "")
 		if err != nil {
 			return ErrMsg{Err: err}
 		}
@@ -206,6 +250,85 @@ func (a App) tickCmd() tea.Cmd {
 	})
 }
 
+// autoRefreshCmd returns the reload command for the current screen when the
+// refresh interval has elapsed
+func (a *App) autoRefreshCmd() tea.Cmd {
+	if !a.autoRefresh || time.Since(a.lastRefresh) < a.refreshInterval {
+		return nil
+	}
+
+	switch a.state {
+	case StateStats:
+		if a.statsData == nil || a.statsData.loading || a.statsData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.statsData.refreshing = true
+		return a.statsCmd()
+	case StateInfo:
+		if a.infoData == nil || a.infoData.loading || a.infoData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.infoData.refreshing = true
+		return a.infoCmd()
+	case StateSlowlog:
+		if a.slowLogData == nil || a.slowLogData.loading || a.slowLogData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.slowLogData.refreshing = true
+		return a.slowLogCmd()
+	case StateClients:
+		if a.clientsData == nil || a.clientsData.loading || a.clientsData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.clientsData.refreshing = true
+		return a.clientsCmd()
+	case StateCluster:
+		if a.clusterData == nil || a.clusterData.loading || a.clusterData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.clusterData.refreshing = true
+		return a.clusterCmd()
+	case StateReplication:
+		if a.replicationData == nil || a.replicationData.loading || a.replicationData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.replicationData.refreshing = true
+		return a.replicationCmd()
+	case StateConfig:
+		if a.configData == nil || a.configData.loading || a.configData.refreshing || a.configEditing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.configData.refreshing = true
+		return a.configCmd()
+	case StateACL:
+		if a.aclData == nil || a.aclData.loading || a.aclData.refreshing {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		a.aclData.refreshing = true
+		return a.aclCmd()
+	case StateDefault:
+		if !a.ready {
+			return nil
+		}
+		it := a.getCurrentItem()
+		if it.Key == "" || !it.Loaded || it.Err {
+			return nil
+		}
+		a.lastRefresh = time.Now()
+		return a.refreshValueCmd(it.Key, it.KeyType)
+	}
+
+	return nil
+}
+
 // deleteCmd deletes a key
 func (a App) deleteCmd(key string) tea.Cmd {
 	return func() tea.Msg {
@@ -233,19 +356,13 @@ func (a App) purgeCmd() tea.Cmd {
 // switchDBCmd switches to a different database
 func (a App) switchDBCmd(db int) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-
 		// Create new options with the new database
 		newOpts := *a.redisOpts
 		newOpts.DB = db
 
-		// Create new client with the new database
-		newRdb := redisv8.NewUniversalClient(&newOpts)
-
-		// Test the connection
-		_, err := newRdb.Ping(ctx).Result()
+		// Create new client with the new database and test the connection
+		newRdb, err := redis.Connect(&newOpts)
 		if err != nil {
-			newRdb.Close()
 			return SwitchDBMsg{DB: db, NewRdb: nil, Err: err}
 		}
 
@@ -256,50 +373,139 @@ func (a App) switchDBCmd(db int) tea.Cmd {
 // statsCmd loads server statistics
 func (a App) statsCmd() tea.Cmd {
 	return func() tea.Msg {
+		start := time.Now()
+		defer func() {
+			logger.Debug("stats loaded", "detail", a.statsDetail, "duration", time.Since(start))
+		}()
+
 		// Get server stats
 		serverStats, err := redis.GetServerStats(a.rdb)
 		if err != nil {
 			return StatsMsg{Err: err}
 		}
 
-		// Get stats for multiple databases (0-15 for standard Redis)
-		var dbStats []*redis.DatabaseStats
-		maxDB := 16 // Standard Redis has 16 databases (0-15)
+		// Per-database stats come from INFO keyspace in a single round trip
+		sampleSize := 0
+		if a.statsDetail {
+			sampleSize = constant.TTLSampleSize
+		}
+		dbStats, err := redis.GetDatabaseStats(a.rdb, a.db, sampleSize)
+		if err != nil {
+			return StatsMsg{Err: err}
+		}
+
+		// CONFIG may be disabled (e.g. managed services), the count is informative only
+		databases, _ := redis.GetDatabaseCount(a.rdb)
 
-		// For cluster mode, only DB 0 is available
-		if _, ok := a.rdb.(*redisv8.ClusterClient); ok {
-			maxDB = 1
+		return StatsMsg{
+			ServerStats: serverStats,
+			DBStats:     dbStats,
+			Databases:   databases,
+			Err:         nil,
 		}
+	}
+}
+
+// infoCmd loads every INFO section for the INFO browser
+func (a App) infoCmd() tea.Cmd {
+	return func() tea.Msg {
+		sections, err := redis.GetInfo(a.rdb)
+		return InfoMsg{Sections: sections, Err: err}
+	}
+}
 
-		for i := 0; i < maxDB; i++ {
-			// Create a client for this specific database
-			opts := *a.redisOpts
-			opts.DB = i
+// slowLogCmd loads the slow log of every node
+func (a App) slowLogCmd() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := redis.GetSlowLog(a.rdb, constant.SlowLogCount)
+		return SlowLogMsg{Entries: entries, Err: err}
+	}
+}
 
-			dbClient := redisv8.NewUniversalClient(&opts)
-			defer dbClient.Close()
+// resetSlowLogCmd clears the slow log of every node
+func (a App) resetSlowLogCmd() tea.Cmd {
+	return func() tea.Msg {
+		return SlowLogResetMsg{Err: redis.ResetSlowLog(a.rdb)}
+	}
+}
 
-			// Test if this database is accessible
-			ctx := context.Background()
-			_, err := dbClient.Ping(ctx).Result()
-			if err != nil {
-				// Skip databases that are not accessible
-				continue
-			}
+// clientsCmd loads the connected clients of every node
+func (a App) clientsCmd() tea.Cmd {
+	return func() tea.Msg {
+		clients, err := redis.GetClients(a.rdb)
+		return ClientsMsg{Clients: clients, Err: err}
+	}
+}
 
-			// Get stats for this database (sample 10 keys for TTL average)
-			stats, err := redis.GetDatabaseStats(dbClient, i, 10)
-			if err == nil && stats.Keys > 0 {
-				// Only include databases that have keys
-				dbStats = append(dbStats, stats)
-			}
+// clusterCmd fetches the cluster topology
+func (a App) clusterCmd() tea.Cmd {
+	return func() tea.Msg {
+		topology, err := redis.GetClusterTopology(a.rdb)
+		return ClusterMsg{Topology: topology, Err: err}
+	}
+}
+
+// replicationCmd fetches INFO replication and, for sentinel deployments,
+// the sentinels' view of the masters
+func (a App) replicationCmd() tea.Cmd {
+	return func() tea.Msg {
+		nodes, err := redis.GetReplication(a.rdb)
+		if err != nil {
+			return ReplicationMsg{Err: err}
 		}
 
-		return StatsMsg{
-			ServerStats: serverStats,
-			DBStats:     dbStats,
-			Err:         nil,
+		msg := ReplicationMsg{Nodes: nodes}
+		if a.redisOpts.MasterName != "" {
+			msg.Sentinel, msg.SentinelErr = redis.GetSentinelStatus(a.redisOpts)
 		}
+		return msg
+	}
+}
+
+// configCmd loads the server configuration
+func (a App) configCmd() tea.Cmd {
+	return func() tea.Msg {
+		params, err := redis.GetConfig(a.rdb)
+		return ConfigMsg{Params: params, Err: err}
+	}
+}
+
+// setConfigCmd changes a server configuration parameter
+func (a App) setConfigCmd(change configChange) tea.Cmd {
+	return func() tea.Msg {
+		err := redis.SetConfig(a.rdb, change.name, change.value)
+		return ConfigSetMsg{Name: change.name, Value: change.value, Previous: change.previous, Err: err}
+	}
+}
+
+// rewriteConfigCmd persists the running configuration to the config file
+func (a App) rewriteConfigCmd() tea.Cmd {
+	return func() tea.Msg {
+		return ConfigRewriteMsg{Err: redis.RewriteConfig(a.rdb)}
+	}
+}
+
+// aclCmd loads the current user, the ACL users and the ACL log
+func (a App) aclCmd() tea.Cmd {
+	return func() tea.Msg {
+		status, err := redis.GetACL(a.rdb)
+		return ACLMsg{Status: status, Err: err}
+	}
+}
+
+// killClientCmd closes a client connection
+func (a App) killClientCmd(client redis.ClientInfo) tea.Cmd {
+	return func() tea.Msg {
+		err := redis.KillClient(a.rdb, client.Node, client.ID)
+		return KillClientMsg{Client: client, Err: err}
+	}
+}
+
+// sampleMetricsCmd takes a single server metrics sample for the stats charts
+func (a App) sampleMetricsCmd() tea.Cmd {
+	return func() tea.Msg {
+		serverStats, err := redis.GetServerStats(a.rdb)
+		return MetricsSampleMsg{ServerStats: serverStats, At: time.Now(), Err: err}
 	}
 }
 
@@ -0,0 +1,76 @@
+// Package chart renders small text charts for numeric time series
+package chart
+
+// Series is a fixed-size rolling window of samples
+type Series struct {
+	values []float64
+	size   int
+}
+
+// NewSeries creates a series keeping at most size samples
+func NewSeries(size int) *Series {
+	if size < 1 {
+		size = 1
+	}
+	return &Series{size: size}
+}
+
+// Push appends a sample, dropping the oldest one when the window is full
+func (s *Series) Push(v float64) {
+	s.values = append(s.values, v)
+	if len(s.values) > s.size {
+		s.values = s.values[len(s.values)-s.size:]
+	}
+}
+
+// Values returns the samples from oldest to newest
+func (s *Series) Values() []float64 {
+	return s.values
+}
+
+// Len returns the number of samples held
+func (s *Series) Len() int {
+	return len(s.values)
+}
+
+// Size returns the window length
+func (s *Series) Size() int {
+	return s.size
+}
+
+// Last returns the most recent sample
+func (s *Series) Last() float64 {
+	if len(s.values) == 0 {
+		return 0
+	}
+	return s.values[len(s.values)-1]
+}
+
+// Min returns the smallest sample in the window
+func (s *Series) Min() float64 {
+	lo, _ := bounds(s.values)
+	return lo
+}
+
+// Max returns the largest sample in the window
+func (s *Series) Max() float64 {
+	_, hi := bounds(s.values)
+	return hi
+}
+
+// bounds returns the minimum and maximum of values
+func bounds(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	return lo, hi
+}
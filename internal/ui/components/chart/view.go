@@ -0,0 +1,32 @@
+package chart
+
+import "strings"
+
+var sparkTicks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the newest width samples as a single line of block
+// characters scaled between the window minimum and maximum. Missing samples
+// are left-padded with spaces so the line grows from the right.
+func Sparkline(values []float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	lo, hi := bounds(values)
+	span := hi - lo
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		idx := 0
+		if span > 0 {
+			idx = int((v - lo) / span * float64(len(sparkTicks)-1))
+		}
+		b.WriteRune(sparkTicks[idx])
+	}
+
+	return b.String()
+}
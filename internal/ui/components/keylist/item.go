@@ -1,6 +1,10 @@
 package keylist
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+)
 
 // Item represents a Redis key in the list
 type Item struct {
@@ -9,13 +13,21 @@ type Item struct {
 	Key        string
 	Val        string
 	TTLSeconds int64
+	Meta       *redis.KeyMetadata // storage details, nil until loaded
+	ShowSize   bool               // describe the key with its size from Meta
 
 	Err    bool
 	Loaded bool // indicates if value has been fetched from Redis
+	Marked bool // selected for a bulk action such as copy
 }
 
 // Title implements list.Item
-func (i Item) Title() string { return i.Key }
+func (i Item) Title() string {
+	if i.Marked {
+		return "● " + i.Key
+	}
+	return i.Key
+}
 
 // Description implements list.Item
 func (i Item) Description() string {
@@ -25,7 +37,18 @@ func (i Item) Description() string {
 	if !i.Loaded {
 		return fmt.Sprintf("%s (not loaded)", i.KeyType)
 	}
-	return fmt.Sprintf("%s: %d bytes", i.KeyType, len(i.Val))
+	if i.Meta == nil || !i.ShowSize {
+		return i.KeyType
+	}
+
+	desc := i.KeyType
+	if i.Meta.Elements >= 0 {
+		desc += fmt.Sprintf(", %d %s", i.Meta.Elements, redis.ElementsLabel(i.KeyType))
+	}
+	if i.Meta.Bytes >= 0 {
+		desc += ", " + redis.FormatBytes(i.Meta.Bytes)
+	}
+	return desc
 }
 
 // FilterValue implements list.Item
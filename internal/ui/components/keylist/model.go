@@ -66,6 +66,46 @@ func (m Model) SelectedItem() list.Item {
 	return m.list.SelectedItem()
 }
 
+// ToggleMark marks or unmarks the selected item and returns whether it is
+// marked
+func (m *Model) ToggleMark() bool {
+	it, ok := m.list.SelectedItem().(Item)
+	if !ok {
+		return false
+	}
+	it.Marked = !it.Marked
+	m.list.SetItem(m.list.Index(), it)
+	return it.Marked
+}
+
+// MarkedKeys returns the keys of the marked items in list order
+func (m Model) MarkedKeys() []string {
+	var keys []string
+	for _, listItem := range m.list.Items() {
+		if it, ok := listItem.(Item); ok && it.Marked {
+			keys = append(keys, it.Key)
+		}
+	}
+	return keys
+}
+
+// ClearMarks unmarks every item
+func (m *Model) ClearMarks() {
+	items := m.list.Items()
+	for i, listItem := range items {
+		if it, ok := listItem.(Item); ok && it.Marked {
+			it.Marked = false
+			items[i] = it
+		}
+	}
+	m.list.SetItems(items)
+}
+
+// CursorDown moves the selection to the next item
+func (m *Model) CursorDown() {
+	m.list.CursorDown()
+}
+
 // Index returns the currently selected index
 func (m Model) Index() int {
 	return m.list.Index()
@@ -0,0 +1,126 @@
+// Package table provides a scrollable, selectable text table
+package table
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column describes a table column. A zero Width makes the column take the
+// remaining space.
+type Column struct {
+	Title string
+	Width int
+}
+
+// Row is a table row, one cell per column
+type Row []string
+
+// StyleFunc returns the style of an unselected row, by row index
+type StyleFunc func(row int) lipgloss.Style
+
+// Model represents the table component
+type Model struct {
+	columns   []Column
+	rows      []Row
+	cursor    int
+	offset    int
+	width     int
+	height    int
+	styleFunc StyleFunc
+}
+
+// New creates a new table model
+func New(columns []Column) Model {
+	return Model{columns: columns}
+}
+
+// Init initializes the component
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+// SetSize updates the component size. Height includes the header line.
+func (m *Model) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.clamp()
+}
+
+// SetColumns replaces the columns
+func (m *Model) SetColumns(columns []Column) {
+	m.columns = columns
+}
+
+// Columns returns the columns
+func (m Model) Columns() []Column {
+	return m.columns
+}
+
+// SetRows replaces the rows, keeping the cursor in range
+func (m *Model) SetRows(rows []Row) {
+	m.rows = rows
+	m.clamp()
+}
+
+// SetStyleFunc sets the style of unselected rows. A nil func uses the
+// default row style.
+func (m *Model) SetStyleFunc(fn StyleFunc) {
+	m.styleFunc = fn
+}
+
+// Rows returns the rows
+func (m Model) Rows() []Row {
+	return m.rows
+}
+
+// Cursor returns the index of the selected row
+func (m Model) Cursor() int {
+	return m.cursor
+}
+
+// SetCursor selects the row at index i
+func (m *Model) SetCursor(i int) {
+	m.cursor = i
+	m.clamp()
+}
+
+// SelectedRow returns the selected row, or nil for an empty table
+func (m Model) SelectedRow() Row {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return m.rows[m.cursor]
+}
+
+// visibleRows returns the number of rows that fit below the header
+func (m Model) visibleRows() int {
+	if m.height <= 1 {
+		return 1
+	}
+	return m.height - 1
+}
+
+// clamp keeps the cursor within the rows and the scroll offset around it
+func (m *Model) clamp() {
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+
+	visible := m.visibleRows()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+visible {
+		m.offset = m.cursor - visible + 1
+	}
+	if maxOffset := len(m.rows) - visible; m.offset > maxOffset {
+		m.offset = maxOffset
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
@@ -0,0 +1,36 @@
+package table
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Update handles cursor movement for the table component
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			m.cursor--
+		case "down", "j":
+			m.cursor++
+		case "pgup":
+			m.cursor -= m.visibleRows()
+		case "pgdown":
+			m.cursor += m.visibleRows()
+		case "home", "g":
+			m.cursor = 0
+		case "end", "G":
+			m.cursor = len(m.rows) - 1
+		}
+	case tea.MouseMsg:
+		switch msg.Type {
+		case tea.MouseWheelUp:
+			m.cursor--
+		case tea.MouseWheelDown:
+			m.cursor++
+		}
+	}
+
+	m.clamp()
+	return m, nil
+}
@@ -0,0 +1,91 @@
+package table
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/muesli/reflow/truncate"
+)
+
+// View renders the header and the visible rows
+func (m Model) View() string {
+	widths := m.columnWidths()
+
+	header := make([]string, len(m.columns))
+	for i, col := range m.columns {
+		header[i] = styles.TableHeaderStyle.Render(cell(col.Title, widths[i]))
+	}
+	lines := []string{strings.Join(header, "")}
+
+	end := m.offset + m.visibleRows()
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	for i := m.offset; i < end; i++ {
+		cells := make([]string, len(m.columns))
+		for c := range m.columns {
+			var value string
+			if c < len(m.rows[i]) {
+				value = m.rows[i][c]
+			}
+			cells[c] = cell(value, widths[c])
+		}
+
+		line := strings.Join(cells, "")
+		if i == m.cursor {
+			line = styles.TableSelectedStyle.Render(line)
+		} else if m.styleFunc != nil {
+			line = m.styleFunc(i).Render(line)
+		} else {
+			line = styles.TableRowStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(m.rows) == 0 {
+		lines = append(lines, styles.TableEmptyStyle.Render("No entries"))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// columnWidths resolves zero-width columns to share the remaining width
+func (m Model) columnWidths() []int {
+	widths := make([]int, len(m.columns))
+	fixed, flexible := 0, 0
+	for i, col := range m.columns {
+		widths[i] = col.Width
+		if col.Width > 0 {
+			fixed += col.Width
+		} else {
+			flexible++
+		}
+	}
+
+	if flexible > 0 {
+		share := (m.width - fixed) / flexible
+		if share < 8 {
+			share = 8
+		}
+		for i := range widths {
+			if widths[i] == 0 {
+				widths[i] = share
+			}
+		}
+	}
+
+	return widths
+}
+
+// cell pads or truncates s to exactly width cells, leaving a one cell gap
+func cell(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\n", " ")
+	if lipgloss.Width(s) > width-1 {
+		s = truncate.StringWithTail(s, uint(width-1), "…")
+	}
+	return s + strings.Repeat(" ", width-lipgloss.Width(s))
+}
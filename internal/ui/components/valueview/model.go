@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
 	"github.com/hawkins/redis-viewer/internal/styles"
 	"github.com/hawkins/redis-viewer/internal/ui/components/keylist"
 	"github.com/hawkins/redis-viewer/internal/util"
@@ -20,6 +21,10 @@ type Model struct {
 	wordWrap bool
 	width    int
 	height   int
+
+	// Previous value of a key, used to highlight changes after a refresh
+	prevKey string
+	prevVal string
 }
 
 // New creates a new valueview model
@@ -78,6 +83,18 @@ func (m *Model) SetContent(content string) {
 	m.viewport.SetContent(content)
 }
 
+// SetPrevious records the value a key had before the latest refresh
+func (m *Model) SetPrevious(key, val string) {
+	m.prevKey = key
+	m.prevVal = val
+}
+
+// ClearPrevious drops any recorded previous value
+func (m *Model) ClearPrevious() {
+	m.prevKey = ""
+	m.prevVal = ""
+}
+
 // FormatContent formats content for a keylist item
 func (m Model) FormatContent(item keylist.Item) string {
 	keyType := fmt.Sprintf("KeyType: %s", item.KeyType)
@@ -87,6 +104,7 @@ func (m Model) FormatContent(item keylist.Item) string {
 	divider := styles.DividerStyle.Render(strings.Repeat("-", width))
 
 	var value string
+	changed := 0
 	if !item.Loaded {
 		// Value not loaded yet - show loading message
 		value = styles.LoadingStyle.Render("Loading value...")
@@ -96,6 +114,13 @@ func (m Model) FormatContent(item keylist.Item) string {
 		if m.wordWrap {
 			formattedValue = wordwrap.String(formattedValue, width)
 		}
+		if m.prevKey == item.Key && m.prevVal != item.Val {
+			previous := util.TryPrettyJSON(m.prevVal)
+			if m.wordWrap {
+				previous = wordwrap.String(previous, width)
+			}
+			formattedValue, changed = markChangedLines(previous, formattedValue)
+		}
 		value = fmt.Sprintf("%s", formattedValue)
 	}
 
@@ -104,12 +129,87 @@ func (m Model) FormatContent(item keylist.Item) string {
 		ttlFormatted := formatTTLSeconds(item.TTLSeconds)
 		content = append(content, fmt.Sprintf("TTL: %s (%d seconds)", ttlFormatted, item.TTLSeconds))
 	}
+	if item.Meta != nil {
+		content = append(content, metadataLines(item.KeyType, item.Meta)...)
+	}
+	if changed > 0 {
+		content = append(content, styles.ChangedStyle.Render(fmt.Sprintf("Changed since last refresh: %d line(s)", changed)))
+	}
 
 	content = append(content, divider, key, divider, value)
 
 	return lipgloss.JoinVertical(lipgloss.Left, content...)
 }
 
+// metadataLines renders the storage details of a key, skipping what the
+// server did not report
+func metadataLines(keyType string, meta *redis.KeyMetadata) []string {
+	var storage, size []string
+	if meta.Bytes >= 0 {
+		storage = append(storage, "Memory: "+redis.FormatBytes(meta.Bytes))
+	}
+	if meta.Encoding != "" {
+		storage = append(storage, "Encoding: "+meta.Encoding)
+	}
+	if meta.IdleSeconds >= 0 {
+		storage = append(storage, "Idle: "+redis.FormatSeconds(meta.IdleSeconds))
+	}
+	if meta.Freq >= 0 {
+		storage = append(storage, fmt.Sprintf("Freq: %d", meta.Freq))
+	}
+	if meta.Elements >= 0 {
+		label := redis.ElementsLabel(keyType)
+		size = append(size, fmt.Sprintf("%s%s: %d", strings.ToUpper(label[:1]), label[1:], meta.Elements))
+	}
+	switch {
+	case meta.SerializedLength >= 0:
+		size = append(size, "Serialized: "+redis.FormatBytes(meta.SerializedLength))
+	case meta.SerializedErr != "":
+		size = append(size, "Serialized: "+meta.SerializedErr)
+	case meta.Dumpable:
+		size = append(size, "Serialized: press z")
+	}
+
+	var placement []string
+	if meta.Slot >= 0 {
+		placement = append(placement, fmt.Sprintf("Slot: %d", meta.Slot))
+	}
+	if meta.Node != "" {
+		placement = append(placement, "Node: "+meta.Node)
+	}
+
+	var lines []string
+	for _, fields := range [][]string{storage, size, placement} {
+		if len(fields) > 0 {
+			lines = append(lines, strings.Join(fields, " | "))
+		}
+	}
+	return lines
+}
+
+// markChangedLines prefixes each line of current that differs from the line
+// at the same position in previous with a change marker
+func markChangedLines(previous, current string) (string, int) {
+	prevLines := strings.Split(previous, "\n")
+	curLines := strings.Split(current, "\n")
+
+	changed := 0
+	for i, line := range curLines {
+		if i < len(prevLines) && prevLines[i] == line {
+			curLines[i] = "  " + line
+			continue
+		}
+		curLines[i] = styles.ChangedStyle.Render("▌ ") + line
+		changed++
+	}
+	if changed == 0 && len(prevLines) > len(curLines) {
+		// Only trailing lines were removed
+		changed = len(prevLines) - len(curLines)
+	}
+
+	return strings.Join(curLines, "\n"), changed
+}
+
 // formatTTLSeconds formats TTL in seconds to a human-readable format
 func formatTTLSeconds(seconds int64) string {
 	if seconds <= 0 {
@@ -0,0 +1,311 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/hawkins/redis-viewer/internal/ui/components/table"
+	"github.com/hawkins/redis-viewer/internal/ui/dialogs"
+)
+
+// ConfigData holds the CONFIG browser contents
+type ConfigData struct {
+	all        []redis.ConfigParam
+	params     []redis.ConfigParam // after filtering
+	loading    bool
+	refreshing bool
+	err        error
+}
+
+// configChange is a CONFIG SET waiting for confirmation
+type configChange struct {
+	name     string
+	value    string
+	previous string
+}
+
+func newConfigTable() table.Model {
+	return table.New([]table.Column{
+		{Title: "Parameter", Width: 34},
+		{Title: "Value"},
+		{Title: "Default"},
+	})
+}
+
+func (a *App) openConfig() tea.Cmd {
+	a.state = StateConfig
+	a.configData = &ConfigData{loading: true}
+	a.configFilter = ""
+	a.configModifiedOnly = false
+	a.configSearch.Reset()
+	a.configTable.SetCursor(0)
+	return a.configCmd()
+}
+
+func (a *App) handleConfigState(msg tea.Msg) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	if a.configEditing {
+		return a.handleConfigEdit(msg)
+	}
+
+	if a.configSearching {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.Type {
+			case tea.KeyEscape:
+				a.configSearching = false
+				a.configSearch.Blur()
+				a.configSearch.Reset()
+				a.configFilter = ""
+				a.refreshConfigTable()
+				return nil
+			case tea.KeyEnter:
+				a.configSearching = false
+				a.configSearch.Blur()
+				return nil
+			}
+		}
+
+		a.configSearch, cmd = a.configSearch.Update(msg)
+		if a.configSearch.Value() != a.configFilter {
+			a.configFilter = a.configSearch.Value()
+			a.refreshConfigTable()
+			a.configTable.SetCursor(0)
+		}
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.configTable, cmd = a.configTable.Update(msg)
+		cmds = append(cmds, cmd)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if a.configFilter != "" {
+				a.configFilter = ""
+				a.configSearch.Reset()
+				a.refreshConfigTable()
+				return nil
+			}
+			a.state = StateDefault
+		case "q", "O":
+			a.state = StateDefault
+		case "/":
+			a.configSearching = true
+			a.configSearch.SetValue(a.configFilter)
+			return a.configSearch.Focus()
+		case "m":
+			a.configModifiedOnly = !a.configModifiedOnly
+			a.refreshConfigTable()
+			a.configTable.SetCursor(0)
+		case "r":
+			if a.configData != nil && !a.configData.loading {
+				a.configData.refreshing = true
+			}
+			cmds = append(cmds, a.configCmd())
+		case "a":
+			a.toggleAutoRefresh()
+		case "enter", "e":
+			return a.editConfigParam()
+		case "W":
+			return a.confirmConfigRewrite()
+		default:
+			a.configTable, cmd = a.configTable.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// editConfigParam opens the value input for the selected parameter
+func (a *App) editConfigParam() tea.Cmd {
+	if a.denyWrite("CONFIG SET") {
+		return nil
+	}
+	if a.configData == nil || a.configTable.Cursor() >= len(a.configData.params) {
+		return nil
+	}
+
+	param := a.configData.params[a.configTable.Cursor()]
+	a.configEditing = true
+	a.configInput.Prompt = param.Name + " = "
+	a.configInput.SetValue(param.Value)
+	a.configInput.CursorEnd()
+	return a.configInput.Focus()
+}
+
+func (a *App) handleConfigEdit(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEscape:
+			a.configEditing = false
+			a.configInput.Blur()
+			a.configInput.Reset()
+			return nil
+		case tea.KeyEnter:
+			param := a.configData.params[a.configTable.Cursor()]
+			value := strings.TrimSpace(a.configInput.Value())
+			if value == param.Value {
+				a.statusMessage = fmt.Sprintf("%s is unchanged", param.Name)
+				return nil
+			}
+			if err := redis.ValidateConfigValue(param.Name, value); err != nil {
+				a.statusMessage = fmt.Sprintf("Invalid value: %v", err)
+				return nil
+			}
+
+			a.configEditing = false
+			a.configInput.Blur()
+			a.configInput.Reset()
+			a.confirmConfigSet(configChange{name: param.Name, value: value, previous: param.Value})
+			return nil
+		}
+	}
+
+	a.configInput, cmd = a.configInput.Update(msg)
+	return cmd
+}
+
+// confirmConfigSet asks for confirmation before changing a parameter
+func (a *App) confirmConfigSet(change configChange) {
+	a.state = StateConfirmConfigSet
+	a.confirmDialog = dialogs.NewConfirmDialog(dialogs.ConfirmConfigSet, change)
+	a.confirmDialog.SetCallbacks(
+		func() tea.Cmd {
+			a.state = StateConfig
+			return a.setConfigCmd(change)
+		},
+		func() tea.Cmd {
+			a.state = StateConfig
+			return nil
+		},
+	)
+}
+
+// confirmConfigRewrite asks for confirmation before CONFIG REWRITE
+func (a *App) confirmConfigRewrite() tea.Cmd {
+	if a.denyWrite("CONFIG REWRITE") {
+		return nil
+	}
+
+	a.state = StateConfirmConfigRewrite
+	a.confirmDialog = dialogs.NewConfirmDialog(dialogs.ConfirmConfigRewrite, nil)
+	a.confirmDialog.SetCallbacks(
+		func() tea.Cmd {
+			a.state = StateConfig
+			return a.rewriteConfigCmd()
+		},
+		func() tea.Cmd {
+			a.state = StateConfig
+			return nil
+		},
+	)
+	return nil
+}
+
+// refreshConfigTable filters the parameters and rebuilds the table,
+// highlighting values that differ from their default
+func (a *App) refreshConfigTable() {
+	if a.configData == nil {
+		a.configTable.SetRows(nil)
+		return
+	}
+
+	filter := strings.ToLower(a.configFilter)
+	var params []redis.ConfigParam
+	for _, p := range a.configData.all {
+		if a.configModifiedOnly && !p.Modified() {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(p.Name+" "+p.Value), filter) {
+			continue
+		}
+		params = append(params, p)
+	}
+	a.configData.params = params
+
+	rows := make([]table.Row, len(params))
+	for i, p := range params {
+		value := p.Value
+		if p.Inconsistent {
+			value += " (differs between nodes)"
+		}
+		def := p.Default
+		if !p.HasDefault {
+			def = "?"
+		}
+		rows[i] = table.Row{p.Name, value, def}
+	}
+	a.configTable.SetRows(rows)
+	a.configTable.SetStyleFunc(func(row int) lipgloss.Style {
+		if row < len(params) {
+			switch {
+			case params[row].Inconsistent:
+				return styles.StatsErrorStyle
+			case params[row].Modified():
+				return styles.ChangedStyle
+			}
+		}
+		return styles.TableRowStyle
+	})
+}
+
+func (a App) configView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.configData == nil || a.configData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading configuration...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.configData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading configuration: %v", a.configData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	modified := 0
+	for _, p := range a.configData.all {
+		if p.Modified() {
+			modified++
+		}
+	}
+	shown := "all"
+	if a.configModifiedOnly {
+		shown = "modified only"
+	}
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(fmt.Sprintf(
+		"Configuration (%d of %d shown, %s, %d differ from defaults)",
+		len(a.configData.params), len(a.configData.all), shown, modified))
+
+	var search string
+	switch {
+	case a.configEditing:
+		search = a.configInput.View()
+	case a.configSearching:
+		search = a.configSearch.View()
+	case a.configFilter != "":
+		search = styles.StatsFooterStyle.Render(fmt.Sprintf("Filter: %s", a.configFilter))
+	}
+
+	footer := styles.StatsFooterStyle.Render(
+		"↑/↓ select | / filter | m modified only | Enter edit | W rewrite config file | r reload | a auto-refresh | ESC, q or O close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		search,
+		a.configTable.View(),
+		footer,
+	))
+}
@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+)
+
+// copyFailuresShown is the number of failed keys listed when a copy ends
+const copyFailuresShown = 10
+
+// CopyData holds the copy progress screen contents
+type CopyData struct {
+	job      *analysisJob
+	target   string
+	keys     int
+	conflict redis.CopyConflict
+	sameDB   bool // the target is the current database
+	done     bool
+	stats    redis.CopyStats
+	err      error
+}
+
+// copyKeys returns the keys a copy applies to: the marked keys, or the
+// selected key when none is marked
+func (a App) copyKeys() []string {
+	if keys := a.keyList.MarkedKeys(); len(keys) > 0 {
+		return keys
+	}
+	if key := a.getCurrentItem().Key; key != "" {
+		return []string{key}
+	}
+	return nil
+}
+
+// openCopy prompts for the connection to copy the marked keys to
+func (a *App) openCopy() tea.Cmd {
+	// Copies only read from this connection, so an RDB file or a replica
+	// can be the source; only an explicit read-only mode forbids them
+	if a.config.ReadOnly {
+		a.statusMessage = "Read-only mode: copying keys is disabled"
+		return nil
+	}
+	if len(a.copyKeys()) == 0 {
+		a.statusMessage = "No key to copy"
+		return nil
+	}
+
+	a.state = StateCopyInput
+	a.copyInput.CursorEnd()
+	return a.copyInput.Focus()
+}
+
+func (a *App) handleCopyInputState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEscape:
+			a.copyInput.Blur()
+			a.state = StateDefault
+			return nil
+		case tea.KeyTab:
+			a.copyConflict = (a.copyConflict + 1) % len(redis.CopyConflicts)
+			return nil
+		case tea.KeyEnter:
+			target := strings.TrimSpace(a.copyInput.Value())
+			base := *a.redisOpts
+			base.DB = a.db
+			targetOpts, err := redis.TargetOptions(a.config, &base, target)
+			if err != nil {
+				a.statusMessage = fmt.Sprintf("Invalid target: %v", err)
+				return nil
+			}
+			if targetOpts.RDBFile != "" {
+				a.statusMessage = "Cannot copy into an RDB file"
+				return nil
+			}
+			conflict := redis.CopyConflicts[a.copyConflict]
+			sameDB := base.SameDatabase(targetOpts)
+			if sameDB && conflict != redis.CopyRename {
+				a.statusMessage = "The target is the current database, only the rename policy can copy into it"
+				return nil
+			}
+
+			a.copyInput.Blur()
+			a.state = StateCopy
+			return a.startCopy(target, targetOpts, a.copyKeys(), conflict, sameDB)
+		}
+	}
+
+	a.copyInput, cmd = a.copyInput.Update(msg)
+	return cmd
+}
+
+// copyDescription describes what the copy dialog will do
+func (a App) copyDescription() string {
+	what := fmt.Sprintf("key '%s'", a.getCurrentItem().Key)
+	if marked := len(a.keyList.MarkedKeys()); marked > 0 {
+		what = fmt.Sprintf("%d marked keys", marked)
+	}
+	return fmt.Sprintf("%s, existing keys: %s (tab to change), to", what, conflictLabel(redis.CopyConflicts[a.copyConflict]))
+}
+
+// conflictLabel describes a conflict policy, with the suffix of renamed keys
+func conflictLabel(c redis.CopyConflict) string {
+	if c == redis.CopyRename {
+		return fmt.Sprintf("rename to <key>%s", constant.DefaultCopySuffix)
+	}
+	return c.String()
+}
+
+// startCopy copies keys to the target in the background
+func (a *App) startCopy(target string, targetOpts *redis.Options, keys []string, conflict redis.CopyConflict, sameDB bool) tea.Cmd {
+	rdb := a.rdb
+	opts := redis.CopyOptions{
+		Conflict:  conflict,
+		Suffix:    constant.DefaultCopySuffix,
+		BatchSize: constant.DefaultImportBatchSize,
+		Pause:     a.analysisPause,
+	}
+
+	job, cmd := startAnalysisJob(func(ctx context.Context, progress func(int)) tea.Msg {
+		dst, err := redis.Connect(targetOpts)
+		if err != nil {
+			return CopyMsg{Err: fmt.Errorf("target %s: %w", target, err)}
+		}
+		defer dst.Close()
+
+		stats, err := redis.CopyKeys(ctx, rdb, dst, keys, opts, progress)
+		return CopyMsg{Stats: stats, Err: err}
+	})
+	a.copyData = &CopyData{job: job, target: target, keys: len(keys), conflict: conflict, sameDB: sameDB}
+	return cmd
+}
+
+func (a *App) handleCopyState(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc", "q", "enter":
+			if d := a.copyData; d != nil && !d.done {
+				if msg.String() == "enter" {
+					return nil
+				}
+				// Keys copied so far are kept
+				d.job.stop()
+				a.statusMessage = fmt.Sprintf("Copy to %s cancelled after %d keys", d.target, d.job.scanned)
+			}
+			a.copyData = nil
+			a.state = StateDefault
+		}
+	}
+	return nil
+}
+
+func (a App) copyView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.copyData == nil {
+		return ""
+	}
+	d := a.copyData
+
+	title := styles.StatsTitleStyle.Render(fmt.Sprintf("Copy %s keys from DB %d to %s (existing keys: %s)",
+		formatNumber(int64(d.keys)), a.db, d.target, conflictLabel(d.conflict)))
+
+	if !d.done {
+		percent := 0.0
+		if d.keys > 0 {
+			percent = float64(d.job.scanned) / float64(d.keys)
+		}
+		count := styles.StatsLoadingStyle.Render(fmt.Sprintf("%s %s of %s keys",
+			a.spinner.View(), formatNumber(int64(d.job.scanned)), formatNumber(int64(d.keys))))
+		hint := styles.StatsFooterStyle.Render("Press ESC to cancel, keys already copied are kept")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, title, a.copyProgress.ViewAs(percent), "", count, "", hint))
+	}
+
+	lines := []string{title}
+	if d.err != nil {
+		lines = append(lines, styles.StatsErrorStyle.Render(fmt.Sprintf("Error copying keys: %v", d.err)), "")
+	}
+	s := d.stats
+	lines = append(lines, fmt.Sprintf("Created %d (%d renamed), replaced %d, skipped %d, failed %d",
+		s.Created, s.Renamed, s.Replaced, s.Skipped, s.Failed()))
+	for i, f := range s.Failures {
+		if i == copyFailuresShown {
+			lines = append(lines, fmt.Sprintf("... and %d more", s.Failed()-copyFailuresShown))
+			break
+		}
+		lines = append(lines, styles.StatsErrorStyle.Render(fmt.Sprintf("%s: %v", f.Key, f.Err)))
+	}
+	lines = append(lines, "", styles.StatsFooterStyle.Render("Press ESC, q or Enter to close"))
+
+	return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
@@ -10,6 +10,10 @@ type ConfirmType int
 const (
 	ConfirmDelete ConfirmType = iota
 	ConfirmPurge
+	ConfirmSlowlogReset
+	ConfirmClientKill
+	ConfirmConfigSet
+	ConfirmConfigRewrite
 )
 
 // ConfirmDialog handles yes/no confirmation
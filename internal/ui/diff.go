@@ -0,0 +1,568 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/snapshot"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/hawkins/redis-viewer/internal/ui/components/table"
+	"github.com/hawkins/redis-viewer/internal/util"
+	"github.com/muesli/reflow/truncate"
+)
+
+// diffTTLTolerance is the largest TTL gap the diff screen considers equal
+const diffTTLTolerance = 5 * time.Second
+
+// maxDiffLineCells bounds the line alignment of the side-by-side view,
+// larger values are compared line by line
+const maxDiffLineCells = 1_000_000
+
+// DiffData holds the keyspace diff screen contents. When a snapshot is
+// compared with the live data, the snapshot is side A and the current
+// database side B.
+type DiffData struct {
+	job       *analysisJob
+	target    string
+	match     string
+	snapshot  string           // path of the snapshot compared, empty for a keyspace diff
+	header    *snapshot.Header // of the snapshot, once loaded
+	done      bool
+	diffs     []redis.KeyDiff
+	summary   redis.DiffSummary
+	truncated bool // more keys differ than listed
+	detail    bool // the side-by-side value diff of the selected key is shown
+	err       error
+}
+
+func newDiffTable() table.Model {
+	return table.New(diffColumns("A", "B"))
+}
+
+// diffColumns names the type and TTL columns after the sides compared
+func diffColumns(sideA, sideB string) []table.Column {
+	return []table.Column{
+		{Title: "", Width: 2},
+		{Title: "Key"},
+		{Title: "Type " + sideA, Width: 11},
+		{Title: "Type " + sideB, Width: 11},
+		{Title: "TTL " + sideA, Width: 12},
+		{Title: "TTL " + sideB, Width: 12},
+		{Title: "Differs", Width: 18},
+	}
+}
+
+// openDiff prompts for the connection to compare with, as "target [pattern]"
+func (a *App) openDiff() tea.Cmd {
+	a.state = StateDiffInput
+	if a.diffData != nil {
+		a.diffInput.SetValue(strings.TrimSpace(a.diffData.target + " " + a.diffData.match))
+		a.diffInput.CursorEnd()
+	}
+	return a.diffInput.Focus()
+}
+
+func (a *App) handleDiffInputState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEscape:
+			a.diffInput.Blur()
+			a.state = StateDefault
+			return nil
+		case tea.KeyEnter:
+			fields := strings.Fields(a.diffInput.Value())
+			if len(fields) == 0 || len(fields) > 2 {
+				a.statusMessage = "Enter a database number or profile, optionally followed by a key pattern"
+				return nil
+			}
+			match := ""
+			if len(fields) == 2 {
+				match = fields[1]
+			}
+
+			a.diffInput.Blur()
+			a.state = StateDiff
+			return a.startDiff(fields[0], match)
+		}
+	}
+
+	a.diffInput, cmd = a.diffInput.Update(msg)
+	return cmd
+}
+
+// startDiff compares the current database with target, cancelling any
+// running comparison
+func (a *App) startDiff(target, match string) tea.Cmd {
+	if a.diffData != nil {
+		a.diffData.job.stop()
+	}
+
+	base := *a.redisOpts
+	base.DB = a.db
+	cfg := a.config
+	rdb := a.rdb
+	opts := redis.DiffOptions{
+		Match:        match,
+		BatchSize:    a.analysisBatchSize,
+		Pause:        a.analysisPause,
+		TTLTolerance: diffTTLTolerance,
+	}
+
+	job, cmd := startAnalysisJob(func(ctx context.Context, progress func(int)) tea.Msg {
+		targetOpts, err := redis.TargetOptions(cfg, &base, target)
+		if err != nil {
+			return DiffMsg{Err: err}
+		}
+		other, err := redis.Connect(targetOpts)
+		if err != nil {
+			return DiffMsg{Err: fmt.Errorf("target %s: %w", target, err)}
+		}
+		defer other.Close()
+
+		var diffs []redis.KeyDiff
+		truncated := false
+		summary, err := redis.DiffKeyspaces(ctx, rdb, other, opts, func(d redis.KeyDiff) error {
+			if len(diffs) == constant.DiffMaxKeys {
+				truncated = true
+				return nil
+			}
+			diffs = append(diffs, d)
+			return nil
+		}, progress)
+		return DiffMsg{Diffs: diffs, Summary: summary, Truncated: truncated, Err: err}
+	})
+	a.diffData = &DiffData{job: job, target: target, match: match}
+	a.diffTable.SetColumns(diffColumns("A", "B"))
+	a.diffTable.SetRows(nil)
+	return cmd
+}
+
+// startSnapshotDiff compares the current database with a snapshot file,
+// cancelling any running comparison
+func (a *App) startSnapshotDiff(path string) tea.Cmd {
+	if a.diffData != nil {
+		a.diffData.job.stop()
+	}
+
+	rdb := a.rdb
+	opts := redis.DiffOptions{
+		BatchSize:    a.analysisBatchSize,
+		Pause:        a.analysisPause,
+		TTLTolerance: diffTTLTolerance,
+	}
+
+	job, cmd := startAnalysisJob(func(ctx context.Context, progress func(int)) tea.Msg {
+		snap, err := snapshot.Load(expandHome(path))
+		if err != nil {
+			return DiffMsg{Err: err}
+		}
+
+		var diffs []redis.KeyDiff
+		truncated := false
+		summary, err := snap.Diff(ctx, rdb, opts, func(d redis.KeyDiff) error {
+			if len(diffs) == constant.DiffMaxKeys {
+				truncated = true
+				return nil
+			}
+			diffs = append(diffs, d)
+			return nil
+		}, progress)
+		return DiffMsg{Diffs: diffs, Summary: summary, Truncated: truncated, Snapshot: &snap.Header, Err: err}
+	})
+	a.diffData = &DiffData{job: job, target: filepath.Base(path), snapshot: path}
+	a.diffTable.SetColumns(diffColumns("Then", "Now"))
+	a.diffTable.SetRows(nil)
+	return cmd
+}
+
+// kindLabel names a kind of difference: a change since the snapshot, or the
+// side a key was found on
+func (d *DiffData) kindLabel(kind redis.DiffKind) string {
+	if d.snapshot != "" {
+		return kind.Change()
+	}
+	return kind.String()
+}
+
+func (a *App) handleDiffState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	d := a.diffData
+
+	if d != nil && d.detail {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "esc", "q", "enter", "backspace":
+				d.detail = false
+			default:
+				a.diffViewport, cmd = a.diffViewport.Update(msg)
+			}
+		case tea.MouseMsg:
+			a.diffViewport, cmd = a.diffViewport.Update(msg)
+		}
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.diffTable, cmd = a.diffTable.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "D", "Z":
+			// Leaving cancels a running comparison
+			if d != nil && !d.done {
+				d.job.stop()
+				a.diffData = nil
+			}
+			a.state = StateDefault
+		case "r":
+			switch {
+			case d == nil:
+			case d.snapshot != "":
+				cmd = a.startSnapshotDiff(d.snapshot)
+			default:
+				cmd = a.startDiff(d.target, d.match)
+			}
+		case "enter", "right", "l":
+			a.openDiffDetail()
+		default:
+			a.diffTable, cmd = a.diffTable.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// refreshDiffTable lists the differing keys
+func (a *App) refreshDiffTable() {
+	d := a.diffData
+	if d == nil {
+		a.diffTable.SetRows(nil)
+		return
+	}
+
+	rows := make([]table.Row, len(d.diffs))
+	for i, diff := range d.diffs {
+		typeA, typeB, ttlA, ttlB := "", "", "", ""
+		if diff.A != nil {
+			typeA, ttlA = diff.A.Type, redis.FormatTTLMillis(diff.A.TTLMillis)
+		}
+		if diff.B != nil {
+			typeB, ttlB = diff.B.Type, redis.FormatTTLMillis(diff.B.TTLMillis)
+		}
+		rows[i] = table.Row{diff.Kind.Marker(), diff.Key, typeA, typeB, ttlA, ttlB, d.differsLabel(diff)}
+	}
+
+	diffs := d.diffs
+	a.diffTable.SetRows(rows)
+	a.diffTable.SetCursor(0)
+	a.diffTable.SetStyleFunc(func(row int) lipgloss.Style {
+		if row < len(diffs) {
+			return diffKindStyle(diffs[row].Kind)
+		}
+		return styles.TableRowStyle
+	})
+}
+
+// differsLabel names what differs in a key found on both sides
+func (d *DiffData) differsLabel(diff redis.KeyDiff) string {
+	if diff.Kind != redis.DiffChanged {
+		return d.kindLabel(diff.Kind)
+	}
+	var parts []string
+	if diff.Type {
+		parts = append(parts, "type")
+	}
+	if diff.Value {
+		parts = append(parts, "value")
+	}
+	if diff.TTL {
+		parts = append(parts, "ttl")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func diffKindStyle(kind redis.DiffKind) lipgloss.Style {
+	switch kind {
+	case redis.DiffOnlyA:
+		return styles.DiffRemovedStyle
+	case redis.DiffOnlyB:
+		return styles.DiffAddedStyle
+	}
+	return styles.ChangedStyle
+}
+
+// openDiffDetail shows the values of the selected key side by side
+func (a *App) openDiffDetail() {
+	d := a.diffData
+	if d == nil || !d.done || d.err != nil {
+		return
+	}
+	i := a.diffTable.Cursor()
+	if i < 0 || i >= len(d.diffs) {
+		return
+	}
+
+	d.detail = true
+	a.diffViewport.SetContent(sideBySide(recordText(d.diffs[i].A), recordText(d.diffs[i].B), a.diffViewport.Width))
+	a.diffViewport.GotoTop()
+}
+
+// recordText formats the value of a record like the value pane does, with
+// set members sorted so that both sides line up
+func recordText(r *redis.KeyRecord) string {
+	if r == nil {
+		return ""
+	}
+	switch v := r.Value.(type) {
+	case nil:
+		return fmt.Sprintf("(%s values are not compared)", r.Type)
+	case string:
+		return v
+	case []string:
+		if r.Type == "set" {
+			v = append([]string(nil), v...)
+			sort.Strings(v)
+		}
+		b, _ := util.JsonMarshalIndent(v)
+		return string(b)
+	}
+	b, _ := util.JsonMarshalIndent(r.Value)
+	return string(b)
+}
+
+// diffOp is a step of a line alignment: a line of both sides, of the left
+// side only or of the right side only
+type diffOp struct {
+	left, right int // line indexes, -1 when absent
+}
+
+// alignLines aligns the lines of two texts on their longest common
+// subsequence, or position by position when they are too large
+func alignLines(left, right []string) []diffOp {
+	n, m := len(left), len(right)
+	if n*m > maxDiffLineCells {
+		ops := make([]diffOp, 0, n+m)
+		for i := 0; i < n || i < m; i++ {
+			switch {
+			case i >= n:
+				ops = append(ops, diffOp{-1, i})
+			case i >= m:
+				ops = append(ops, diffOp{i, -1})
+			case left[i] == right[i]:
+				ops = append(ops, diffOp{i, i})
+			default:
+				ops = append(ops, diffOp{i, -1}, diffOp{-1, i})
+			}
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the common subsequence length of left[i:] and right[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case left[i] == right[j]:
+			ops = append(ops, diffOp{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{i, -1})
+			i++
+		default:
+			ops = append(ops, diffOp{-1, j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{i, -1})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{-1, j})
+	}
+	return ops
+}
+
+// sideBySide renders two texts in columns, pairing removed and added lines
+// as changed lines
+func sideBySide(left, right string, width int) string {
+	leftLines := strings.Split(strings.ReplaceAll(left, "\t", "    "), "\n")
+	rightLines := strings.Split(strings.ReplaceAll(right, "\t", "    "), "\n")
+	if left == "" {
+		leftLines = nil
+	}
+	if right == "" {
+		rightLines = nil
+	}
+	ops := alignLines(leftLines, rightLines)
+
+	colWidth := (width - 3) / 2
+	if colWidth < 10 {
+		colWidth = 10
+	}
+	cell := func(s string) string {
+		s = truncate.StringWithTail(s, uint(colWidth), "…")
+		return s + strings.Repeat(" ", colWidth-lipgloss.Width(s))
+	}
+	divider := styles.DividerStyle.Render(" │ ")
+
+	var lines []string
+	for k := 0; k < len(ops); {
+		if ops[k].left >= 0 && ops[k].right >= 0 {
+			lines = append(lines, cell(leftLines[ops[k].left])+divider+cell(rightLines[ops[k].right]))
+			k++
+			continue
+		}
+
+		// Pair a run of removed lines with the added lines that follow
+		var removed, added []string
+		for ; k < len(ops) && ops[k].right < 0; k++ {
+			removed = append(removed, leftLines[ops[k].left])
+		}
+		for ; k < len(ops) && ops[k].left < 0; k++ {
+			added = append(added, rightLines[ops[k].right])
+		}
+		for r := 0; r < len(removed) || r < len(added); r++ {
+			l, rt := cell(""), cell("")
+			style := styles.ChangedStyle
+			switch {
+			case r >= len(added):
+				style = styles.DiffRemovedStyle
+				l = cell(removed[r])
+			case r >= len(removed):
+				style = styles.DiffAddedStyle
+				rt = cell(added[r])
+			default:
+				l, rt = cell(removed[r]), cell(added[r])
+			}
+			lines = append(lines, style.Render(l)+divider+style.Render(rt))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// diffSideLabel describes one side of the comparison in the detail header
+func diffSideLabel(side string, r *redis.KeyRecord) string {
+	if r == nil {
+		return side + ": (missing)"
+	}
+	return fmt.Sprintf("%s: %s, ttl %s", side, r.Type, redis.FormatTTLMillis(r.TTLMillis))
+}
+
+func (a App) diffView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.diffData == nil {
+		return ""
+	}
+	d := a.diffData
+
+	if !d.done {
+		target := d.target
+		if d.snapshot != "" {
+			target = "snapshot " + target
+		}
+		progress := styles.StatsLoadingStyle.Render(fmt.Sprintf(
+			"%s Comparing keys with %s... %s scanned", a.spinner.View(), target, formatNumber(int64(d.job.scanned))))
+		hint := styles.StatsFooterStyle.Render("Press ESC to cancel")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, progress, "", hint))
+	}
+
+	if d.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error comparing keys: %v", d.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	match := "all keys"
+	if d.match != "" {
+		match = d.match
+	}
+
+	if d.detail {
+		diff := d.diffs[a.diffTable.Cursor()]
+		title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(fmt.Sprintf("%s (%s)", diff.Key, d.diffDetails(diff)))
+		sideA, sideB := fmt.Sprintf("A (DB %d)", a.db), "B ("+d.target+")"
+		if d.snapshot != "" {
+			sideA, sideB = "Snapshot", fmt.Sprintf("Live (DB %d)", a.db)
+		}
+		colWidth := (a.diffViewport.Width - 3) / 2
+		header := styles.TableHeaderStyle.Render(fmt.Sprintf("%-*s   %s",
+			colWidth, diffSideLabel(sideA, diff.A), diffSideLabel(sideB, diff.B)))
+		footer := styles.StatsFooterStyle.Render("↑/↓ scroll | ESC, q or Enter back to the list")
+		return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			header,
+			a.diffViewport.View(),
+			footer,
+		))
+	}
+
+	s := d.summary
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(fmt.Sprintf(
+		"Keyspace Diff (A: DB %d, B: %s, %s)", a.db, d.target, match))
+	summary := fmt.Sprintf("%s in both: %s identical, %s changed  |  %s only in A  |  %s only in B",
+		formatNumber(int64(s.Compared)), formatNumber(int64(s.Identical)), formatNumber(int64(s.Changed)),
+		formatNumber(int64(s.OnlyA)), formatNumber(int64(s.OnlyB)))
+	closeKeys := "ESC, q or D close"
+	if h := d.header; h != nil {
+		if h.Match != "" {
+			match = h.Match
+		}
+		title = styles.StatsTitleStyle.Copy().MarginBottom(0).Render(fmt.Sprintf(
+			"Snapshot Diff (%s of DB %d taken %s, %s, live DB %d)",
+			d.target, h.DB, h.Created.Local().Format("2006-01-02 15:04:05"), match, a.db))
+		summary = fmt.Sprintf("%s added  |  %s removed  |  %s modified  |  %s unchanged",
+			formatNumber(int64(s.OnlyB)), formatNumber(int64(s.OnlyA)), formatNumber(int64(s.Changed)),
+			formatNumber(int64(s.Identical)))
+		closeKeys = "ESC, q or Z close"
+	}
+	if d.truncated {
+		summary += fmt.Sprintf("  (first %s listed)", formatNumber(int64(len(d.diffs))))
+	}
+	footer := styles.StatsFooterStyle.Render("↑/↓ select | Enter compare values | r re-run | " + closeKeys)
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		styles.StatsFooterStyle.Render(summary),
+		a.diffTable.View(),
+		footer,
+	))
+}
+
+// diffDetails describes a key diff for the detail title
+func (d *DiffData) diffDetails(diff redis.KeyDiff) string {
+	if diff.Kind == redis.DiffChanged {
+		return diff.Details()
+	}
+	return d.kindLabel(diff.Kind)
+}
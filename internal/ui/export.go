@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	redisv8 "github.com/go-redis/redis/v8"
+	"github.com/hawkins/redis-viewer/internal/export"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/ui/components/keylist"
+)
+
+// exportFormats are the formats offered by the export dialog, cycled with Tab
+var exportFormats = []export.Format{export.FormatRaw, export.FormatPretty, export.FormatJSONL, export.FormatCommands, export.FormatRESP}
+
+// openExport prompts for the destination of an export of the selected key
+func (a *App) openExport() tea.Cmd {
+	if a.getCurrentItem().Key == "" {
+		a.statusMessage = "No key to export"
+		return nil
+	}
+
+	a.state = StateExportInput
+	a.exportAll = false
+	a.exportInput.SetValue(a.defaultExportPath())
+	a.exportInput.CursorEnd()
+	return a.exportInput.Focus()
+}
+
+func (a *App) handleExportInputState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEscape:
+			a.exportInput.Blur()
+			a.exportInput.Reset()
+			a.state = StateDefault
+			return nil
+		case tea.KeyTab:
+			// Keep the suggested file name in line with the format
+			path := a.exportInput.Value()
+			old := exportFormats[a.exportFormat].Extension()
+			a.exportFormat = (a.exportFormat + 1) % len(exportFormats)
+			if strings.HasSuffix(path, old) {
+				a.exportInput.SetValue(strings.TrimSuffix(path, old) + exportFormats[a.exportFormat].Extension())
+				a.exportInput.CursorEnd()
+			}
+			return nil
+		case tea.KeyCtrlA:
+			suggested := a.exportInput.Value() == a.defaultExportPath()
+			a.exportAll = !a.exportAll
+			if suggested {
+				a.exportInput.SetValue(a.defaultExportPath())
+				a.exportInput.CursorEnd()
+			}
+			return nil
+		case tea.KeyEnter:
+			path := strings.TrimSpace(a.exportInput.Value())
+			if path == "" {
+				a.statusMessage = "Export path cannot be empty"
+				return nil
+			}
+
+			a.exportInput.Blur()
+			a.exportInput.Reset()
+			a.state = StateDefault
+
+			keys := []string{a.getCurrentItem().Key}
+			if a.exportAll {
+				keys = a.listedKeys()
+			}
+			format := exportFormats[a.exportFormat]
+			a.statusMessage = fmt.Sprintf("Exporting %d keys to %s...", len(keys), path)
+			return a.exportCmd(keys, format, path)
+		}
+	}
+
+	a.exportInput, cmd = a.exportInput.Update(msg)
+	return cmd
+}
+
+// defaultExportPath suggests a file name for the export in the working directory
+func (a App) defaultExportPath() string {
+	name := "redis-viewer-export"
+	if !a.exportAll {
+		name = sanitizeFilename(a.getCurrentItem().Key)
+	}
+	return name + exportFormats[a.exportFormat].Extension()
+}
+
+// listedKeys returns the keys of the key list, including the scan results
+// not displayed yet
+func (a App) listedKeys() []string {
+	var keys []string
+	for _, listItem := range a.keyList.Items() {
+		if it, ok := listItem.(keylist.Item); ok {
+			keys = append(keys, it.Key)
+		}
+	}
+	if a.pendingScanIndex < len(a.pendingScanItems) {
+		for _, it := range a.pendingScanItems[a.pendingScanIndex:] {
+			keys = append(keys, it.Key)
+		}
+	}
+	return keys
+}
+
+// exportDescription describes what the export dialog will write
+func (a App) exportDescription() string {
+	what := fmt.Sprintf("key '%s'", a.getCurrentItem().Key)
+	toggle := "ctrl+a all listed keys"
+	if a.exportAll {
+		what = fmt.Sprintf("%d listed keys", len(a.listedKeys()))
+		toggle = "ctrl+a selected key"
+	}
+	return fmt.Sprintf("%s as %s (tab format, %s)", what, exportFormats[a.exportFormat], toggle)
+}
+
+// exportCmd writes the records of keys to a new file
+func (a App) exportCmd(keys []string, format export.Format, path string) tea.Cmd {
+	return func() tea.Msg {
+		written, skipped, err := exportKeys(a.rdb, keys, format, expandHome(path), a.analysisBatchSize)
+		return ExportMsg{Path: path, Format: format, Keys: written, Skipped: skipped, Err: err}
+	}
+}
+
+// exportKeys loads the records of keys in batches and writes them to path.
+// An existing file is never overwritten.
+func exportKeys(rdb redisv8.UniversalClient, keys []string, format export.Format, path string, batchSize int) (int, int, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	buf := bufio.NewWriter(f)
+	w, err := export.NewWriter(buf, format)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if batchSize <= 0 {
+		batchSize = len(keys)
+	}
+	ctx := context.Background()
+	written, skipped := 0, 0
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		records, err := redis.LoadRecords(ctx, rdb, keys[start:end], format.Dump())
+		if err != nil {
+			return written, skipped, err
+		}
+		for _, r := range records {
+			if err := w.Write(r); err != nil {
+				if errors.Is(err, export.ErrUnsupportedType) {
+					skipped++
+					continue
+				}
+				return written, skipped, err
+			}
+			written++
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return written, skipped, err
+	}
+	return written, skipped, buf.Flush()
+}
+
+// expandHome expands a leading ~ to the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		logger.Warn("home directory unknown", "err", err)
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
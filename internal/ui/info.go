@@ -0,0 +1,237 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+)
+
+// InfoData holds the INFO browser contents
+type InfoData struct {
+	sections   []redis.InfoSection
+	previous   map[string]string // field values from the previous load, used for deltas
+	loading    bool
+	refreshing bool
+	err        error
+}
+
+// infoFieldWidth is the label column width of the INFO browser
+const infoFieldWidth = 36
+
+func (a *App) handleInfoState(msg tea.Msg) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	if a.infoSearching {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.Type {
+			case tea.KeyEscape:
+				a.infoSearching = false
+				a.infoSearch.Blur()
+				a.infoSearch.Reset()
+				a.infoFilter = ""
+				a.refreshInfoContent()
+				return nil
+			case tea.KeyEnter:
+				a.infoSearching = false
+				a.infoSearch.Blur()
+				return nil
+			}
+		}
+
+		a.infoSearch, cmd = a.infoSearch.Update(msg)
+		if a.infoSearch.Value() != a.infoFilter {
+			a.infoFilter = a.infoSearch.Value()
+			a.refreshInfoContent()
+			a.infoViewport.GotoTop()
+		}
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.infoViewport, cmd = a.infoViewport.Update(msg)
+		cmds = append(cmds, cmd)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			if a.infoFilter != "" {
+				a.infoFilter = ""
+				a.infoSearch.Reset()
+				a.refreshInfoContent()
+				return nil
+			}
+			a.state = StateDefault
+		case "I", "q":
+			a.state = StateDefault
+		case "/":
+			a.infoSearching = true
+			a.infoSearch.SetValue(a.infoFilter)
+			return a.infoSearch.Focus()
+		case "r":
+			if a.infoData != nil && !a.infoData.loading {
+				a.infoData.refreshing = true
+			}
+			cmds = append(cmds, a.infoCmd())
+		case "a":
+			a.toggleAutoRefresh()
+		case "tab":
+			a.jumpInfoSection(1)
+		case "shift+tab":
+			a.jumpInfoSection(-1)
+		default:
+			a.infoViewport, cmd = a.infoViewport.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// infoValues returns the currently displayed INFO fields keyed by section and name
+func (a App) infoValues() map[string]string {
+	values := make(map[string]string)
+	if a.infoData == nil {
+		return values
+	}
+	for _, section := range a.infoData.sections {
+		for _, field := range section.Fields {
+			values[section.Name+"/"+field.Key] = field.Value
+		}
+	}
+	return values
+}
+
+// jumpInfoSection scrolls the INFO browser to the next (dir > 0) or previous section
+func (a *App) jumpInfoSection(dir int) {
+	current := a.infoViewport.YOffset
+	if dir > 0 {
+		for _, offset := range a.infoSectionOffsets {
+			if offset > current {
+				a.infoViewport.SetYOffset(offset)
+				return
+			}
+		}
+		return
+	}
+	for i := len(a.infoSectionOffsets) - 1; i >= 0; i-- {
+		if a.infoSectionOffsets[i] < current {
+			a.infoViewport.SetYOffset(a.infoSectionOffsets[i])
+			return
+		}
+	}
+}
+
+// refreshInfoContent re-renders the INFO browser viewport content
+func (a *App) refreshInfoContent() {
+	a.infoSectionOffsets = nil
+	if a.infoData == nil {
+		a.infoViewport.SetContent("")
+		return
+	}
+
+	filter := strings.ToLower(a.infoFilter)
+	var lines []string
+
+	for _, section := range a.infoData.sections {
+		var rows []string
+		for _, field := range section.Fields {
+			if filter != "" &&
+				!strings.Contains(strings.ToLower(field.Key), filter) &&
+				!strings.Contains(strings.ToLower(field.Value), filter) {
+				continue
+			}
+
+			row := styles.StatsLabelStyle.Copy().Width(infoFieldWidth).Render(field.Key) +
+				styles.StatsValueStyle.Render(field.Value)
+			if prev, ok := a.infoData.previous[section.Name+"/"+field.Key]; ok {
+				if delta := formatInfoDelta(prev, field.Value); delta != "" {
+					row += styles.InfoDeltaStyle.Render("  " + delta)
+				}
+			}
+			rows = append(rows, row)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		a.infoSectionOffsets = append(a.infoSectionOffsets, len(lines))
+		lines = append(lines, styles.InfoSectionStyle.Render("# "+section.Name))
+		lines = append(lines, rows...)
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, styles.StatsFooterStyle.Render("No fields match the search"))
+	}
+
+	a.infoViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// formatInfoDelta returns the signed difference between two numeric INFO
+// values, or "" when they are equal or not numeric
+func formatInfoDelta(prev, cur string) string {
+	if prev == cur {
+		return ""
+	}
+
+	if p, err := strconv.ParseInt(prev, 10, 64); err == nil {
+		if c, err := strconv.ParseInt(cur, 10, 64); err == nil {
+			return fmt.Sprintf("%+d", c-p)
+		}
+	}
+
+	p, err := strconv.ParseFloat(prev, 64)
+	if err != nil {
+		return ""
+	}
+	c, err := strconv.ParseFloat(cur, 64)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%+.2f", c-p)
+}
+
+func (a App) infoView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.infoData == nil || a.infoData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading INFO...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.infoData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading INFO: %v", a.infoData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render("Redis INFO")
+
+	var search string
+	switch {
+	case a.infoSearching:
+		search = a.infoSearch.View()
+	case a.infoFilter != "":
+		search = styles.StatsFooterStyle.Render(fmt.Sprintf("Search: %s", a.infoFilter))
+	}
+
+	footer := styles.StatsFooterStyle.Render(
+		"↑/↓ scroll | Tab/Shift+Tab jump section | / search | r reload | a auto-refresh | ESC, q or I close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		search,
+		a.infoViewport.View(),
+		footer,
+	))
+}
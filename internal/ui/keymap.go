@@ -15,6 +15,23 @@ type KeyMap struct {
 	Stats       key.Binding
 	Edit        key.Binding
 	Create      key.Binding
+	AutoRefresh key.Binding
+	Info        key.Binding
+	SlowLog     key.Binding
+	Clients     key.Binding
+	BigKeys     key.Binding
+	Prefixes    key.Binding
+	TTLReport   key.Binding
+	Cluster     key.Binding
+	KeyScope    key.Binding
+	Replication key.Binding
+	Config      key.Binding
+	ACL         key.Binding
+	Export      key.Binding
+	Diff        key.Binding
+	Copy        key.Binding
+	Snapshot    key.Binding
+	Serialized  key.Binding
 }
 
 // DefaultKeyMap returns a set of default keybindings
@@ -53,5 +70,56 @@ func DefaultKeyMap() KeyMap {
 		Create: key.NewBinding(
 			key.WithKeys("n"),
 		),
+		AutoRefresh: key.NewBinding(
+			key.WithKeys("a"),
+		),
+		Info: key.NewBinding(
+			key.WithKeys("I"),
+		),
+		SlowLog: key.NewBinding(
+			key.WithKeys("L"),
+		),
+		Clients: key.NewBinding(
+			key.WithKeys("C"),
+		),
+		BigKeys: key.NewBinding(
+			key.WithKeys("B"),
+		),
+		Prefixes: key.NewBinding(
+			key.WithKeys("M"),
+		),
+		TTLReport: key.NewBinding(
+			key.WithKeys("T"),
+		),
+		Cluster: key.NewBinding(
+			key.WithKeys("N"),
+		),
+		KeyScope: key.NewBinding(
+			key.WithKeys("S"),
+		),
+		Replication: key.NewBinding(
+			key.WithKeys("R"),
+		),
+		Config: key.NewBinding(
+			key.WithKeys("O"),
+		),
+		ACL: key.NewBinding(
+			key.WithKeys("A"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("E"),
+		),
+		Diff: key.NewBinding(
+			key.WithKeys("D"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+		),
+		Snapshot: key.NewBinding(
+			key.WithKeys("Z"),
+		),
+		Serialized: key.NewBinding(
+			key.WithKeys("z"),
+		),
 	}
 }
@@ -1,8 +1,12 @@
 package ui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/hawkins/redis-viewer/internal/export"
 	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/snapshot"
 )
 
 // Error message
@@ -35,6 +39,15 @@ type LoadValueMsg struct {
 	Val        string
 	Err        error
 	TTLSeconds int64
+	Meta       *redis.KeyMetadata
+	Refreshed  bool // true when produced by auto-refresh
+}
+
+// Serialized length message
+type SerializedLengthMsg struct {
+	Key    string
+	Length int64
+	Err    error
 }
 
 // Count message
@@ -77,6 +90,127 @@ type SwitchDBMsg struct {
 type StatsMsg struct {
 	ServerStats *redis.ServerStats
 	DBStats     []*redis.DatabaseStats
+	Databases   int
+	Err         error
+}
+
+type InfoMsg struct {
+	Sections []redis.InfoSection
+	Err      error
+}
+
+// Slow log messages
+type SlowLogMsg struct {
+	Entries []redis.SlowLogEntry
+	Err     error
+}
+
+type SlowLogResetMsg struct {
+	Err error
+}
+
+// Client list messages
+type ClientsMsg struct {
+	Clients []redis.ClientInfo
+	Err     error
+}
+
+type KillClientMsg struct {
+	Client redis.ClientInfo
+	Err    error
+}
+
+type ClusterMsg struct {
+	Topology *redis.ClusterTopology
+	Err      error
+}
+
+type ReplicationMsg struct {
+	Nodes       []redis.ReplicationInfo
+	Sentinel    *redis.SentinelStatus
+	SentinelErr error
+	Err         error
+}
+
+// CONFIG browser messages
+type ConfigMsg struct {
+	Params []redis.ConfigParam
+	Err    error
+}
+
+type ConfigSetMsg struct {
+	Name     string
+	Value    string
+	Previous string
+	Err      error
+}
+
+type ConfigRewriteMsg struct {
+	Err error
+}
+
+type ACLMsg struct {
+	Status *redis.ACLStatus
+	Err    error
+}
+
+// Export messages
+type ExportMsg struct {
+	Path    string
+	Format  export.Format
+	Keys    int
+	Skipped int
+	Err     error
+}
+
+// Keyspace diff messages
+type DiffMsg struct {
+	Diffs     []redis.KeyDiff
+	Summary   redis.DiffSummary
+	Truncated bool             // more keys differ than listed
+	Snapshot  *snapshot.Header // of the snapshot compared, if any
+	Err       error
+}
+
+// Snapshot messages
+type SnapshotMsg struct {
+	Path  string
+	Match string
+	Keys  int
+	Err   error
+}
+
+// Copy messages
+type CopyMsg struct {
+	Stats redis.CopyStats
+	Err   error
+}
+
+// Keyspace analysis messages
+type AnalysisProgressMsg struct {
+	Job     *analysisJob
+	Scanned int
+}
+
+type BigKeysMsg struct {
+	Report *redis.BigKeysReport
+	Err    error
+}
+
+type TTLReportMsg struct {
+	Report *redis.TTLReport
+	Err    error
+}
+
+type PrefixesMsg struct {
+	Report *redis.PrefixReport
+	Full   bool // every key was scanned
+	Err    error
+}
+
+type MetricsSampleMsg struct {
+	ServerStats *redis.ServerStats
+	At          time.Time
 	Err         error
 }
 
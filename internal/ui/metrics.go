@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/ui/components/chart"
+)
+
+// MetricsHistory keeps rolling server metrics sampled while the stats page is open
+type MetricsHistory struct {
+	OpsPerSec  *chart.Series
+	UsedMemory *chart.Series
+	Clients    *chart.Series
+	HitRatio   *chart.Series // percentage of lookups that hit, per sample
+	Evictions  *chart.Series // keys evicted per second
+
+	last   *redis.ServerStats
+	lastAt time.Time
+}
+
+// NewMetricsHistory creates a history holding window samples per metric
+func NewMetricsHistory(window int) *MetricsHistory {
+	return &MetricsHistory{
+		OpsPerSec:  chart.NewSeries(window),
+		UsedMemory: chart.NewSeries(window),
+		Clients:    chart.NewSeries(window),
+		HitRatio:   chart.NewSeries(window),
+		Evictions:  chart.NewSeries(window),
+	}
+}
+
+// Add records a sample. Counter-based metrics are derived from the
+// difference with the previous sample.
+func (h *MetricsHistory) Add(stats *redis.ServerStats, at time.Time) {
+	h.OpsPerSec.Push(float64(stats.OpsPerSec))
+	h.UsedMemory.Push(float64(stats.UsedMemoryBytes))
+	h.Clients.Push(float64(stats.ConnectedClients))
+
+	hits, misses := stats.KeyspaceHits, stats.KeyspaceMisses
+	var evictionRate float64
+	if h.last != nil {
+		hits -= h.last.KeyspaceHits
+		misses -= h.last.KeyspaceMisses
+		if elapsed := at.Sub(h.lastAt).Seconds(); elapsed > 0 {
+			evictionRate = float64(stats.EvictedKeys-h.last.EvictedKeys) / elapsed
+		}
+	}
+	if hits+misses > 0 {
+		h.HitRatio.Push(float64(hits) / float64(hits+misses) * 100)
+	} else {
+		// No lookups since the last sample, carry the previous ratio forward
+		h.HitRatio.Push(h.HitRatio.Last())
+	}
+	if evictionRate < 0 {
+		// Counters were reset (CONFIG RESETSTAT or restart)
+		evictionRate = 0
+	}
+	h.Evictions.Push(evictionRate)
+
+	h.last = stats
+	h.lastAt = at
+}
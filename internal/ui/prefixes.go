@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/hawkins/redis-viewer/internal/ui/components/table"
+)
+
+// PrefixesData holds the key prefix breakdown screen contents
+type PrefixesData struct {
+	job    *analysisJob
+	report *redis.PrefixReport
+	full   bool                 // every key was scanned
+	path   []*redis.PrefixStats // drill-down stack, the last element is shown
+	rows   []*redis.PrefixStats // in display order, nil for the "(other)" row
+	err    error
+}
+
+// current returns the prefix whose sub-prefixes are listed
+func (d *PrefixesData) current() *redis.PrefixStats {
+	if len(d.path) == 0 {
+		return d.report.Root
+	}
+	return d.path[len(d.path)-1]
+}
+
+func newPrefixesTable() table.Model {
+	return table.New([]table.Column{
+		{Title: "Prefix"},
+		{Title: "Keys", Width: 12},
+		{Title: "% Keys", Width: 8},
+		{Title: "Memory", Width: 12},
+		{Title: "% Memory", Width: 10},
+		{Title: "TTL Set", Width: 9},
+		{Title: "Sub-prefixes", Width: 12},
+	})
+}
+
+func (a *App) openPrefixes() tea.Cmd {
+	a.state = StatePrefixes
+	return a.startPrefixes(a.prefixesFull)
+}
+
+// startPrefixes starts a new prefix analysis, cancelling any running one
+func (a *App) startPrefixes(full bool) tea.Cmd {
+	if a.prefixesData != nil {
+		a.prefixesData.job.stop()
+	}
+	a.prefixesFull = full
+
+	rdb := a.rdb
+	opts := a.scanStatsOptions()
+	if !full {
+		opts.Limit = a.analysisSampleSize
+	}
+	delimiter, depth := a.prefixDelimiter, a.prefixDepth
+
+	job, cmd := startAnalysisJob(func(ctx context.Context, progress func(int)) tea.Msg {
+		report, err := redis.AnalysePrefixes(ctx, rdb, opts, delimiter, depth, progress)
+		return PrefixesMsg{Report: report, Full: opts.Limit == 0 || report.Root.Keys < int64(opts.Limit), Err: err}
+	})
+	a.prefixesData = &PrefixesData{job: job}
+	return cmd
+}
+
+func (a *App) handlePrefixesState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.prefixesTable, cmd = a.prefixesTable.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "M":
+			// Leaving cancels a running analysis
+			if a.prefixesData != nil && a.prefixesData.report == nil {
+				a.prefixesData.job.stop()
+				a.prefixesData = nil
+			}
+			a.state = StateDefault
+		case "r":
+			cmd = a.startPrefixes(a.prefixesFull)
+		case "f":
+			cmd = a.startPrefixes(!a.prefixesFull)
+		case "s":
+			a.prefixesSort = (a.prefixesSort + 1) % (redis.PrefixSortName + 1)
+			a.refreshPrefixRows(0)
+		case "enter", "right", "l":
+			a.drillDownPrefix()
+		case "backspace", "left", "h":
+			a.drillUpPrefix()
+		default:
+			a.prefixesTable, cmd = a.prefixesTable.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// drillDownPrefix lists the sub-prefixes of the selected prefix
+func (a *App) drillDownPrefix() {
+	d := a.prefixesData
+	if d == nil || d.report == nil {
+		return
+	}
+	i := a.prefixesTable.Cursor()
+	if i < 0 || i >= len(d.rows) || d.rows[i] == nil || !d.rows[i].HasChildren() {
+		return
+	}
+	d.path = append(d.path, d.rows[i])
+	a.refreshPrefixRows(0)
+}
+
+// drillUpPrefix goes back to the parent prefix, selecting the one we came from
+func (a *App) drillUpPrefix() {
+	d := a.prefixesData
+	if d == nil || d.report == nil || len(d.path) == 0 {
+		return
+	}
+	from := d.path[len(d.path)-1]
+	d.path = d.path[:len(d.path)-1]
+	a.refreshPrefixRows(0)
+	for i, p := range d.rows {
+		if p == from {
+			a.prefixesTable.SetCursor(i)
+			break
+		}
+	}
+}
+
+// refreshPrefixRows lists the sub-prefixes of the current prefix, sorted,
+// followed by the keys that belong to none of them
+func (a *App) refreshPrefixRows(cursor int) {
+	d := a.prefixesData
+	if d == nil || d.report == nil {
+		a.prefixesTable.SetRows(nil)
+		return
+	}
+
+	root := d.report.Root
+	node := d.current()
+	children := node.Children()
+	redis.SortPrefixes(children, a.prefixesSort)
+
+	d.rows = make([]*redis.PrefixStats, 0, len(children)+1)
+	rows := make([]table.Row, 0, len(children)+1)
+	for _, c := range children {
+		d.rows = append(d.rows, c)
+		sub := ""
+		if c.HasChildren() {
+			sub = formatNumber(int64(len(c.Children())))
+		}
+		rows = append(rows, prefixRow(c.Prefix, *c, root, sub))
+	}
+	if other := node.Other(); other.Keys > 0 {
+		label := "(other)"
+		if node == root {
+			label = "(no prefix)"
+		}
+		d.rows = append(d.rows, nil)
+		rows = append(rows, prefixRow(label, other, root, ""))
+	}
+
+	a.prefixesTable.SetRows(rows)
+	a.prefixesTable.SetCursor(cursor)
+}
+
+func prefixRow(label string, p redis.PrefixStats, root *redis.PrefixStats, sub string) table.Row {
+	return table.Row{
+		label,
+		formatNumber(p.Keys),
+		formatPercent(p.Keys, root.Keys),
+		redis.FormatBytes(p.Bytes),
+		formatPercent(p.Bytes, root.Bytes),
+		fmt.Sprintf("%.1f%%", p.TTLCoverage()),
+		sub,
+	}
+}
+
+// formatPercent renders n as a percentage of total
+func formatPercent(n, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(n)/float64(total)*100)
+}
+
+func (a App) prefixesView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.prefixesData == nil {
+		return ""
+	}
+	d := a.prefixesData
+
+	if d.report == nil {
+		progress := styles.StatsLoadingStyle.Render(fmt.Sprintf(
+			"%s Analysing key prefixes... %s done", a.spinner.View(), formatNumber(int64(d.job.scanned))))
+		hint := styles.StatsFooterStyle.Render("Press ESC to cancel")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, progress, "", hint))
+	}
+
+	if d.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error analysing key prefixes: %v", d.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	root := d.report.Root
+	scope := "all keys"
+	if !d.full {
+		scope = "sample of " + formatNumber(root.Keys) + " keys"
+	}
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(fmt.Sprintf(
+		"Key Prefixes (DB %d, %s, %s, sorted by %s)", a.db, scope, redis.FormatBytes(root.Bytes), a.prefixesSort))
+
+	crumbs := []string{"(all)"}
+	for _, p := range d.path {
+		crumbs = append(crumbs, p.Prefix)
+	}
+	node := d.current()
+	path := styles.StatsFooterStyle.Render(fmt.Sprintf("%s  —  %s keys, %s, delimiter %q, depth %d",
+		strings.Join(crumbs, " › "), formatNumber(node.Keys), redis.FormatBytes(node.Bytes),
+		d.report.Delimiter, d.report.MaxDepth))
+
+	scanToggle := "f full scan"
+	if d.full {
+		scanToggle = "f sampled scan"
+	}
+	footer := styles.StatsFooterStyle.Render(fmt.Sprintf(
+		"↑/↓ select | Enter drill down | Backspace up | s sort | %s | r re-run | ESC, q or M close", scanToggle))
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		path,
+		a.prefixesTable.View(),
+		footer,
+	))
+}
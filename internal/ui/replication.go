@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+)
+
+// ReplicationData holds the replication and sentinel screen contents
+type ReplicationData struct {
+	nodes       []redis.ReplicationInfo
+	sentinel    *redis.SentinelStatus // nil outside of sentinel deployments
+	sentinelErr error
+	loading     bool
+	refreshing  bool
+	err         error
+}
+
+// replicationColumnWidths are the replica table column widths, the last column takes the rest
+var replicationColumnWidths = []int{24, 14, 16, 14, 10}
+
+func (a *App) openReplication() tea.Cmd {
+	a.state = StateReplication
+	a.replicationData = &ReplicationData{loading: true}
+	a.replicationViewport.GotoTop()
+	return a.replicationCmd()
+}
+
+func (a *App) handleReplicationState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.replicationViewport, cmd = a.replicationViewport.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "R":
+			a.state = StateDefault
+		case "r":
+			if a.replicationData != nil && !a.replicationData.loading {
+				a.replicationData.refreshing = true
+			}
+			cmd = a.replicationCmd()
+		case "a":
+			a.toggleAutoRefresh()
+		default:
+			a.replicationViewport, cmd = a.replicationViewport.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// refreshReplicationContent renders replication and sentinel details into the viewport
+func (a *App) refreshReplicationContent() {
+	d := a.replicationData
+	if d == nil || d.err != nil {
+		a.replicationViewport.SetContent("")
+		return
+	}
+
+	header := func(cells ...string) string {
+		return styles.TableHeaderStyle.Render(tableCells(cells, replicationColumnWidths))
+	}
+	row := func(cells ...string) string {
+		return tableCells(cells, replicationColumnWidths)
+	}
+	label := func(s string) string {
+		return styles.StatsLabelStyle.Render(s)
+	}
+
+	var lines []string
+	for i, n := range d.nodes {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		node := n.Node
+		if node == "" || a.redisOpts.MasterName != "" {
+			node = "current master"
+		}
+
+		if n.Role != "master" {
+			lines = append(lines,
+				styles.InfoSectionStyle.Render(fmt.Sprintf("%s (replica)", node)),
+				label("Master:")+n.MasterAddr,
+				label("Link status:")+linkStatus(n.LinkStatus),
+				label("Last I/O:")+redis.FormatSeconds(n.LastIOSeconds)+" ago",
+				label("Sync in progress:")+fmt.Sprintf("%t", n.SyncInProgress),
+			)
+			continue
+		}
+
+		lines = append(lines,
+			styles.InfoSectionStyle.Render(fmt.Sprintf("%s (master, offset %s, %d replicas)",
+				node, formatNumber(n.Offset), len(n.Replicas))))
+		if len(n.Replicas) == 0 {
+			lines = append(lines, styles.TableEmptyStyle.Render("No replicas connected"))
+			continue
+		}
+		lines = append(lines, header("Replica", "State", "Offset", "Behind", "Lag"))
+		for _, r := range n.Replicas {
+			line := row(r.Addr, r.State, formatNumber(r.Offset), redis.FormatBytes(r.Behind), redis.FormatSeconds(r.Lag))
+			if r.State != "online" {
+				line = styles.StatsErrorStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	if a.redisOpts.MasterName != "" {
+		lines = append(lines, "", "")
+		lines = append(lines, a.sentinelLines()...)
+	}
+
+	a.replicationViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// sentinelLines renders the masters monitored by the sentinels with their
+// replicas, sentinels, quorum and failover state
+func (a *App) sentinelLines() []string {
+	d := a.replicationData
+	if d.sentinelErr != nil {
+		return []string{styles.StatsErrorStyle.Render(fmt.Sprintf("Error querying sentinels: %v", d.sentinelErr))}
+	}
+	if d.sentinel == nil {
+		return nil
+	}
+
+	label := func(s string) string {
+		return styles.StatsLabelStyle.Render(s)
+	}
+	header := func(cells ...string) string {
+		return styles.TableHeaderStyle.Render(tableCells(cells, replicationColumnWidths))
+	}
+
+	lines := []string{styles.StatsTitleStyle.Copy().MarginBottom(0).Render(
+		fmt.Sprintf("Sentinel (as seen by %s)", d.sentinel.Sentinel))}
+
+	for _, m := range d.sentinel.Masters {
+		title := fmt.Sprintf("Master %s", m.Name)
+		if m.Name == a.redisOpts.MasterName {
+			title += " *"
+		}
+
+		state := "ok"
+		switch {
+		case m.FailoverInProgress():
+			state = styles.ChangedStyle.Render("failover in progress")
+		case m.Down():
+			state = styles.StatsErrorStyle.Render("down")
+		}
+
+		quorum := m.QuorumStatus
+		if !m.QuorumOK {
+			quorum = styles.StatsErrorStyle.Render(quorum)
+		}
+
+		lines = append(lines,
+			"",
+			styles.InfoSectionStyle.Render(title),
+			label("Address:")+m.Addr,
+			label("State:")+state,
+			label("Flags:")+m.Flags,
+			label("Quorum:")+fmt.Sprintf("%d of %d sentinels", m.Quorum, m.NumOtherSentinels+1),
+			label("Quorum check:")+quorum,
+			label("Config epoch:")+formatNumber(m.ConfigEpoch),
+			"",
+			header("Replica", "Link", "Offset", "Priority", "Flags"),
+		)
+		for _, r := range m.Replicas {
+			line := tableCells([]string{r.Addr, r.LinkStatus, formatNumber(r.Offset), formatNumber(r.Priority), r.Flags}, replicationColumnWidths)
+			if r.LinkStatus != "ok" || strings.Contains(r.Flags, "down") {
+				line = styles.StatsErrorStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+
+		lines = append(lines, "", header("Sentinel", "Last OK Ping", "Voted Leader", "", "Flags"))
+		for _, s := range m.Sentinels {
+			leader := s.VotedLeader
+			if leader == "?" {
+				leader = ""
+			}
+			line := tableCells([]string{s.Addr, fmt.Sprintf("%dms ago", s.LastOKPingMillis), leader, "", s.Flags}, replicationColumnWidths)
+			if strings.Contains(s.Flags, "down") {
+				line = styles.StatsErrorStyle.Render(line)
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+// linkStatus highlights a replication link that is down
+func linkStatus(status string) string {
+	if status != "up" {
+		return styles.StatsErrorStyle.Render(status)
+	}
+	return status
+}
+
+func (a App) replicationView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.replicationData == nil || a.replicationData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading replication status...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.replicationData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading replication status: %v", a.replicationData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render("Replication")
+	footer := styles.StatsFooterStyle.Render("↑/↓ scroll | r reload | a auto-refresh | ESC, q or R close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		a.replicationViewport.View(),
+		footer,
+	))
+}
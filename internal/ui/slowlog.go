@@ -0,0 +1,181 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/hawkins/redis-viewer/internal/ui/components/table"
+	"github.com/hawkins/redis-viewer/internal/ui/dialogs"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+// SlowLogData holds the slow log screen contents
+type SlowLogData struct {
+	entries    []redis.SlowLogEntry // in display order
+	loading    bool
+	refreshing bool
+	err        error
+}
+
+// slowLogDetailLines is the number of lines showing the selected command
+const slowLogDetailLines = 3
+
+func newSlowLogTable() table.Model {
+	return table.New([]table.Column{
+		{Title: "Time", Width: 20},
+		{Title: "Duration", Width: 12},
+		{Title: "Node", Width: 22},
+		{Title: "Client", Width: 22},
+		{Title: "Name", Width: 16},
+		{Title: "Command"},
+	})
+}
+
+func (a *App) openSlowLog() tea.Cmd {
+	a.state = StateSlowlog
+	a.slowLogData = &SlowLogData{loading: true}
+	a.slowLogTable.SetCursor(0)
+	return a.slowLogCmd()
+}
+
+func (a *App) handleSlowLogState(msg tea.Msg) tea.Cmd {
+	var (
+		cmd  tea.Cmd
+		cmds []tea.Cmd
+	)
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.slowLogTable, cmd = a.slowLogTable.Update(msg)
+		cmds = append(cmds, cmd)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "L":
+			a.state = StateDefault
+		case "r":
+			if a.slowLogData != nil && !a.slowLogData.loading {
+				a.slowLogData.refreshing = true
+			}
+			cmds = append(cmds, a.slowLogCmd())
+		case "a":
+			a.toggleAutoRefresh()
+		case "s":
+			a.slowLogByDuration = !a.slowLogByDuration
+			a.refreshSlowLogTable()
+			a.slowLogTable.SetCursor(0)
+		case "R":
+			if a.denyWrite("resetting the slow log") {
+				return nil
+			}
+			a.state = StateConfirmSlowlogReset
+			a.confirmDialog = dialogs.NewConfirmDialog(dialogs.ConfirmSlowlogReset, nil)
+			a.confirmDialog.SetCallbacks(
+				func() tea.Cmd {
+					a.state = StateSlowlog
+					return a.resetSlowLogCmd()
+				},
+				func() tea.Cmd {
+					a.state = StateSlowlog
+					return nil
+				},
+			)
+		default:
+			a.slowLogTable, cmd = a.slowLogTable.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	return tea.Batch(cmds...)
+}
+
+// refreshSlowLogTable sorts the entries and rebuilds the table rows
+func (a *App) refreshSlowLogTable() {
+	if a.slowLogData == nil {
+		a.slowLogTable.SetRows(nil)
+		return
+	}
+
+	entries := a.slowLogData.entries
+	if a.slowLogByDuration {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Duration > entries[j].Duration })
+	} else {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.After(entries[j].Time) })
+	}
+
+	rows := make([]table.Row, len(entries))
+	for i, e := range entries {
+		rows[i] = table.Row{
+			e.Time.Format("2006-01-02 15:04:05"),
+			e.Duration.String(),
+			e.Node,
+			e.ClientAddr,
+			e.ClientName,
+			formatCommandArgs(e.Args),
+		}
+	}
+	a.slowLogTable.SetRows(rows)
+}
+
+// formatCommandArgs joins command arguments, quoting those that need it
+func formatCommandArgs(args []string) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\r\n\"'") || !strconv.CanBackquote(arg) {
+			parts[i] = strconv.Quote(arg)
+		} else {
+			parts[i] = arg
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (a App) slowLogView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.slowLogData == nil || a.slowLogData.loading {
+		loadingMsg := styles.StatsLoadingStyle.Render(a.spinner.View() + " Loading slow log...")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, loadingMsg)
+	}
+
+	if a.slowLogData.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error loading slow log: %v", a.slowLogData.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	sortLabel := "time"
+	if a.slowLogByDuration {
+		sortLabel = "duration"
+	}
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(
+		fmt.Sprintf("Slow Log (%d entries, sorted by %s)", len(a.slowLogData.entries), sortLabel))
+
+	// Full command of the selected entry
+	var detail string
+	if cursor := a.slowLogTable.Cursor(); cursor < len(a.slowLogData.entries) {
+		wrapped := wordwrap.String(formatCommandArgs(a.slowLogData.entries[cursor].Args), a.width-4)
+		lines := strings.Split(wrapped, "\n")
+		if len(lines) > slowLogDetailLines {
+			lines = append(lines[:slowLogDetailLines-1], "…")
+		}
+		detail = strings.Join(lines, "\n")
+	}
+	detail = lipgloss.NewStyle().Height(slowLogDetailLines).Render(detail)
+
+	footer := styles.StatsFooterStyle.Render(
+		"↑/↓ select | s sort by time/duration | r reload | a auto-refresh | R reset slow log | ESC, q or L close")
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		a.slowLogTable.View(),
+		"",
+		detail,
+		footer,
+	))
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/snapshot"
+)
+
+// openSnapshot prompts for a snapshot file to save, or to compare with once
+// one was saved
+func (a *App) openSnapshot() tea.Cmd {
+	a.state = StateSnapshotInput
+	a.snapshotCompare = a.snapshotPath != ""
+	a.snapshotInput.SetValue(a.defaultSnapshotInput())
+	a.snapshotInput.CursorEnd()
+	return a.snapshotInput.Focus()
+}
+
+func (a *App) handleSnapshotInputState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEscape:
+			a.snapshotInput.Blur()
+			a.snapshotInput.Reset()
+			a.state = StateDefault
+			return nil
+		case tea.KeyTab:
+			a.snapshotCompare = !a.snapshotCompare
+			a.snapshotInput.SetValue(a.defaultSnapshotInput())
+			a.snapshotInput.CursorEnd()
+			return nil
+		case tea.KeyEnter:
+			fields := strings.Fields(a.snapshotInput.Value())
+			if len(fields) == 0 || len(fields) > 2 || (a.snapshotCompare && len(fields) > 1) {
+				a.statusMessage = "Enter a snapshot file, followed by an optional key pattern when saving"
+				return nil
+			}
+
+			a.snapshotInput.Blur()
+			a.snapshotInput.Reset()
+			path := fields[0]
+			a.snapshotPath = path
+			if a.snapshotCompare {
+				a.state = StateDiff
+				return a.startSnapshotDiff(path)
+			}
+
+			match := ""
+			if len(fields) == 2 {
+				match = fields[1]
+			}
+			a.state = StateDefault
+			a.statusMessage = fmt.Sprintf("Saving a snapshot to %s...", path)
+			return a.snapshotCmd(path, match)
+		}
+	}
+
+	a.snapshotInput, cmd = a.snapshotInput.Update(msg)
+	return cmd
+}
+
+// defaultSnapshotInput suggests the last snapshot to compare with, or a new
+// file name in the working directory to save to
+func (a App) defaultSnapshotInput() string {
+	if a.snapshotCompare {
+		return a.snapshotPath
+	}
+	return fmt.Sprintf("redis-viewer-db%d-%s%s", a.db, time.Now().Format("20060102-150405"), snapshot.Extension)
+}
+
+// snapshotDescription describes what the snapshot dialog will do
+func (a App) snapshotDescription() string {
+	if a.snapshotCompare {
+		return fmt.Sprintf("Compare DB %d with the snapshot (tab to save one instead)", a.db)
+	}
+	return fmt.Sprintf("Save the keys of DB %d, file then optional key pattern (tab to compare instead)", a.db)
+}
+
+// snapshotCmd saves the keys of match to a new snapshot file
+func (a App) snapshotCmd(path, match string) tea.Cmd {
+	rdb := a.rdb
+	header := snapshot.Header{Created: time.Now(), DB: a.db, Addrs: a.redisOpts.Addrs}
+	opts := redis.ScanStatsOptions{Match: match, BatchSize: a.analysisBatchSize, Pause: a.analysisPause}
+	return func() tea.Msg {
+		saved, err := snapshot.Save(context.Background(), rdb, expandHome(path), header, opts, nil)
+		return SnapshotMsg{Path: path, Match: match, Keys: saved, Err: err}
+	}
+}
@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hawkins/redis-viewer/internal/redis"
+	"github.com/hawkins/redis-viewer/internal/styles"
+)
+
+// TTLData holds the TTL distribution screen contents
+type TTLData struct {
+	job    *analysisJob
+	report *redis.TTLReport
+	err    error
+}
+
+// ttlBarWidth is the width of the longest histogram bar
+const ttlBarWidth = 30
+
+func (a *App) openTTLReport() tea.Cmd {
+	a.state = StateTTLReport
+	a.ttlViewport.GotoTop()
+	return a.startTTLReport()
+}
+
+// startTTLReport starts a new TTL analysis, cancelling any running one
+func (a *App) startTTLReport() tea.Cmd {
+	if a.ttlData != nil {
+		a.ttlData.job.stop()
+	}
+
+	rdb := a.rdb
+	opts := a.scanStatsOptions()
+	opts.Match = a.ttlPattern
+	if !a.ttlFull {
+		opts.Limit = a.analysisSampleSize
+	}
+
+	job, cmd := startAnalysisJob(func(ctx context.Context, progress func(int)) tea.Msg {
+		report, err := redis.AnalyseTTL(ctx, rdb, opts, progress)
+		return TTLReportMsg{Report: report, Err: err}
+	})
+	a.ttlData = &TTLData{job: job}
+	return cmd
+}
+
+func (a *App) handleTTLReportState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if a.ttlMatching {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.Type {
+			case tea.KeyEscape:
+				a.ttlMatching = false
+				a.ttlMatch.Blur()
+				return nil
+			case tea.KeyEnter:
+				a.ttlMatching = false
+				a.ttlMatch.Blur()
+				a.ttlPattern = strings.TrimSpace(a.ttlMatch.Value())
+				a.ttlViewport.GotoTop()
+				return a.startTTLReport()
+			}
+		}
+
+		a.ttlMatch, cmd = a.ttlMatch.Update(msg)
+		return cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		a.ttlViewport, cmd = a.ttlViewport.Update(msg)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q", "T":
+			// Leaving cancels a running analysis
+			if a.ttlData != nil && a.ttlData.report == nil {
+				a.ttlData.job.stop()
+				a.ttlData = nil
+			}
+			a.state = StateDefault
+		case "/":
+			a.ttlMatching = true
+			a.ttlMatch.SetValue(a.ttlPattern)
+			return a.ttlMatch.Focus()
+		case "f":
+			a.ttlFull = !a.ttlFull
+			cmd = a.startTTLReport()
+		case "r":
+			a.ttlViewport.GotoTop()
+			cmd = a.startTTLReport()
+		default:
+			a.ttlViewport, cmd = a.ttlViewport.Update(msg)
+		}
+	}
+
+	return cmd
+}
+
+// refreshTTLContent renders the report into the viewport
+func (a *App) refreshTTLContent() {
+	if a.ttlData == nil || a.ttlData.report == nil {
+		a.ttlViewport.SetContent("")
+		return
+	}
+	report := a.ttlData.report
+	widths := []int{16, 14, 10, 14}
+
+	header := func(cells ...string) string {
+		return styles.TableHeaderStyle.Render(tableCells(cells, widths))
+	}
+
+	lines := []string{
+		styles.InfoSectionStyle.Render("Coverage"),
+		fmt.Sprintf("%s %s keys (%s) | %s %s keys (%s), %s",
+			styles.StatsLabelStyle.Render("With TTL:"),
+			formatNumber(report.WithTTL()), formatPercent(report.WithTTL(), report.Scanned),
+			styles.StatsLabelStyle.Render("Without TTL:"),
+			formatNumber(report.WithoutTTL), formatPercent(report.WithoutTTL, report.Scanned),
+			redis.FormatBytes(report.NoTTLBytes)),
+		"",
+		styles.InfoSectionStyle.Render("Remaining TTL"),
+		header("TTL", "Keys", "% Keys", "Memory", ""),
+	}
+
+	var largest int64
+	for _, b := range report.Buckets {
+		if b.Keys > largest {
+			largest = b.Keys
+		}
+	}
+	for _, b := range report.Buckets {
+		bar := ""
+		if largest > 0 {
+			bar = styles.StatsChartStyle.Render(strings.Repeat("█", int(b.Keys*ttlBarWidth/largest)))
+		}
+		lines = append(lines, tableCells([]string{
+			b.Label,
+			formatNumber(b.Keys),
+			formatPercent(b.Keys, report.WithTTL()),
+			redis.FormatBytes(b.Bytes),
+			bar,
+		}, widths))
+	}
+
+	lines = append(lines, "", styles.InfoSectionStyle.Render("Expiry forecast"))
+	if report.Sampled() {
+		lines = append(lines,
+			header("Window", "Keys", "% Keys", "Memory", "Estimated for all keys"))
+	} else {
+		lines = append(lines, header("Window", "Keys", "% Keys", "Memory"))
+	}
+	for _, f := range report.Forecasts {
+		cells := []string{
+			f.Label,
+			formatNumber(f.Keys),
+			formatPercent(f.Keys, report.Scanned),
+			redis.FormatBytes(f.Bytes),
+		}
+		if report.Sampled() {
+			cells = append(cells, fmt.Sprintf("%s keys, %s",
+				formatNumber(report.Estimate(f.Keys)), redis.FormatBytes(report.Estimate(f.Bytes))))
+		}
+		lines = append(lines, tableCells(cells, widths))
+	}
+
+	a.ttlViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (a App) ttlReportView() string {
+	height := a.height - lipgloss.Height(a.statusView())
+
+	if a.ttlData == nil {
+		return ""
+	}
+	d := a.ttlData
+
+	if d.report == nil {
+		progress := styles.StatsLoadingStyle.Render(fmt.Sprintf(
+			"%s Analysing TTLs... %s done", a.spinner.View(), formatNumber(int64(d.job.scanned))))
+		hint := styles.StatsFooterStyle.Render("Press ESC to cancel")
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center,
+			lipgloss.JoinVertical(lipgloss.Center, progress, "", hint))
+	}
+
+	if d.err != nil {
+		errorMsg := styles.StatsErrorStyle.Render(fmt.Sprintf("Error analysing TTLs: %v", d.err))
+		return lipgloss.Place(a.width, height, lipgloss.Center, lipgloss.Center, errorMsg)
+	}
+
+	scope := formatNumber(d.report.Scanned) + " keys"
+	if d.report.Sampled() {
+		scope = fmt.Sprintf("sample of %s of %s keys", formatNumber(d.report.Scanned), formatNumber(d.report.TotalKeys))
+	}
+	title := styles.StatsTitleStyle.Copy().MarginBottom(0).Render(
+		fmt.Sprintf("TTL Distribution (DB %d, %s)", a.db, scope))
+
+	var match string
+	switch {
+	case a.ttlMatching:
+		match = a.ttlMatch.View()
+	case a.ttlPattern != "":
+		match = styles.StatsFooterStyle.Render("Match: " + a.ttlPattern)
+	}
+
+	scanToggle := "f full scan"
+	if a.ttlFull {
+		scanToggle = "f sampled scan"
+	}
+	footer := styles.StatsFooterStyle.Render(fmt.Sprintf(
+		"↑/↓ scroll | / match pattern | %s | r re-run | ESC, q or T close", scanToggle))
+
+	return lipgloss.NewStyle().Padding(0, 2).Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		match,
+		a.ttlViewport.View(),
+		footer,
+	))
+}
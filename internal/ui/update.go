@@ -1,8 +1,12 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -10,6 +14,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	redisv8 "github.com/go-redis/redis/v8"
 	"github.com/hawkins/redis-viewer/internal/constant"
+	"github.com/hawkins/redis-viewer/internal/logger"
+	"github.com/hawkins/redis-viewer/internal/redis"
 	"github.com/hawkins/redis-viewer/internal/styles"
 	"github.com/hawkins/redis-viewer/internal/ui/components/keylist"
 	"github.com/hawkins/redis-viewer/internal/ui/dialogs"
@@ -28,15 +34,227 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StatsMsg:
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to load stats: %v", msg.Err)
+			logger.Error("load stats failed", "err", msg.Err)
 			a.statsData = &StatsData{loading: false, err: msg.Err}
 		} else {
 			a.statsData = &StatsData{
 				serverStats: msg.ServerStats,
 				dbStats:     msg.DBStats,
+				databases:   msg.Databases,
 				loading:     false,
 				err:         nil,
 			}
 		}
+	case InfoMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to load INFO: %v", msg.Err)
+			logger.Error("load INFO failed", "err", msg.Err)
+			a.infoData = &InfoData{err: msg.Err}
+		} else {
+			a.infoData = &InfoData{
+				sections: msg.Sections,
+				previous: a.infoValues(),
+			}
+		}
+		a.refreshInfoContent()
+	case SlowLogMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to load slow log: %v", msg.Err)
+			logger.Error("load slow log failed", "err", msg.Err)
+			a.slowLogData = &SlowLogData{err: msg.Err}
+		} else {
+			a.slowLogData = &SlowLogData{entries: msg.Entries}
+		}
+		a.refreshSlowLogTable()
+	case SlowLogResetMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to reset slow log: %v", msg.Err)
+			logger.Error("reset slow log failed", "err", msg.Err)
+		} else {
+			a.statusMessage = "Slow log reset"
+			logger.Info("slow log reset")
+			cmds = append(cmds, a.slowLogCmd())
+		}
+	case ClientsMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to load clients: %v", msg.Err)
+			logger.Error("load clients failed", "err", msg.Err)
+			a.clientsData = &ClientsData{err: msg.Err}
+		} else {
+			a.clientsData = &ClientsData{all: msg.Clients}
+		}
+		a.refreshClientsTable()
+	case KillClientMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to kill client %s: %v", msg.Client.Addr, msg.Err)
+			logger.Error("kill client failed", "id", msg.Client.ID, "addr", msg.Client.Addr, "err", msg.Err)
+		} else {
+			a.statusMessage = fmt.Sprintf("Client %s killed", msg.Client.Addr)
+			logger.Info("client killed", "id", msg.Client.ID, "addr", msg.Client.Addr, "name", msg.Client.Name)
+			cmds = append(cmds, a.clientsCmd())
+		}
+	case ClusterMsg:
+		if msg.Err != nil && !errors.Is(msg.Err, redis.ErrNotCluster) {
+			a.statusMessage = fmt.Sprintf("Failed to load cluster topology: %v", msg.Err)
+			logger.Error("load cluster topology failed", "err", msg.Err)
+		}
+		a.clusterData = &ClusterData{topology: msg.Topology, err: msg.Err}
+		a.refreshClusterContent()
+	case ReplicationMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to load replication status: %v", msg.Err)
+			logger.Error("load replication status failed", "err", msg.Err)
+		}
+		if msg.SentinelErr != nil {
+			logger.Error("query sentinels failed", "err", msg.SentinelErr)
+		}
+		a.replicationData = &ReplicationData{
+			nodes:       msg.Nodes,
+			sentinel:    msg.Sentinel,
+			sentinelErr: msg.SentinelErr,
+			err:         msg.Err,
+		}
+		a.refreshReplicationContent()
+	case ConfigMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to load configuration: %v", msg.Err)
+			logger.Error("load configuration failed", "err", msg.Err)
+			a.configData = &ConfigData{err: msg.Err}
+		} else {
+			a.configData = &ConfigData{all: msg.Params}
+		}
+		a.refreshConfigTable()
+	case ConfigSetMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to set %s: %v", msg.Name, msg.Err)
+			logger.Error("config set failed", "name", msg.Name, "value", msg.Value, "err", msg.Err)
+		} else {
+			a.statusMessage = fmt.Sprintf("%s set to %q (was %q)", msg.Name, msg.Value, msg.Previous)
+			logger.Info("config set", "name", msg.Name, "value", msg.Value, "previous", msg.Previous)
+		}
+		cmds = append(cmds, a.configCmd())
+	case ConfigRewriteMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to rewrite the config file: %v", msg.Err)
+			logger.Error("config rewrite failed", "err", msg.Err)
+		} else {
+			a.statusMessage = "Config file rewritten"
+			logger.Info("config rewritten")
+		}
+	case ACLMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Failed to load ACLs: %v", msg.Err)
+			logger.Error("load ACLs failed", "err", msg.Err)
+		}
+		a.aclData = &ACLData{status: msg.Status, err: msg.Err}
+		a.refreshACLContent()
+	case ExportMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Export failed: %v", msg.Err)
+			logger.Error("export failed", "path", msg.Path, "format", msg.Format, "err", msg.Err)
+		} else {
+			a.statusMessage = fmt.Sprintf("Exported %d keys to %s", msg.Keys, msg.Path)
+			if msg.Skipped > 0 {
+				a.statusMessage += fmt.Sprintf(" (%d of an unsupported type skipped)", msg.Skipped)
+			}
+			logger.Info("export complete", "path", msg.Path, "format", msg.Format, "keys", msg.Keys, "skipped", msg.Skipped)
+		}
+	case SnapshotMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("Snapshot failed: %v", msg.Err)
+			logger.Error("snapshot failed", "path", msg.Path, "match", msg.Match, "err", msg.Err)
+		} else {
+			a.statusMessage = fmt.Sprintf("Saved %d keys to %s, press Z to compare with it", msg.Keys, msg.Path)
+			logger.Info("snapshot saved", "path", msg.Path, "match", msg.Match, "keys", msg.Keys)
+		}
+	case DiffMsg:
+		if a.diffData != nil && !errors.Is(msg.Err, context.Canceled) {
+			d := a.diffData
+			switch {
+			case d.snapshot != "" && msg.Err != nil:
+				logger.Error("snapshot diff failed", "path", d.snapshot, "err", msg.Err)
+			case d.snapshot != "":
+				logger.Info("snapshot diff complete", "path", d.snapshot, "unchanged", msg.Summary.Identical,
+					"modified", msg.Summary.Changed, "removed", msg.Summary.OnlyA, "added", msg.Summary.OnlyB)
+			case msg.Err != nil:
+				logger.Error("keyspace diff failed", "target", d.target, "match", d.match, "err", msg.Err)
+			default:
+				logger.Info("keyspace diff complete", "target", d.target, "match", d.match, "compared", msg.Summary.Compared,
+					"changed", msg.Summary.Changed, "only_a", msg.Summary.OnlyA, "only_b", msg.Summary.OnlyB)
+			}
+			d.done = true
+			d.diffs = msg.Diffs
+			d.summary = msg.Summary
+			d.truncated = msg.Truncated
+			d.header = msg.Snapshot
+			d.err = msg.Err
+			a.refreshDiffTable()
+		}
+	case CopyMsg:
+		if a.copyData != nil && !errors.Is(msg.Err, context.Canceled) {
+			d := a.copyData
+			for _, f := range msg.Stats.Failures {
+				logger.Warn("copy failed", "key", f.Key, "err", f.Err)
+			}
+			if msg.Err != nil {
+				logger.Error("copy failed", "target", d.target, "err", msg.Err)
+			} else {
+				logger.Info("copy complete", "target", d.target, "created", msg.Stats.Created, "replaced", msg.Stats.Replaced,
+					"renamed", msg.Stats.Renamed, "skipped", msg.Stats.Skipped, "failed", msg.Stats.Failed())
+			}
+			d.done = true
+			d.stats = msg.Stats
+			d.err = msg.Err
+			if msg.Err == nil && msg.Stats.Failed() == 0 {
+				a.keyList.ClearMarks()
+			}
+			if d.sameDB {
+				// Copies into the current database show up in the key list
+				cmds = append(cmds, a.scanCmd(), a.countCmd())
+			}
+		}
+	case AnalysisProgressMsg:
+		msg.Job.scanned = msg.Scanned
+		cmds = append(cmds, msg.Job.wait())
+	case BigKeysMsg:
+		if a.bigKeysData != nil && !errors.Is(msg.Err, context.Canceled) {
+			if msg.Err != nil {
+				logger.Error("big keys analysis failed", "err", msg.Err)
+			} else {
+				logger.Info("big keys analysis complete", "keys", msg.Report.Scanned)
+			}
+			a.bigKeysData.report = msg.Report
+			a.bigKeysData.err = msg.Err
+			a.refreshBigKeysContent()
+		}
+	case TTLReportMsg:
+		if a.ttlData != nil && !errors.Is(msg.Err, context.Canceled) {
+			if msg.Err != nil {
+				logger.Error("TTL analysis failed", "err", msg.Err)
+			} else {
+				logger.Info("TTL analysis complete", "keys", msg.Report.Scanned, "match", a.ttlPattern)
+			}
+			a.ttlData.report = msg.Report
+			a.ttlData.err = msg.Err
+			a.refreshTTLContent()
+		}
+	case PrefixesMsg:
+		if a.prefixesData != nil && !errors.Is(msg.Err, context.Canceled) {
+			if msg.Err != nil {
+				logger.Error("prefix analysis failed", "err", msg.Err)
+			} else {
+				logger.Info("prefix analysis complete", "keys", msg.Report.Root.Keys, "full", msg.Full)
+			}
+			a.prefixesData.report = msg.Report
+			a.prefixesData.full = msg.Full
+			a.prefixesData.err = msg.Err
+			a.refreshPrefixRows(0)
+		}
+	case MetricsSampleMsg:
+		a.metricsSampling = false
+		if msg.Err == nil && a.metrics != nil {
+			a.metrics.Add(msg.ServerStats, msg.At)
+		}
 	case EditKeyMsg:
 		if msg.Err != nil {
 			a.state = StateDefault
@@ -61,6 +279,7 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Err != nil {
 			a.state = StateDefault
 			a.statusMessage = fmt.Sprintf("Editor failed: %v", msg.Err)
+			logger.Error("editor failed", "err", msg.Err)
 			_ = os.Remove(msg.TmpFile)
 			a.editingKey = ""
 			a.editingTmpFile = ""
@@ -77,8 +296,10 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.state = StateDefault
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to update key: %v", msg.Err)
+			logger.Error("update key failed", "key", msg.Key, "err", msg.Err)
 		} else {
 			a.statusMessage = fmt.Sprintf("Key '%s' updated successfully", msg.Key)
+			logger.Info("key updated", "key", msg.Key)
 			a.ready = false
 			a.scanInProgress = true
 			a.scannedKeyCount = 0
@@ -88,8 +309,10 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.state = StateDefault
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to create key: %v", msg.Err)
+			logger.Error("create key failed", "key", msg.Key, "err", msg.Err)
 		} else {
 			a.statusMessage = fmt.Sprintf("Key '%s' created successfully", msg.Key)
+			logger.Info("key created", "key", msg.Key)
 			a.ready = false
 			a.scanInProgress = true
 			a.scannedKeyCount = 0
@@ -98,8 +321,10 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case DeleteMsg:
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to delete key: %v", msg.Err)
+			logger.Error("delete key failed", "key", msg.Key, "err", msg.Err)
 		} else {
 			a.statusMessage = fmt.Sprintf("Key '%s' deleted successfully", msg.Key)
+			logger.Info("key deleted", "key", msg.Key)
 			a.ready = false
 			a.scanInProgress = true
 			a.scannedKeyCount = 0
@@ -108,7 +333,9 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case SetTTLMsg:
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to set TTL: %v", msg.Err)
+			logger.Error("set TTL failed", "key", msg.Key, "ttl", msg.TTL, "err", msg.Err)
 		} else {
+			logger.Info("TTL set", "key", msg.Key, "ttl", msg.TTL)
 			if msg.TTL <= 0 {
 				a.statusMessage = fmt.Sprintf("TTL removed from key '%s' (now persistent)", msg.Key)
 			} else {
@@ -122,7 +349,9 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case PurgeMsg:
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to purge database: %v", msg.Err)
+			logger.Error("purge failed", "db", msg.DB, "err", msg.Err)
 		} else {
+			logger.Info("database purged", "db", msg.DB)
 			a.statusMessage = fmt.Sprintf("Database %d purged successfully", msg.DB)
 			a.ready = false
 			a.scanInProgress = true
@@ -135,7 +364,9 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.switchDBDialog.Reset()
 		if msg.Err != nil {
 			a.statusMessage = fmt.Sprintf("Failed to switch database: %v", msg.Err)
+			logger.Error("switch database failed", "db", msg.DB, "err", msg.Err)
 		} else {
+			logger.Info("switched database", "from", a.db, "to", msg.DB)
 			if a.rdb != nil {
 				_ = a.rdb.Close()
 			}
@@ -149,6 +380,10 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case ErrMsg:
 		a.statusMessage = msg.Err.Error()
+		if redis.IsNoPerm(msg.Err) {
+			a.statusMessage += " (press A to inspect ACLs)"
+		}
+		logger.Error("error", "err", msg.Err)
 	case tea.WindowSizeMsg:
 		a.width, a.height = msg.Width, msg.Height
 		statusBarHeight := lipgloss.Height(a.statusView())
@@ -160,22 +395,96 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		detailViewWidth := a.width - listViewWidth
 		a.valueView.SetSize(detailViewWidth, height)
+
+		// INFO browser: title, search and footer lines plus horizontal padding
+		a.infoViewport.Width = a.width - 4
+		a.infoViewport.Height = height - 3
+
+		// Slow log: title, blank line, command detail and footer around the table
+		a.slowLogTable.SetSize(a.width-4, height-3-slowLogDetailLines)
+
+		// Client list: title, filter and footer lines around the table
+		a.clientsTable.SetSize(a.width-4, height-3)
+
+		// Big keys report: title and footer lines
+		a.bigKeysViewport.Width = a.width - 4
+		a.bigKeysViewport.Height = height - 2
+
+		// Prefix breakdown: title, path and footer lines around the table
+		a.prefixesTable.SetSize(a.width-4, height-3)
+
+		// TTL report: title, match and footer lines
+		a.ttlViewport.Width = a.width - 4
+		a.ttlViewport.Height = height - 3
+
+		// Cluster topology: title and footer lines
+		a.clusterViewport.Width = a.width - 4
+		a.clusterViewport.Height = height - 2
+
+		// Replication status: title and footer lines
+		a.replicationViewport.Width = a.width - 4
+		a.replicationViewport.Height = height - 2
+
+		// CONFIG browser: title, filter or edit and footer lines around the table
+		a.configTable.SetSize(a.width-4, height-3)
+
+		// ACL screen: title and footer lines
+		a.aclViewport.Width = a.width - 4
+		a.aclViewport.Height = height - 2
+
+		// Keyspace diff: title, summary and footer lines around the table,
+		// title, header and footer lines around the value diff
+		a.diffTable.SetSize(a.width-4, height-3)
+		a.diffViewport.Width = a.width - 4
+		a.diffViewport.Height = height - 3
+
+		// Copy progress bar
+		a.copyProgress.Width = a.width - 8
+		if a.copyProgress.Width > 80 {
+			a.copyProgress.Width = 80
+		}
 		content := a.valueView.FormatContent(a.getCurrentItem())
 		a.valueView.SetContent(content)
 	case TickMsg:
 		a.now = msg.T
-		cmds = append(cmds, a.tickCmd())
+		cmds = append(cmds, a.tickCmd(), a.autoRefreshCmd())
+		if a.state == StateStats && !a.metricsSampling {
+			a.metricsSampling = true
+			cmds = append(cmds, a.sampleMetricsCmd())
+		}
 	case LoadValueMsg:
 		items := a.keyList.Items()
 		for i, listItem := range items {
 			if it, ok := listItem.(keylist.Item); ok && it.Key == msg.Key {
+				if msg.Refreshed {
+					// Highlight lines that changed since the previous load
+					if it.Loaded && it.Val != msg.Val {
+						a.valueView.SetPrevious(it.Key, it.Val)
+					} else {
+						a.valueView.ClearPrevious()
+					}
+				}
+				meta := msg.Meta
+				if meta != nil && it.Meta != nil && it.Val == msg.Val {
+					// Keep the serialized length loaded on demand while the value is unchanged
+					kept := *meta
+					kept.SerializedLength = it.Meta.SerializedLength
+					kept.SerializedErr = it.Meta.SerializedErr
+					meta = &kept
+				}
+				if meta != nil && a.dumpDenied {
+					meta.Dumpable = false
+				}
 				items[i] = keylist.Item{
-					KeyType:    it.KeyType,
+					KeyType:    msg.KeyType,
 					Key:        it.Key,
 					Val:        msg.Val,
 					Err:        msg.Err != nil,
-					TTLSeconds: it.TTLSeconds,
+					TTLSeconds: msg.TTLSeconds,
+					Meta:       meta,
+					ShowSize:   a.listShowSize,
 					Loaded:     true,
+					Marked:     it.Marked,
 				}
 				a.keyList.SetItems(items)
 				content := a.valueView.FormatContent(items[i].(keylist.Item))
@@ -183,6 +492,31 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				break
 			}
 		}
+	case SerializedLengthMsg:
+		if msg.Err != nil {
+			a.statusMessage = fmt.Sprintf("DUMP failed: %v", msg.Err)
+			logger.Warn("serialized length failed", "key", msg.Key, "err", msg.Err)
+			// Without the permission, no other key can be dumped either
+			a.dumpDenied = a.dumpDenied || redis.IsNoPerm(msg.Err)
+		}
+		items := a.keyList.Items()
+		for i, listItem := range items {
+			if it, ok := listItem.(keylist.Item); ok && it.Key == msg.Key && it.Meta != nil {
+				meta := *it.Meta
+				if msg.Err != nil {
+					meta.SerializedErr = msg.Err.Error()
+				} else {
+					meta.SerializedLength = msg.Length
+				}
+				it.Meta = &meta
+				items[i] = it
+				a.keyList.SetItems(items)
+				if a.getCurrentItem().Key == msg.Key {
+					a.valueView.SetContent(a.valueView.FormatContent(it))
+				}
+				break
+			}
+		}
 	case ScanMsg:
 		a.scannedKeyCount = msg.TotalScanned
 		a.scanInProgress = !msg.IsComplete
@@ -269,9 +603,22 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StateCreateKeyInput:
 		cmd = a.handleCreateKeyInputState(msg)
 		cmds = append(cmds, cmd)
+	case StateExportInput:
+		cmd = a.handleExportInputState(msg)
+		cmds = append(cmds, cmd)
+	case StateDiffInput:
+		cmd = a.handleDiffInputState(msg)
+		cmds = append(cmds, cmd)
+	case StateCopyInput:
+		cmd = a.handleCopyInputState(msg)
+		cmds = append(cmds, cmd)
+	case StateSnapshotInput:
+		cmd = a.handleSnapshotInputState(msg)
+		cmds = append(cmds, cmd)
 	case StateEditingKey:
 		// Non-interactive state
-	case StateConfirmDelete, StateConfirmPurge:
+	case StateConfirmDelete, StateConfirmPurge, StateConfirmSlowlogReset, StateConfirmClientKill,
+		StateConfirmConfigSet, StateConfirmConfigRewrite:
 		a.confirmDialog, cmd = a.confirmDialog.Update(msg)
 		cmds = append(cmds, cmd)
 	case StateHelp:
@@ -280,6 +627,45 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case StateStats:
 		cmd = a.handleStatsState(msg)
 		cmds = append(cmds, cmd)
+	case StateInfo:
+		cmd = a.handleInfoState(msg)
+		cmds = append(cmds, cmd)
+	case StateSlowlog:
+		cmd = a.handleSlowLogState(msg)
+		cmds = append(cmds, cmd)
+	case StateClients:
+		cmd = a.handleClientsState(msg)
+		cmds = append(cmds, cmd)
+	case StateBigKeys:
+		cmd = a.handleBigKeysState(msg)
+		cmds = append(cmds, cmd)
+	case StatePrefixes:
+		cmd = a.handlePrefixesState(msg)
+		cmds = append(cmds, cmd)
+	case StateTTLReport:
+		cmd = a.handleTTLReportState(msg)
+		cmds = append(cmds, cmd)
+	case StateCluster:
+		cmd = a.handleClusterState(msg)
+		cmds = append(cmds, cmd)
+	case StateKeyScope:
+		cmd = a.handleKeyScopeState(msg)
+		cmds = append(cmds, cmd)
+	case StateReplication:
+		cmd = a.handleReplicationState(msg)
+		cmds = append(cmds, cmd)
+	case StateConfig:
+		cmd = a.handleConfigState(msg)
+		cmds = append(cmds, cmd)
+	case StateACL:
+		cmd = a.handleACLState(msg)
+		cmds = append(cmds, cmd)
+	case StateDiff:
+		cmd = a.handleDiffState(msg)
+		cmds = append(cmds, cmd)
+	case StateCopy:
+		cmd = a.handleCopyState(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	a.spinner, cmd = a.spinner.Update(msg)
@@ -361,6 +747,9 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 				)
 				return a.switchDBDialog.Focus()
 			case key.Matches(msg, a.keyMap.SetTTL):
+				if a.denyWrite("setting TTL") {
+					return nil
+				}
 				if selectedItem := a.keyList.SelectedItem(); selectedItem != nil {
 					if i, ok := selectedItem.(keylist.Item); ok {
 						a.keyToSetTTL = i.Key
@@ -374,6 +763,9 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 				a.scannedKeyCount = 0
 				return tea.Batch(a.scanCmd(), a.countCmd())
 			case key.Matches(msg, a.keyMap.Delete):
+				if a.denyWrite("deleting keys") {
+					return nil
+				}
 				if selectedItem := a.keyList.SelectedItem(); selectedItem != nil {
 					if i, ok := selectedItem.(keylist.Item); ok {
 						a.keyToDelete = i.Key
@@ -393,6 +785,9 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 					}
 				}
 			case key.Matches(msg, a.keyMap.Purge):
+				if a.denyWrite("purging the database") {
+					return nil
+				}
 				a.state = StateConfirmPurge
 				a.confirmDialog = dialogs.NewConfirmDialog(dialogs.ConfirmPurge, a.db)
 				a.confirmDialog.SetCallbacks(
@@ -414,13 +809,21 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 				}
 				content := a.valueView.FormatContent(a.getCurrentItem())
 				a.valueView.SetContent(content)
+			case key.Matches(msg, a.keyMap.Serialized):
+				if it := a.getCurrentItem(); it.Meta != nil && it.Meta.Dumpable && !a.dumpDenied {
+					return a.serializedLengthCmd(it.Key)
+				}
 			case key.Matches(msg, a.keyMap.Help):
 				a.state = StateHelp
 			case key.Matches(msg, a.keyMap.Stats):
 				a.state = StateStats
 				a.statsData = &StatsData{loading: true}
+				a.metrics = NewMetricsHistory(a.metricsWindow)
 				return a.statsCmd()
 			case key.Matches(msg, a.keyMap.Edit):
+				if a.denyWrite("editing keys") {
+					return nil
+				}
 				if selectedItem := a.keyList.SelectedItem(); selectedItem != nil {
 					if i, ok := selectedItem.(keylist.Item); ok {
 						a.state = StateEditingKey
@@ -429,8 +832,49 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 					}
 				}
 			case key.Matches(msg, a.keyMap.Create):
+				if a.denyWrite("creating keys") {
+					return nil
+				}
 				a.state = StateCreateKeyInput
 				return a.createKeyInput.Focus()
+			case key.Matches(msg, a.keyMap.Export):
+				return a.openExport()
+			case key.Matches(msg, a.keyMap.Diff):
+				return a.openDiff()
+			case key.Matches(msg, a.keyMap.Copy):
+				return a.openCopy()
+			case key.Matches(msg, a.keyMap.Snapshot):
+				return a.openSnapshot()
+			case key.Matches(msg, a.keyMap.AutoRefresh):
+				a.toggleAutoRefresh()
+			case key.Matches(msg, a.keyMap.Info):
+				return a.openInfo()
+			case key.Matches(msg, a.keyMap.SlowLog):
+				return a.openSlowLog()
+			case key.Matches(msg, a.keyMap.Clients):
+				return a.openClients()
+			case key.Matches(msg, a.keyMap.BigKeys):
+				return a.openBigKeys()
+			case key.Matches(msg, a.keyMap.Prefixes):
+				return a.openPrefixes()
+			case key.Matches(msg, a.keyMap.TTLReport):
+				return a.openTTLReport()
+			case key.Matches(msg, a.keyMap.Cluster):
+				return a.openCluster()
+			case key.Matches(msg, a.keyMap.Replication):
+				return a.openReplication()
+			case key.Matches(msg, a.keyMap.Config):
+				return a.openConfig()
+			case key.Matches(msg, a.keyMap.ACL):
+				return a.openACL()
+			case key.Matches(msg, a.keyMap.KeyScope):
+				if _, ok := a.rdb.(*redisv8.ClusterClient); !ok {
+					a.statusMessage = "Key scopes need a cluster connection"
+					return nil
+				}
+				a.state = StateKeyScope
+				a.keyScopeInput.SetValue(scopeInputValue(a.keyScope))
+				return a.keyScopeInput.Focus()
 			}
 		case tea.KeyCtrlC:
 			return tea.Quit
@@ -458,21 +902,19 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 		case tea.KeyUp, tea.KeyDown:
 			if a.focused == PaneList {
 				a.keyList, cmd = a.keyList.Update(msg)
-				cmds = append(cmds, cmd)
-
-				if selectedItem := a.keyList.SelectedItem(); selectedItem != nil {
-					if it, ok := selectedItem.(keylist.Item); ok && !it.Loaded {
-						cmds = append(cmds, a.loadValueCmd(it.Key, it.KeyType, it.TTLSeconds))
-					}
-				}
-
-				a.valueView.GotoTop()
-				content := a.valueView.FormatContent(a.getCurrentItem())
-				a.valueView.SetContent(content)
+				cmds = append(cmds, cmd, a.showSelectedItem())
 			} else {
 				a.valueView, cmd = a.valueView.Update(msg)
 				cmds = append(cmds, cmd)
 			}
+		case tea.KeySpace:
+			// Mark the key for copy and move on to the next one
+			if a.focused == PaneList && a.keyList.SelectedItem() != nil {
+				a.keyList.ToggleMark()
+				a.keyList.CursorDown()
+				a.statusMessage = fmt.Sprintf("%d keys marked (c to copy)", len(a.keyList.MarkedKeys()))
+				cmds = append(cmds, a.showSelectedItem())
+			}
 		}
 	default:
 		a.keyList, cmd = a.keyList.Update(msg)
@@ -485,6 +927,23 @@ func (a *App) handleDefaultState(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// showSelectedItem shows the value of the selected key, loading it when needed
+func (a *App) showSelectedItem() tea.Cmd {
+	var cmd tea.Cmd
+	a.valueView.ClearPrevious()
+
+	if selectedItem := a.keyList.SelectedItem(); selectedItem != nil {
+		if it, ok := selectedItem.(keylist.Item); ok && !it.Loaded {
+			cmd = a.loadValueCmd(it.Key, it.KeyType, it.TTLSeconds)
+		}
+	}
+
+	a.valueView.GotoTop()
+	content := a.valueView.FormatContent(a.getCurrentItem())
+	a.valueView.SetContent(content)
+	return cmd
+}
+
 func (a *App) handleSetTTLState(msg tea.Msg) tea.Cmd {
 	var (
 		cmd  tea.Cmd
@@ -572,6 +1031,53 @@ func (a *App) handleCreateKeyInputState(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+func (a *App) handleKeyScopeState(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.Type {
+		case tea.KeyEscape:
+			a.keyScopeInput.Blur()
+			a.keyScopeInput.Reset()
+			a.state = StateDefault
+			return nil
+		case tea.KeyEnter:
+			scope, err := redis.ParseKeyScope(a.keyScopeInput.Value())
+			if err != nil {
+				a.statusMessage = fmt.Sprintf("Invalid key scope: %v", err)
+				return nil
+			}
+
+			a.keyScopeInput.Blur()
+			a.keyScopeInput.Reset()
+			a.state = StateDefault
+			a.keyScope = scope
+			a.statusMessage = fmt.Sprintf("Key scope: %s", scope)
+			logger.Info("key scope changed", "scope", scope.String())
+
+			a.ready = false
+			a.scanInProgress = true
+			a.scannedKeyCount = 0
+			return tea.Batch(a.scanCmd(), a.countCmd())
+		}
+	}
+
+	a.keyScopeInput, cmd = a.keyScopeInput.Update(msg)
+	return cmd
+}
+
+// scopeInputValue renders a key scope in the syntax ParseKeyScope accepts
+func scopeInputValue(scope redis.KeyScope) string {
+	var fields []string
+	if scope.Node != "" {
+		fields = append(fields, scope.Node)
+	}
+	if scope.Slots != nil {
+		fields = append(fields, scope.Slots.String())
+	}
+	return strings.Join(fields, " ")
+}
+
 func (a *App) handleHelpState(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
 
@@ -597,12 +1103,69 @@ func (a *App) handleStatsState(msg tea.Msg) tea.Cmd {
 		case "r":
 			a.statsData = &StatsData{loading: true}
 			cmds = append(cmds, a.statsCmd())
+		case "a":
+			a.toggleAutoRefresh()
+		case "I":
+			cmds = append(cmds, a.openInfo())
+		case "L":
+			cmds = append(cmds, a.openSlowLog())
+		case "C":
+			cmds = append(cmds, a.openClients())
+		case "B":
+			cmds = append(cmds, a.openBigKeys())
+		case "M":
+			cmds = append(cmds, a.openPrefixes())
+		case "T":
+			cmds = append(cmds, a.openTTLReport())
+		case "N":
+			cmds = append(cmds, a.openCluster())
+		case "R":
+			cmds = append(cmds, a.openReplication())
+		case "O":
+			cmds = append(cmds, a.openConfig())
+		case "A":
+			cmds = append(cmds, a.openACL())
+		case "s":
+			a.statsDetail = !a.statsDetail
+			a.statsData = &StatsData{loading: true}
+			cmds = append(cmds, a.statsCmd())
 		}
 	}
 
 	return tea.Batch(cmds...)
 }
 
+func (a *App) openInfo() tea.Cmd {
+	a.state = StateInfo
+	a.infoData = &InfoData{loading: true}
+	a.infoFilter = ""
+	a.infoSearch.Reset()
+	a.infoViewport.GotoTop()
+	return a.infoCmd()
+}
+
+// denyWrite reports whether write actions are disabled, explaining why in the status bar
+func (a *App) denyWrite(action string) bool {
+	if !a.readOnly {
+		return false
+	}
+	a.statusMessage = fmt.Sprintf("Read-only mode: %s is disabled", action)
+	return true
+}
+
+func (a *App) toggleAutoRefresh() {
+	a.autoRefresh = !a.autoRefresh
+	a.lastRefresh = time.Now()
+	if a.autoRefresh {
+		a.statusMessage = fmt.Sprintf("Auto-refresh enabled (every %s)", a.refreshInterval)
+	} else {
+		a.valueView.ClearPrevious()
+		a.statusMessage = "Auto-refresh disabled"
+	}
+	content := a.valueView.FormatContent(a.getCurrentItem())
+	a.valueView.SetContent(content)
+}
+
 func (a App) getCurrentItem() keylist.Item {
 	if selectedItem := a.keyList.SelectedItem(); selectedItem != nil {
 		if it, ok := selectedItem.(keylist.Item); ok {
@@ -2,11 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hawkins/redis-viewer/internal/redis"
 	"github.com/hawkins/redis-viewer/internal/styles"
+	"github.com/hawkins/redis-viewer/internal/ui/components/chart"
 )
 
 // View renders the application
@@ -16,6 +18,30 @@ func (a App) View() string {
 	// Show stats page, help dialog, or main content
 	if a.state == StateStats {
 		content = a.statsView()
+	} else if a.state == StateInfo {
+		content = a.infoView()
+	} else if a.state == StateSlowlog || a.state == StateConfirmSlowlogReset {
+		content = a.slowLogView()
+	} else if a.state == StateClients || a.state == StateConfirmClientKill {
+		content = a.clientsView()
+	} else if a.state == StateBigKeys {
+		content = a.bigKeysView()
+	} else if a.state == StatePrefixes {
+		content = a.prefixesView()
+	} else if a.state == StateTTLReport {
+		content = a.ttlReportView()
+	} else if a.state == StateCluster {
+		content = a.clusterView()
+	} else if a.state == StateReplication {
+		content = a.replicationView()
+	} else if a.state == StateConfig || a.state == StateConfirmConfigSet || a.state == StateConfirmConfigRewrite {
+		content = a.configView()
+	} else if a.state == StateACL {
+		content = a.aclView()
+	} else if a.state == StateDiff {
+		content = a.diffView()
+	} else if a.state == StateCopy {
+		content = a.copyView()
 	} else if a.state == StateHelp {
 		content = a.helpView()
 	} else {
@@ -40,9 +66,27 @@ func (a App) helpView() string {
 		"  d         Switch database",
 		"  t         Set TTL for selected key",
 		"  w         Toggle word wrap",
+		"  z         Load the serialized (DUMP) size of the selected key",
+		"  a         Toggle auto-refresh of the selected key",
 		"  i         View server statistics",
+		"  I         Browse full INFO output",
+		"  L         View the slow log",
+		"  C         Inspect connected clients",
+		"  B         Analyse big keys and memory usage",
+		"  M         Break down memory and keys by prefix",
+		"  T         Analyse the TTL distribution and expiries",
+		"  N         View the cluster topology",
+		"  R         View replication and sentinel status",
+		"  O         Browse and edit the server configuration",
+		"  A         Inspect ACL users and denied commands",
+		"  S         Restrict keys to a cluster node or slot range",
 		"  e         Edit selected key in $EDITOR",
 		"  n         Create new key in $EDITOR",
+		"  E         Export the selected key or listed keys to a file",
+		"  D         Compare keys with another database or profile",
+		"  Space     Mark the selected key",
+		"  c         Copy the marked or selected keys to another database or profile",
+		"  Z         Save a snapshot of keys or compare live keys with one",
 		"  x         Delete selected key",
 		"  P         Purge database (delete all keys)",
 		"  ?         Toggle this help",
@@ -74,6 +118,21 @@ func (a App) statusView() string {
 	// Pre-render fixed elements to get their widths
 	var statusKey, encoding, wrapIndicator, datetime string
 
+	// Read-only and auto-refresh indicators
+	var modeIndicator string
+	if a.autoRefresh {
+		modeIndicator = styles.AutoRefreshIndicatorStyle.Render(fmt.Sprintf("AUTO %s", a.refreshInterval))
+	}
+	if a.redisOpts.ReadsFromReplicas() {
+		modeIndicator = styles.ReplicaIndicatorStyle.Render("REPLICAS") + modeIndicator
+	}
+	if a.readOnly {
+		modeIndicator = styles.ReadOnlyIndicatorStyle.Render("READ-ONLY") + modeIndicator
+	}
+	if a.redisOpts.RDBFile != "" {
+		modeIndicator = styles.RDBIndicatorStyle.Render("RDB "+filepath.Base(a.redisOpts.RDBFile)) + modeIndicator
+	}
+
 	switch a.state {
 	case StateFuzzySearch:
 		if a.fuzzyStrict {
@@ -91,6 +150,21 @@ func (a App) statusView() string {
 	case StateCreateKeyInput:
 		status = "Create"
 		statusDesc = a.createKeyInput.View()
+	case StateExportInput:
+		status = "Export"
+		statusDesc = a.exportDescription() + " " + a.exportInput.View()
+	case StateDiffInput:
+		status = "Diff"
+		statusDesc = fmt.Sprintf("Compare DB %d with %s", a.db, a.diffInput.View())
+	case StateCopyInput:
+		status = "Copy"
+		statusDesc = a.copyDescription() + " " + a.copyInput.View()
+	case StateSnapshotInput:
+		status = "Snapshot"
+		statusDesc = a.snapshotDescription() + " " + a.snapshotInput.View()
+	case StateKeyScope:
+		status = "Scope"
+		statusDesc = a.keyScopeInput.View()
 	case StateEditingKey:
 		status = "Editor"
 		statusDesc = a.statusMessage
@@ -104,7 +178,7 @@ func (a App) statusView() string {
 		datetime = styles.DatetimeStyle.Render(a.now)
 
 		// Calculate available width for the confirmation message
-		fixedWidth := lipgloss.Width(statusKey) + lipgloss.Width(encoding) + lipgloss.Width(wrapIndicator) + lipgloss.Width(datetime)
+		fixedWidth := lipgloss.Width(statusKey) + lipgloss.Width(encoding) + lipgloss.Width(wrapIndicator) + lipgloss.Width(modeIndicator) + lipgloss.Width(datetime)
 		availableWidth := a.width - fixedWidth
 
 		// Account for the message template
@@ -129,6 +203,22 @@ func (a App) statusView() string {
 		}
 		datetime = styles.DatetimeStyle.Render(a.now)
 		statusDesc = fmt.Sprintf("PURGE ALL KEYS in database %d? (y/n)", a.db)
+	case StateConfirmSlowlogReset:
+		status = "Confirm"
+		statusDesc = "Reset the slow log on all nodes? (y/n)"
+	case StateConfirmClientKill:
+		status = "Confirm"
+		if client, ok := a.confirmDialog.Data().(redis.ClientInfo); ok {
+			statusDesc = fmt.Sprintf("Kill client %d (%s %s)? (y/n)", client.ID, client.Addr, client.Name)
+		}
+	case StateConfirmConfigSet:
+		status = "Confirm"
+		if change, ok := a.confirmDialog.Data().(configChange); ok {
+			statusDesc = fmt.Sprintf("CONFIG SET %s from %q to %q? (y/n)", change.name, change.previous, change.value)
+		}
+	case StateConfirmConfigRewrite:
+		status = "Confirm"
+		statusDesc = "Rewrite the config file with the running configuration? (y/n)"
 	default:
 		status = "Ready"
 		statusDesc = a.statusMessage
@@ -150,6 +240,10 @@ func (a App) statusView() string {
 				statusDesc = fmt.Sprintf("[%s: %s]", modeLabel, a.fuzzyFilter)
 			}
 		}
+		// Show the cluster key scope in status
+		if !a.keyScope.IsZero() {
+			statusDesc = strings.TrimSpace(fmt.Sprintf("[Scope: %s] %s", a.keyScope, statusDesc))
+		}
 	}
 
 	// Render fixed elements if not already done
@@ -167,7 +261,7 @@ func (a App) statusView() string {
 	}
 
 	// Calculate available width for status description
-	availableWidth := a.width - lipgloss.Width(statusKey) - lipgloss.Width(encoding) - lipgloss.Width(wrapIndicator) - lipgloss.Width(datetime)
+	availableWidth := a.width - lipgloss.Width(statusKey) - lipgloss.Width(encoding) - lipgloss.Width(wrapIndicator) - lipgloss.Width(modeIndicator) - lipgloss.Width(datetime)
 	if availableWidth < 0 {
 		availableWidth = 0
 	}
@@ -176,7 +270,7 @@ func (a App) statusView() string {
 		Width(availableWidth).
 		Render(statusDesc)
 
-	bar := lipgloss.JoinHorizontal(lipgloss.Top, statusKey, statusVal, encoding, wrapIndicator, datetime)
+	bar := lipgloss.JoinHorizontal(lipgloss.Top, statusKey, statusVal, encoding, wrapIndicator, modeIndicator, datetime)
 
 	return styles.StatusBarStyle.Width(a.width).Render(bar)
 }
@@ -248,18 +342,53 @@ func (a App) statsView() string {
 		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, memoryInfo...))
 	}
 
+	// Metrics History Section
+	if a.metrics != nil && a.metrics.OpsPerSec.Len() > 0 {
+		sections = append(sections, styles.StatsSectionStyle.Render(
+			fmt.Sprintf("Metrics History (last %ds)", a.metrics.OpsPerSec.Size())))
+
+		chartWidth := a.metrics.OpsPerSec.Size()
+		if maxWidth := a.width - 70; chartWidth > maxWidth {
+			chartWidth = maxWidth
+		}
+		if chartWidth < 10 {
+			chartWidth = 10
+		}
+
+		formatCount := func(v float64) string { return formatNumber(int64(v)) }
+		formatMemory := func(v float64) string { return redis.FormatBytes(int64(v)) }
+		formatPercent := func(v float64) string { return fmt.Sprintf("%.1f%%", v) }
+		formatRate := func(v float64) string { return fmt.Sprintf("%.1f/s", v) }
+
+		metricsInfo := []string{
+			metricChartRow("Ops/sec:", a.metrics.OpsPerSec, chartWidth, formatCount),
+			metricChartRow("Used Memory:", a.metrics.UsedMemory, chartWidth, formatMemory),
+			metricChartRow("Connected Clients:", a.metrics.Clients, chartWidth, formatCount),
+			metricChartRow("Hit Ratio:", a.metrics.HitRatio, chartWidth, formatPercent),
+			metricChartRow("Evictions:", a.metrics.Evictions, chartWidth, formatRate),
+		}
+		sections = append(sections, lipgloss.JoinVertical(lipgloss.Left, metricsInfo...))
+	}
+
 	// Database Section
 	if dbStats, ok := a.statsData.dbStats.([]*redis.DatabaseStats); ok && len(dbStats) > 0 {
-		sections = append(sections, styles.StatsSectionStyle.Render("Database Statistics"))
+		title := "Database Statistics"
+		if a.statsData.databases > 0 {
+			title = fmt.Sprintf("Database Statistics (%d of %d databases in use)", len(dbStats), a.statsData.databases)
+		}
+		sections = append(sections, styles.StatsSectionStyle.Render(title))
 
 		// Table header
-		tableHeader := lipgloss.JoinHorizontal(
-			lipgloss.Left,
+		headers := []string{
 			styles.StatsHeaderStyle.Copy().Width(10).Render("Database"),
 			styles.StatsHeaderStyle.Copy().Width(15).Render("Keys"),
+			styles.StatsHeaderStyle.Copy().Width(15).Render("Expires"),
 			styles.StatsHeaderStyle.Copy().Width(20).Render("Avg TTL"),
-		)
-		sections = append(sections, tableHeader)
+		}
+		if a.statsDetail {
+			headers = append(headers, styles.StatsHeaderStyle.Copy().Width(25).Render("Sampled TTL"))
+		}
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Left, headers...))
 
 		// Table rows
 		for _, db := range dbStats {
@@ -268,19 +397,32 @@ func (a App) statsView() string {
 				avgTTL = "No TTL"
 			}
 
-			row := lipgloss.JoinHorizontal(
-				lipgloss.Left,
-				styles.StatsRowStyle.Copy().Width(10).Render(fmt.Sprintf("DB %d", db.DB)),
+			name := fmt.Sprintf("DB %d", db.DB)
+			if db.DB == a.db {
+				name += " *"
+			}
+
+			cells := []string{
+				styles.StatsRowStyle.Copy().Width(10).Render(name),
 				styles.StatsRowStyle.Copy().Width(15).Render(formatNumber(db.Keys)),
+				styles.StatsRowStyle.Copy().Width(15).Render(formatNumber(db.Expires)),
 				styles.StatsRowStyle.Copy().Width(20).Render(avgTTL),
-			)
-			sections = append(sections, row)
+			}
+			if a.statsDetail {
+				sampled := "-"
+				if db.SampleSize > 0 {
+					sampled = fmt.Sprintf("%s (%d keys)", db.SampledTTL, db.SampleSize)
+				}
+				cells = append(cells, styles.StatsRowStyle.Copy().Width(25).Render(sampled))
+			}
+			sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Left, cells...))
 		}
 	}
 
 	// Footer
 	sections = append(sections, "")
-	sections = append(sections, styles.StatsFooterStyle.Render("Press 'i', 'q', or ESC to close | Press 'r' to reload"))
+	sections = append(sections, styles.StatsFooterStyle.Render("Press 'i', 'q', or ESC to close | Press 'r' to reload | Press 'a' to toggle auto-refresh | Press 's' to sample TTLs"))
+	sections = append(sections, styles.StatsFooterStyle.Render(statsScreensHelp))
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
 
@@ -294,6 +436,17 @@ func (a App) statsView() string {
 	)
 }
 
+// metricChartRow renders a labelled sparkline with the current, min and max values
+func metricChartRow(label string, series *chart.Series, width int, format func(float64) string) string {
+	return styles.StatsLabelStyle.Render(label) +
+		styles.StatsChartStyle.Render(chart.Sparkline(series.Values(), width)) +
+		styles.StatsValueStyle.Render(" "+format(series.Last())) +
+		styles.StatsFooterStyle.Render(fmt.Sprintf("  min %s  max %s", format(series.Min()), format(series.Max())))
+}
+
+// statsScreensHelp lists the screens reachable from the stats page
+const statsScreensHelp = "More: 'I' full INFO | 'L' slow log | 'C' clients | 'B' big keys | 'M' prefixes | 'T' TTLs | 'N' cluster | 'R' replication | 'O' config | 'A' ACLs"
+
 func formatUptime(seconds int64) string {
 	days := seconds / 86400
 	hours := (seconds % 86400) / 3600